@@ -0,0 +1,84 @@
+// Command coredeps reports the dependencies of the core packages (logger, logWriter and utils) and exits with
+// a non-zero status if any of them pulls in something other than the standard library or another core
+// package. Optional sinks and integrations live in their own sub packages so that binaries only pay for what
+// they import; run this from the repository root (e.g. in CI) to make sure the core stays that way:
+//
+//	go run ./cmd/coredeps
+//
+// go test ./cmd/coredeps makes the same check, and also fails if the core packages add more to a program than
+// the budget of its TestCoreBinarySize.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+//import path of this repository.
+const root = "github.com/shyamgrover/go-lite-logger"
+
+//packages that make up the core. Every other package of the repository is optional.
+var core = []string{
+	root + "/logger",
+	root + "/logWriter",
+	root + "/utils",
+}
+
+func main() {
+	standard, internal, foreign, err := dependencies()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "coredeps: go list failed:", err)
+		os.Exit(2)
+	}
+
+	fmt.Printf("core packages:            %d\n", len(internal))
+	fmt.Printf("standard library imports: %d\n", len(standard))
+	fmt.Printf("other imports:            %d\n", len(foreign))
+	for _, pkg := range foreign {
+		fmt.Println("  ", pkg)
+	}
+	if len(foreign) > 0 {
+		fmt.Fprintln(os.Stderr, "coredeps: core packages must only depend on the standard library")
+		os.Exit(1)
+	}
+}
+
+//This method lists the packages the core packages are built from with go list, split into the standard library,
+// the core packages themselves and everything else.
+func dependencies() (standard []string, internal []string, foreign []string, err error) {
+	args := append([]string{"list", "-deps", "-f", "{{.ImportPath}} {{.Standard}}"}, core...)
+	out, err := exec.Command("go", args...).Output()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		switch {
+		case fields[1] == "true":
+			standard = append(standard, fields[0])
+		case isCore(fields[0]):
+			internal = append(internal, fields[0])
+		default:
+			foreign = append(foreign, fields[0])
+		}
+	}
+	return standard, internal, foreign, nil
+}
+
+//This method reports whether pkg is one of the core packages.
+func isCore(pkg string) bool {
+	for _, c := range core {
+		if pkg == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestCoreDependencies fails if a core package depends on anything but the standard library and the other core
+// packages.
+func TestCoreDependencies(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	_, internal, foreign, err := dependencies()
+	if err != nil {
+		t.Fatal("go list failed:", err)
+	}
+	if len(internal) != len(core) {
+		t.Errorf("go list found core packages %v, want %v", internal, core)
+	}
+	for _, pkg := range foreign {
+		t.Errorf("core packages depend on %s", pkg)
+	}
+}
+
+//programs built by TestCoreBinarySize: one logging through the core packages, and the same without them.
+var (
+	coreProgram = `package main
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	myLogger, err := logger.New(logger.WithFile(filepath.Join(os.TempDir(), "coredeps.log")),
+		logger.WithLevel(logWriter.InfoLevel))
+	if err != nil {
+		panic(err)
+	}
+	myLogger.Info("started")
+	myLogger.CloseLogger()
+}
+`
+	plainProgram = `package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	file, err := os.Create(filepath.Join(os.TempDir(), "coredeps.log"))
+	if err != nil {
+		panic(err)
+	}
+	file.WriteString("started\n")
+	file.Close()
+}
+`
+)
+
+//maxCoreOverhead is how many bytes the core packages may add to a program. Most of it is standard library
+// packages the core needs; a new dependency with a few packages of its own shows up as a jump of several hundred
+// KiB.
+const maxCoreOverhead = 7 << 20
+
+// TestCoreBinarySize builds a program logging through the core packages and the same program writing the file
+// itself, and fails if the core packages add more than maxCoreOverhead bytes.
+func TestCoreBinarySize(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds two programs")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	core := buildProgram(t, "core", coreProgram)
+	plain := buildProgram(t, "plain", plainProgram)
+	t.Logf("core program: %d bytes, without the core packages: %d bytes, overhead %d bytes of %d allowed", core,
+		plain, core-plain, maxCoreOverhead)
+	if core-plain > maxCoreOverhead {
+		t.Errorf("the core packages add %d bytes to a program, more than the budget of %d", core-plain,
+			maxCoreOverhead)
+	}
+}
+
+//This method builds the program from source and returns the size of the binary. It is built from this
+// directory, so that the core packages resolve to this repository.
+func buildProgram(t *testing.T, name string, source string) int64 {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	if err := ioutil.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	binary := filepath.Join(dir, name)
+	if out, err := exec.Command("go", "build", "-o", binary, path).CombinedOutput(); err != nil {
+		t.Fatalf("building the %s program failed: %v\n%s", name, err, out)
+	}
+	info, err := os.Stat(binary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Size()
+}
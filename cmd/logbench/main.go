@@ -0,0 +1,125 @@
+// Command logbench measures the cost of logging calls: the time and the heap allocations of one entry, from the
+// logging call to the worker writing it to its buffer. Each case logs b.N entries and flushes, so the work of the
+// worker is part of the measurement. The output has the layout of go test -bench:
+//
+//	go run ./cmd/logbench
+//	go run ./cmd/logbench -max-allocs 0 -bench string
+//
+// With -max-allocs it fails when a case allocates more per entry than allowed, which guards the allocation free
+// path of plain string messages and the common field types. BenchmarkLogging and TestAllocations of the logger
+// package do the same under go test. The parallel cases log from GOMAXPROCS goroutines at
+// once through the channel, through the ring of WithRingQueue and through four workers of WithShards; compare
+// them with -cpu to see how they scale:
+//
+//	go run ./cmd/logbench -bench parallel -cpu 8
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+var (
+	bench     = flag.String("bench", "", "run only the cases whose name contains this text")
+	maxAllocs = flag.Int64("max-allocs", -1, "fail if a case allocates more per entry, -1 to never fail")
+	cpu       = flag.Int("cpu", 0, "GOMAXPROCS for the parallel cases, 0 to keep the default")
+)
+
+//benchCase is a way of logging measured by logbench.
+type benchCase struct {
+	name     string
+	log      func(l *logger.Logger) //logs one entry
+	options  []logger.Option        //options of the logger besides its file and level
+	parallel bool                   //log from GOMAXPROCS goroutines
+}
+
+//cases are the logging calls measured, from the cheapest to the most expensive.
+var cases = []benchCase{
+	{name: "string", log: func(l *logger.Logger) { l.Info("request handled") }},
+	{name: "string/filtered", log: func(l *logger.Logger) { l.Debug("request handled") }},
+	{name: "fields"},
+	{name: "fields/per-call", log: func(l *logger.Logger) {
+		l.WithFields(logWriter.Fields{"method": "GET", "status": 200}).Info("request handled")
+	}},
+	{name: "fields/typed", log: func(l *logger.Logger) {
+		l.Infow("request handled", logWriter.String("method", "GET"), logWriter.Int("status", 200))
+	}},
+	{name: "args", log: func(l *logger.Logger) { l.Info("request handled in", 42, "ms") }},
+	{name: "formatted", log: func(l *logger.Logger) { l.Infof("request %s handled in %d ms", "GET /orders", 42) }},
+	{name: "parallel/channel", parallel: true},
+	{name: "parallel/ring", options: []logger.Option{logger.WithRingQueue()}, parallel: true},
+	{name: "parallel/sharded", options: []logger.Option{logger.WithShards(4, logger.ShardRoundRobin)}, parallel: true},
+}
+
+func main() {
+	flag.Parse()
+	dir, err := ioutil.TempDir("", "logbench")
+	if err != nil {
+		fail("%v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if *cpu > 0 {
+		runtime.GOMAXPROCS(*cpu)
+	}
+	failed := false
+	for i, c := range cases {
+		if !strings.Contains(c.name, *bench) {
+			continue
+		}
+		opts := append([]logger.Option{logger.WithFile(filepath.Join(dir, fmt.Sprintf("bench%d.log", i))),
+			logger.WithLevel(logWriter.InfoLevel)}, c.options...)
+		myLogger, err := logger.New(opts...)
+		if err != nil {
+			fail("%v", err)
+		}
+		log := c.log
+		switch {
+		case c.name == "fields":
+			fielded := myLogger.With("method", "GET", "status", 200)
+			log = func(*logger.Logger) { fielded.Info("request handled") }
+		case log == nil:
+			log = func(l *logger.Logger) { l.Info("request handled") }
+		}
+		result := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			if c.parallel {
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						log(myLogger)
+					}
+				})
+			} else {
+				for n := 0; n < b.N; n++ {
+					log(myLogger)
+				}
+			}
+			if err := myLogger.Flush(); err != nil {
+				b.Fatal(err)
+			}
+		})
+		myLogger.CloseLogger()
+		fmt.Printf("%-20s %s\t%s\n", c.name, result.String(), result.MemString())
+		if *maxAllocs >= 0 && result.AllocsPerOp() > *maxAllocs {
+			fmt.Printf("%s: %d allocs per entry, more than %d\n", c.name, result.AllocsPerOp(), *maxAllocs)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+//This method prints the message and exits with a non-zero status.
+func fail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "logbench: "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,89 @@
+// Command logcat renders log files written with logWriter.BinaryFormatter (the "binary" format of config files
+// and LOGGER_FORMAT) as text or JSON lines on stdout. It reads the files named on the command line one after
+// the other, or stdin if there are none:
+//
+//	go run ./cmd/logcat app.log
+//	go run ./cmd/logcat -format json -utc app.log.1 app.log | jq .
+//
+// A record cut off at the end of a file, e.g. by a crash, is reported on stderr after the entries before it; any
+// other record that cannot be decoded ends the file. logcat exits with a non-zero status if a file could not
+// be read completely.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"io"
+	"os"
+)
+
+var (
+	format   = flag.String("format", "text", "output format, text or json")
+	utc      = flag.Bool("utc", false, "write the times in UTC instead of local time")
+	layout   = flag.String("time-layout", "", "layout of the times, the default of the output format if empty")
+	caller   = flag.Bool("caller", true, "write the call site of every entry")
+	sequence = flag.Bool("seq", false, "write the sequence number of every entry")
+)
+
+func main() {
+	flag.Parse()
+	var formatter logWriter.Formatter
+	switch *format {
+	case "text":
+		formatter = logWriter.TextFormatter{TimestampFormat: *layout, UTC: *utc, Caller: *caller, Sequence: *sequence}
+	case "json":
+		formatter = logWriter.JSONFormatter{TimestampFormat: *layout, UTC: *utc, Caller: *caller, Sequence: *sequence}
+	default:
+		fmt.Fprintf(os.Stderr, "logcat: unknown format %q, want text or json\n", *format)
+		os.Exit(2)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	failed := false
+	if flag.NArg() == 0 {
+		failed = !render("stdin", os.Stdin, formatter, out)
+	}
+	for _, path := range flag.Args() {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "logcat:", err)
+			failed = true
+			continue
+		}
+		if !render(path, file, formatter, out) {
+			failed = true
+		}
+		file.Close()
+	}
+	if err := out.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "logcat:", err)
+		failed = true
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+//This method writes the entries read from r to out with the formatter and reports whether all of them could be
+// decoded.
+func render(name string, r io.Reader, formatter logWriter.Formatter, out *bufio.Writer) bool {
+	reader := logWriter.NewBinaryReader(r)
+	for count := 0; ; count++ {
+		entry, err := reader.Read()
+		if err == io.EOF {
+			return true
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "logcat: %s: after %d entries: %v\n", name, count, err)
+			return false
+		}
+		line, err := formatter.Format(entry)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "logcat: %s: entry %d: %v\n", name, count+1, err)
+			continue
+		}
+		out.Write(line)
+	}
+}
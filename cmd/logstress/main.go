@@ -0,0 +1,218 @@
+// Command logstress is a soak test for the logger. It runs many producer goroutines against a logger while
+// injecting random events (flushes, debug windows, the log file disappearing for a while, closing the logger
+// while producers are still logging) and then checks these invariants against the log file:
+//
+//   - every entry is either in the file or counted as dropped in the CloseReport,
+//   - no entry is written twice,
+//   - every line is complete, i.e. no partial or interleaved lines,
+//   - entries of one producer appear in the order they were logged.
+//
+// Each epoch uses a new logger on the same file, so the file also sees the logger being closed and reopened.
+// Run it for longer with more epochs or producers before landing changes to the worker or the channel:
+//
+//	go run ./cmd/logstress -epochs 50 -producers 64 -entries 20000
+//
+// -ring runs the same checks with the entries going through the ring of WithRingQueue instead of the channel.
+// -shards spreads the entries over several workers with ShardByLogger, each producer logging through a logger
+// of its own. -vectored gathers full buffers of 4 KiB into vectored writes.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	epochs    = flag.Int("epochs", 5, "number of loggers opened one after the other on the same file")
+	producers = flag.Int("producers", 16, "producer goroutines per epoch")
+	entries   = flag.Int("entries", 5000, "entries logged by each producer per epoch")
+	retain    = flag.Int("retain", 0, "RetainOnFailure cap in bytes, 0 to discard failed buffers")
+	seed      = flag.Int64("seed", time.Now().UnixNano(), "random seed, printed so that failures can be replayed")
+	dir       = flag.String("dir", "", "directory for the log file, a temporary directory by default")
+	ring      = flag.Bool("ring", false, "hand entries to the worker through the lock-free ring of WithRingQueue")
+	shards    = flag.Int("shards", 1, "workers the entries are spread over, see WithShards")
+	vectored  = flag.Int("vectored", 0, "full buffers written with one vectored write, see WithVectoredWrites")
+)
+
+//the payload of every entry: producer, entry number, padding length and the padding itself.
+var payload = regexp.MustCompile(`p=(\d+) e=(\d+) n=(\d+) pad=(\d+) (x*)$`)
+
+func main() {
+	flag.Parse()
+	random := rand.New(rand.NewSource(*seed))
+	fmt.Printf("seed %d\n", *seed)
+
+	logDir := *dir
+	if len(logDir) == 0 {
+		tempDir, err := ioutil.TempDir("", "logstress")
+		if err != nil {
+			fail("%v", err)
+		}
+		defer os.RemoveAll(tempDir)
+		logDir = tempDir
+	}
+	path := filepath.Join(logDir, "stress.log")
+	os.Remove(path)
+
+	var produced, dropped uint64
+	for epoch := 0; epoch < *epochs; epoch++ {
+		report := runEpoch(epoch, path, random)
+		produced += uint64(*producers * *entries)
+		dropped += report.EntriesDropped
+		fmt.Printf("epoch %d: flushed %d, dropped %d, %d bytes in %v\n",
+			epoch, report.EntriesFlushed, report.EntriesDropped, report.BytesWritten, report.Duration)
+	}
+
+	written, violations := verify(path)
+	for _, v := range violations {
+		fmt.Println("violation:", v)
+	}
+	if written+dropped != produced {
+		violations = append(violations, fmt.Sprintf("produced %d entries but found %d in the file and %d dropped",
+			produced, written, dropped))
+		fmt.Println("violation:", violations[len(violations)-1])
+	}
+	if len(violations) > 0 {
+		fail("%d invariant violations", len(violations))
+	}
+	fmt.Printf("ok: %d entries produced, %d written, %d dropped\n", produced, written, dropped)
+}
+
+//This method runs one epoch: it opens a logger, starts the producers and fires random events until the
+// producers are done or the logger was closed under them, and returns the close report.
+func runEpoch(epoch int, path string, random *rand.Rand) logger.CloseReport {
+	opts := []logger.Option{logger.WithFile(path), logger.WithLevel(logWriter.InfoLevel)}
+	if *ring {
+		opts = append(opts, logger.WithRingQueue())
+	}
+	if *vectored > 1 {
+		opts = append(opts, logger.WithVectoredWrites(*vectored), logger.WithBufferSize(4096))
+	}
+	if *shards > 1 {
+		opts = append(opts, logger.WithShards(*shards, logger.ShardByLogger))
+	}
+	myLogger, err := logger.New(opts...)
+	if err != nil {
+		fail("epoch %d: %v", epoch, err)
+	}
+	myLogger.RetainOnFailure(*retain)
+
+	var wg sync.WaitGroup
+	var finished int32
+	for p := 0; p < *producers; p++ {
+		wg.Add(1)
+		go func(p int, padding int) {
+			defer wg.Done()
+			pad := strings.Repeat("x", padding)
+			producer := myLogger.WithFields(nil) //a logger of its own, whose entries stay on one worker
+			for n := 0; n < *entries; n++ {
+				producer.Infof("p=%d e=%d n=%d pad=%d %s", p, epoch, n, padding, pad)
+			}
+		}(p, random.Intn(200))
+	}
+	go func() {
+		wg.Wait()
+		atomic.StoreInt32(&finished, 1)
+	}()
+
+	closeEarly := random.Intn(3) == 0
+	for atomic.LoadInt32(&finished) == 0 {
+		time.Sleep(time.Duration(random.Intn(5)) * time.Millisecond)
+		switch random.Intn(10) {
+		case 0, 1, 2:
+			myLogger.SelfCheck()
+		case 3:
+			myLogger.EnableDebugFor(time.Duration(random.Intn(10)) * time.Millisecond)
+		case 4:
+			injectMissingFile(path, time.Duration(random.Intn(5))*time.Millisecond)
+		case 5:
+			if closeEarly {
+				myLogger.CloseLogger()
+				wg.Wait()
+				return myLogger.CloseLogger()
+			}
+		}
+	}
+	return myLogger.CloseLogger()
+}
+
+//This method moves the log file away for the given time, so that flushes in between fail.
+func injectMissingFile(path string, outage time.Duration) {
+	moved := path + ".away"
+	if err := os.Rename(path, moved); err != nil {
+		return
+	}
+	time.Sleep(outage)
+	if err := os.Rename(moved, path); err != nil {
+		fail("restoring log file: %v", err)
+	}
+}
+
+//This method reads the log file back and checks every line. It returns the number of entries found and the
+// invariant violations.
+func verify(path string) (uint64, []string) {
+	file, err := os.Open(path)
+	if err != nil {
+		fail("%v", err)
+	}
+	defer file.Close()
+
+	var written uint64
+	var violations []string
+	last := make(map[string]int)
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.Contains(text, "go-lite-logger self-check") {
+			continue
+		}
+		match := payload.FindStringSubmatch(text)
+		if match == nil || !strings.HasPrefix(text, "[") {
+			violations = append(violations, fmt.Sprintf("line %d is malformed: %q", line, text))
+			continue
+		}
+		if padding, _ := strconv.Atoi(match[4]); padding != len(match[5]) {
+			violations = append(violations, fmt.Sprintf("line %d is truncated: %q", line, text))
+			continue
+		}
+		key := match[0][:strings.Index(match[0], " pad=")]
+		if seen[key] {
+			violations = append(violations, fmt.Sprintf("line %d is a duplicate: %q", line, text))
+			continue
+		}
+		seen[key] = true
+		written++
+
+		producer := match[1] + "/" + match[2]
+		n, _ := strconv.Atoi(match[3])
+		if previous, ok := last[producer]; ok && n <= previous {
+			violations = append(violations, fmt.Sprintf("line %d is out of order: %q", line, text))
+		}
+		last[producer] = n
+	}
+	if err = scanner.Err(); err != nil {
+		violations = append(violations, err.Error())
+	}
+	return written, violations
+}
+
+//This method prints the message and exits with a non-zero status.
+func fail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "logstress: "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,57 @@
+// Package compat keeps the v1 CreateLogger call working on top of the options API, so that programs can move
+// to logger.New one call site at a time. A v1 caller changes only the import path:
+//
+//	myLogger, err := compat.CreateLogger(compat.InfoLevel, "app.log", "logs/", onError)
+//
+// The returned logger is a regular *logger.Logger, so new code in the same program can use options, sinks and
+// formatters on it. The package moves unchanged to the v2 module, where it maps the v1 level numbers onto the
+// renumbered levels, see docs/v2.md.
+//
+// Deprecated: use logger.New with options. The package is removed in v3.
+package compat
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+)
+
+// Level is a level in the v1 numbering, as stored or compared as a raw number by v1 callers. FromV1 converts it.
+type Level uint32
+
+// The levels of v1, with their v1 numbers.
+const (
+	ErrorLevel Level = iota
+	WarnLevel
+	InfoLevel
+	DebugLevel
+	TraceLevel
+	FatalLevel
+	PanicLevel
+)
+
+//levels maps the v1 numbers to the levels of the logger.
+var levels = [...]logWriter.Level{
+	ErrorLevel: logWriter.ErrorLevel,
+	WarnLevel:  logWriter.WarnLevel,
+	InfoLevel:  logWriter.InfoLevel,
+	DebugLevel: logWriter.DebugLevel,
+	TraceLevel: logWriter.TraceLevel,
+	FatalLevel: logWriter.FatalLevel,
+	PanicLevel: logWriter.PanicLevel,
+}
+
+// FromV1 converts a v1 level number to the level of the logger. Numbers past PanicLevel, such as those of levels
+// registered with logWriter.RegisterLevel, are passed on unchanged.
+func FromV1(level Level) logWriter.Level {
+	if int(level) < len(levels) {
+		return levels[level]
+	}
+	return logWriter.Level(level)
+}
+
+// CreateLogger creates a logger the way v1 did: the file path is logDir and fileName concatenated, so logDir
+// needs a trailing separator, and logDir is created if it does not exist. errorCallback is called when writing
+// to the log file fails.
+func CreateLogger(logLevel Level, fileName string, logDir string, errorCallback func()) (*logger.Logger, error) {
+	return logger.CreateLogger(FromV1(logLevel), fileName, logDir, errorCallback)
+}
@@ -0,0 +1,36 @@
+package compat_test
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/compat"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+func ExampleCreateLogger() {
+	dir, err := ioutil.TempDir("", "example")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	//the v1 call, with the trailing separator v1 callers pass
+	myLogger, err := compat.CreateLogger(compat.WarnLevel, "app.log", dir+string(os.PathSeparator), func() {})
+	if err != nil {
+		panic(err)
+	}
+	myLogger.Info("left out")
+	myLogger.Warn("disk almost full")
+	myLogger.CloseLogger()
+
+	data, err := ioutil.ReadFile(dir + string(os.PathSeparator) + "app.log")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(strings.Count(string(data), "\n"), strings.Contains(string(data), "[WARN]  "))
+	fmt.Println(compat.FromV1(compat.DebugLevel))
+	// Output:
+	// 1 true
+	// debug
+}
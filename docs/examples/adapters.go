@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/integrations/expvarlog"
+	"github.com/shyamgrover/go-lite-logger/integrations/httplog"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"regexp"
+)
+
+//writerExample routes the output of a standard library logger and of a command through WriterLevel.
+func writerExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithFormatter(logWriter.TextFormatter{}))
+	if err != nil {
+		return err
+	}
+	stdLogger := log.New(myLogger.WriterLevel(logWriter.ErrorLevel), "http: ", 0)
+	stdLogger.Printf("TLS handshake error from %s", "10.0.0.7:5123")
+
+	cmd := exec.Command("sh", "-c", "echo first >&2; printf 'second\\nthird' >&2")
+	cmd.Stderr = myLogger.WithField("cmd", "backup").WriterLevel(logWriter.WarnLevel)
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("running command: %v", err)
+	}
+	myLogger.WriterLevel(logWriter.DebugLevel).Write([]byte("below the level\n"))
+	myLogger.CloseLogger()
+	return expectFile(dir+"app.log", []string{
+		"[ERROR] ", " http: TLS handshake error from 10.0.0.7:5123\n",
+		" first cmd=backup\n", " second cmd=backup\n",
+	}, []string{"third", "below the level"})
+}
+
+//httpExample logs requests with the net/http middleware; server errors are logged at Error level.
+func httpExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithFormatter(logWriter.TextFormatter{}))
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	})
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(httplog.New(myLogger, httplog.Config{})(mux))
+	for _, path := range []string{"/orders", "/fail", "/missing"} {
+		response, err := http.Get(server.URL + path)
+		if err != nil {
+			server.Close()
+			return err
+		}
+		response.Body.Close()
+	}
+	server.Close()
+	myLogger.CloseLogger()
+	return expectFile(dir+"app.log", []string{
+		"[INFO] ", " GET /orders 200 bytes=2 latency_ms=", " method=GET path=/orders remote_addr=127.0.0.1:",
+		"[ERROR] ", " GET /fail 500 bytes=5 ", " status=500\n",
+		"[WARN]  ", " GET /missing 404 ",
+	}, nil)
+}
+
+//accessLogExample writes the requests of the net/http middleware as Combined Log Format lines and checks that
+// a quote in a header cannot forge a field.
+func accessLogExample(dir string) error {
+	pattern, err := logWriter.ParsePattern("%m")
+	if err != nil {
+		return err
+	}
+	myLogger, err := logger.New(logger.WithFile(dir+"access.log"), logger.WithFormatter(pattern))
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	})
+	server := httptest.NewServer(httplog.New(myLogger, httplog.Config{Format: "combined"})(mux))
+	request, err := http.NewRequest(http.MethodGet, server.URL+"/orders?page=2", nil)
+	if err != nil {
+		server.Close()
+		return err
+	}
+	request.SetBasicAuth("alice", "secret")
+	request.Header.Set("Referer", "https://shop.example/")
+	request.Header.Set("User-Agent", `curl "7.0"`)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		server.Close()
+		return err
+	}
+	response.Body.Close()
+	server.Close()
+	myLogger.CloseLogger()
+
+	data, err := ioutil.ReadFile(dir + "access.log")
+	if err != nil {
+		return err
+	}
+	line := regexp.MustCompile(`^127\.0\.0\.1 - alice \[\d\d/\w{3}/\d{4}:\d\d:\d\d:\d\d [-+]\d{4}\] ` +
+		`"GET /orders\?page=2 HTTP/1\.1" 200 2 "https://shop\.example/" "curl \\"7\.0\\""\n$`)
+	if !line.Match(data) {
+		return fmt.Errorf("unexpected access log %q", data)
+	}
+	return nil
+}
+
+//expvarExample publishes the totals of a logger and reads them back from /debug/vars.
+func expvarExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir + "app.log"))
+	if err != nil {
+		return err
+	}
+	defer myLogger.CloseLogger()
+	expvarlog.Publish("example", myLogger)
+	myLogger.Info("published")
+	if err = myLogger.Flush(); err != nil {
+		return err
+	}
+
+	recorder := httptest.NewRecorder()
+	expvar.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	var vars struct {
+		Loggers map[string]struct {
+			Entries map[string]uint64 `json:"entries"`
+			Written uint64            `json:"entries_written"`
+			Flushes uint64            `json:"flushes"`
+		} `json:"golitelogger"`
+	}
+	if err = json.Unmarshal(recorder.Body.Bytes(), &vars); err != nil {
+		return err
+	}
+	published := vars.Loggers["example"]
+	if published.Entries["info"] != 1 || published.Written != 1 || published.Flushes != 1 {
+		return fmt.Errorf("unexpected published totals %+v", published)
+	}
+	return nil
+}
@@ -0,0 +1,800 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//basicExample creates a logger at Info level, logs at every level and closes it.
+func basicExample(dir string) error {
+	myLogger, err := logger.CreateLogger(logWriter.InfoLevel, "app.log", dir, func() {})
+	if err != nil {
+		return err
+	}
+	myLogger.Debug("not logged: below the logger level")
+	myLogger.Info("service started")
+	myLogger.Warnf("disk usage at %d%%", 91)
+	myLogger.Errorfunc(func() string { return "computed only when loggable" })
+	myLogger.SetStatus(false)
+	myLogger.Error("not logged: logging is off")
+	myLogger.CloseLogger()
+
+	return expectFile(dir+"app.log",
+		[]string{"[INFO]", "service started", "[WARN]", "disk usage at", "computed only when loggable"},
+		[]string{"not logged"})
+}
+
+//closeReportExample shows the report returned by CloseLogger.
+func closeReportExample(dir string) error {
+	myLogger, err := logger.CreateLogger(logWriter.InfoLevel, "app.log", dir, func() {})
+	if err != nil {
+		return err
+	}
+	for i := 0; i < 100; i++ {
+		myLogger.Info("entry", i)
+	}
+	report := myLogger.CloseLogger()
+	myLogger.Info("dropped: the logger is closed")
+	if report.Err() != nil || report.EntriesFlushed != 100 || report.EntriesDropped != 0 {
+		return fmt.Errorf("unexpected close report %+v", report)
+	}
+	return nil
+}
+
+//optionsExample creates a logger with New and options; a short flush interval gets entries to the file
+// without closing the logger.
+func optionsExample(dir string) error {
+	myLogger, err := logger.New(
+		logger.WithFile("app.log"),
+		logger.WithDir(dir+"nested"),
+		logger.WithLevel(logWriter.WarnLevel),
+		logger.WithBufferSize(4096),
+		logger.WithChannelSize(64),
+		logger.WithFlushInterval(20*time.Millisecond),
+	)
+	if err != nil {
+		return err
+	}
+	defer myLogger.CloseLogger()
+	myLogger.Info("not logged: below the logger level")
+	myLogger.Warn("flushed by the timer")
+	time.Sleep(200 * time.Millisecond)
+	return expectFile(dir+"nested/app.log", []string{"[WARN]", "flushed by the timer"}, []string{"not logged"})
+}
+
+//permissionsExample creates a private log directory and file and refuses an existing world-readable file.
+func permissionsExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"private/app.log"), logger.WithFileMode(0600),
+		logger.WithDirMode(0700), logger.WithPrivateFiles(), logger.WithOwner(os.Getuid(), -1))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("started")
+	myLogger.CloseLogger()
+	for path, want := range map[string]os.FileMode{dir + "private": 0700, dir + "private/app.log": 0600} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.Mode().Perm() != want {
+			return fmt.Errorf("%s has mode %v, want %v", path, info.Mode().Perm(), want)
+		}
+	}
+
+	if err = ioutil.WriteFile(dir+"shared.log", nil, 0644); err != nil {
+		return err
+	}
+	if err = os.Chmod(dir+"shared.log", 0644); err != nil {
+		return err
+	}
+	if _, err = logger.New(logger.WithFile(dir+"shared.log"), logger.WithPrivateFiles()); err == nil {
+		return fmt.Errorf("world-readable file accepted")
+	}
+	if _, err = logger.New(logger.WithFile(dir+"shared.log"), logger.WithOwner(os.Getuid()+1, -1)); err == nil {
+		return fmt.Errorf("file of another user accepted")
+	}
+	return nil
+}
+
+//fileLockExample lets two loggers, standing in for two processes, share one file and checks that no line was
+// torn apart.
+func fileLockExample(dir string) error {
+	var loggers []*logger.Logger
+	for i := 0; i < 2; i++ {
+		myLogger, err := logger.New(logger.WithFile(dir+"shared.log"), logger.WithFileLock(), logger.WithBufferSize(512))
+		if err != nil {
+			return err
+		}
+		loggers = append(loggers, myLogger)
+	}
+	var wg sync.WaitGroup
+	for i, myLogger := range loggers {
+		wg.Add(1)
+		go func(process int, myLogger *logger.Logger) {
+			defer wg.Done()
+			for n := 0; n < 500; n++ {
+				myLogger.Infof("process %d entry %d %s", process, n, strings.Repeat("x", 40))
+			}
+			myLogger.CloseLogger()
+		}(i, myLogger)
+	}
+	wg.Wait()
+	data, err := ioutil.ReadFile(dir + "shared.log")
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "[INFO]") || !strings.HasSuffix(line, strings.Repeat("x", 40)) {
+			return fmt.Errorf("torn line %q", line)
+		}
+	}
+	if len(lines) != 1000 {
+		return fmt.Errorf("%d lines, want 1000", len(lines))
+	}
+	return nil
+}
+
+//samplingExample logs the same message in a loop and keeps the first two and every fifth after them, then checks
+// where and when the summary of an ended interval is written.
+func samplingExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"),
+		logger.WithSampling(logWriter.InfoLevel, logger.Sampling{Interval: time.Minute, First: 2, Thereafter: 5}))
+	if err != nil {
+		return err
+	}
+	for i := 1; i <= 12; i++ {
+		myLogger.Infof("retrying connection, attempt %d", i)
+	}
+	myLogger.Warn("not sampled")
+	sampled := myLogger.Stats().EntriesSampled
+	myLogger.CloseLogger()
+	if sampled != 8 {
+		return fmt.Errorf("%d entries sampled, want 8", sampled)
+	}
+	err = expectFile(dir+"app.log", []string{"attempt 1\n", "attempt 2\n", "attempt 7\n", "attempt 12\n", "not sampled",
+		"sampled: dropped 8 similar entries: retrying connection, attempt %d\n"}, []string{"attempt 3\n", "???:0"})
+	if err != nil {
+		return err
+	}
+
+	myLogger, err = logger.New(logger.WithFile(dir+"interval.log"),
+		logger.WithSampling(logWriter.InfoLevel, logger.Sampling{Interval: 20 * time.Millisecond, First: 1}))
+	if err != nil {
+		return err
+	}
+	for i := 0; i < 3; i++ {
+		myLogger.Info("polling")
+	}
+	time.Sleep(30 * time.Millisecond)
+	myLogger.Info("interval over")
+	myLogger.CloseLogger()
+	data, err := ioutil.ReadFile(dir + "interval.log")
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], "basic.go:") || !strings.Contains(lines[1], "dropped 2") {
+		return fmt.Errorf("summary not attributed to the sampled call site:\n%s", data)
+	}
+	if lines[1][8:34] > lines[2][8:34] {
+		return fmt.Errorf("summary written after the entry that ended the interval:\n%s", data)
+	}
+	return nil
+}
+
+//dedupExample collapses an error repeated in a loop into two lines.
+func dedupExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithDeduplication(time.Minute))
+	if err != nil {
+		return err
+	}
+	for i := 0; i < 42; i++ {
+		myLogger.Error("connection refused")
+	}
+	myLogger.Info("giving up")
+	myLogger.Error("connection refused")
+	myLogger.CloseLogger()
+	data, err := ioutil.ReadFile(dir + "app.log")
+	if err != nil {
+		return err
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 4 {
+		return fmt.Errorf("%d lines:\n%s", lines, data)
+	}
+	return expectFile(dir+"app.log", []string{"connection refused\n", "connection refused (repeated 41 times)\n",
+		"giving up\n"}, nil)
+}
+
+//rateLimitExample logs a notice once per key and an error at most once per interval from a loop.
+func rateLimitExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir + "app.log"))
+	if err != nil {
+		return err
+	}
+	for i := 0; i < 100; i++ {
+		myLogger.InfoOnce("legacy-api", "legacy API used, it will be removed in v2")
+		myLogger.ErrorEvery(time.Hour, "poll failed, attempt", i)
+	}
+	myLogger.ErrorEvery(time.Hour, "other call site")
+	myLogger.CloseLogger()
+	data, err := ioutil.ReadFile(dir + "app.log")
+	if err != nil {
+		return err
+	}
+	if strings.Count(string(data), "legacy API used") != 1 || strings.Count(string(data), "poll failed") != 1 {
+		return fmt.Errorf("not rate limited:\n%s", data)
+	}
+	return expectFile(dir+"app.log", []string{"poll failed, attempt 0\n", "other call site\n"}, nil)
+}
+
+//traceOnErrorExample runs at Info level and gets the latest debug entries written when an error occurs.
+func traceOnErrorExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithTraceOnError(3))
+	if err != nil {
+		return err
+	}
+	for i := 1; i <= 5; i++ {
+		myLogger.Debugf("step %d", i)
+	}
+	myLogger.Info("still fine")
+	myLogger.Error("step 6 failed")
+	myLogger.Error("no context left for this one")
+	myLogger.CloseLogger()
+	data, err := ioutil.ReadFile(dir + "app.log")
+	if err != nil {
+		return err
+	}
+	text := string(data)
+	if strings.Index(text, "step 3\n") > strings.Index(text, "step 5\n") ||
+		strings.Index(text, "step 5\n") > strings.Index(text, "step 6 failed") || strings.Count(text, "[DEBUG]") != 3 {
+		return fmt.Errorf("unexpected log:\n%s", text)
+	}
+	return expectFile(dir+"app.log", []string{"still fine"}, []string{"step 1\n", "step 2\n"})
+}
+
+//gatedFormatter is a formatter that holds the worker until gate is closed, like a stalled disk, and then
+// formats with next, or as text if next is nil.
+type gatedFormatter struct {
+	gate chan struct{}
+	next logWriter.Formatter
+}
+
+func (f gatedFormatter) Format(entry logWriter.Entry) ([]byte, error) {
+	<-f.gate
+	if f.next == nil {
+		return logWriter.TextFormatter{}.Format(entry)
+	}
+	return f.next.Format(entry)
+}
+
+//overflowExample logs faster than a stalled worker writes: DropNewest keeps the oldest entries, DropOldest the
+// most recent ones, and neither makes the logging calls wait.
+func overflowExample(dir string) error {
+	for _, policy := range []logger.OverflowPolicy{logger.DropNewest, logger.DropOldest} {
+		gate := make(chan struct{})
+		myLogger, err := logger.New(
+			logger.WithFile(dir+policy.String()+".log"),
+			logger.WithChannelSize(4),
+			logger.WithOverflowPolicy(policy),
+			logger.WithFormatter(gatedFormatter{gate: gate}),
+		)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < 20; i++ {
+			myLogger.Info("entry", i)
+		}
+		close(gate)
+		if report := myLogger.CloseLogger(); report.EntriesDropped < 10 {
+			return fmt.Errorf("unexpected close report %+v", report)
+		}
+		wanted, unwanted := "entry 2\n", "entry 19\n"
+		if policy == logger.DropOldest {
+			wanted, unwanted = unwanted, wanted
+		}
+		if err = expectFile(dir+policy.String()+".log", []string{wanted}, []string{unwanted, "entry 10\n"}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//ringQueueExample logs from many goroutines through the lock-free ring of WithRingQueue: a small ring blocks the
+// callers until the worker makes room, so no entry is lost, and DropNewest drops entries while a stalled worker
+// leaves the ring full.
+func ringQueueExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"ring.log"), logger.WithChannelSize(4), logger.WithRingQueue())
+	if err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				myLogger.Info("goroutine", g, "entry", i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	if report := myLogger.CloseLogger(); report.EntriesFlushed != 800 || report.EntriesDropped != 0 {
+		return fmt.Errorf("unexpected close report %+v", report)
+	}
+	if err = expectFile(dir+"ring.log", []string{"goroutine 0 entry 0\n", "goroutine 7 entry 99\n"}, nil); err != nil {
+		return err
+	}
+
+	gate := make(chan struct{})
+	myLogger, err = logger.New(logger.WithFile(dir+"ring_dropped.log"), logger.WithChannelSize(4),
+		logger.WithRingQueue(), logger.WithOverflowPolicy(logger.DropNewest),
+		logger.WithFormatter(gatedFormatter{gate: gate}))
+	if err != nil {
+		return err
+	}
+	for i := 0; i < 20; i++ {
+		myLogger.Info("entry", i)
+	}
+	if depth := myLogger.Stats().QueueDepth; depth == 0 || depth > 4 {
+		return fmt.Errorf("unexpected queue depth %d", depth)
+	}
+	close(gate)
+	if report := myLogger.CloseLogger(); report.EntriesDropped < 10 {
+		return fmt.Errorf("unexpected close report %+v", report)
+	}
+
+	if _, err = logger.New(logger.WithFile(dir+"ring.log"), logger.WithRingQueue(),
+		logger.WithOverflowPolicy(logger.DropOldest)); err == nil {
+		return errors.New("DropOldest was accepted with the ring queue")
+	}
+	return nil
+}
+
+//synchronousExample finds every entry in the file as soon as the logging call returned, without flushing.
+func synchronousExample(dir string) error {
+	hook := &deployHook{}
+	myLogger, err := logger.New(logger.WithFile(dir+"sync.log"), logger.WithSynchronous(), logger.WithHook(hook))
+	if err != nil {
+		return err
+	}
+	for i := 0; i < 3; i++ {
+		myLogger.Info("step", i)
+		if err = expectFile(dir+"sync.log", []string{fmt.Sprintf("step %d deploy=canary\n", i)}, nil); err != nil {
+			return err
+		}
+	}
+	if stats := myLogger.Stats(); stats.QueueDepth != 0 || hook.fired != 3 {
+		return fmt.Errorf("unexpected stats %+v after %d hooks", stats, hook.fired)
+	}
+	if report := myLogger.CloseLogger(); report.Err() != nil || report.EntriesFlushed != 3 {
+		return fmt.Errorf("unexpected close report %+v", report)
+	}
+	if _, err = logger.New(logger.WithFile(dir+"sync.log"), logger.WithSynchronous(), logger.WithRingQueue()); err == nil {
+		return errors.New("WithSynchronous was accepted with the ring queue")
+	}
+	return nil
+}
+
+//shardsExample logs from several goroutines through four workers, first sharing one file and then writing a
+// file each.
+func shardsExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"sharded.log"), logger.WithShards(4, logger.ShardRoundRobin),
+		logger.WithSequence())
+	if err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				myLogger.Info("goroutine", g, "entry", i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err = myLogger.Flush(); err != nil {
+		return err
+	}
+	if err = expectFile(dir+"sharded.log", []string{"goroutine 0 entry 0 seq=", "goroutine 7 entry 99 seq=", " seq=800\n"},
+		nil); err != nil {
+		return err
+	}
+	if report := myLogger.CloseLogger(); report.EntriesFlushed != 800 || report.EntriesDropped != 0 {
+		return fmt.Errorf("unexpected close report %+v", report)
+	}
+
+	myLogger, err = logger.New(logger.WithFile(dir+"shard.log"), logger.WithShards(3, logger.ShardByLogger),
+		logger.WithShardFiles())
+	if err != nil {
+		return err
+	}
+	for i := 0; i < 3; i++ {
+		myLogger.With("request", i).Info("handled")
+	}
+	if report := myLogger.CloseLogger(); report.EntriesFlushed != 3 || len(report.SinkErrors) > 0 {
+		return fmt.Errorf("unexpected close report %+v", report)
+	}
+	for _, name := range []string{"shard.log", "shard.1.log", "shard.2.log"} {
+		if err = expectFile(dir+name, []string{"handled request="}, nil); err != nil {
+			return err
+		}
+	}
+	if _, err = logger.ParseShardStrategy("hash"); err == nil {
+		return errors.New("unknown shard strategy accepted")
+	}
+	return nil
+}
+
+//defaultExample logs through the package-level functions, first with the standard library fallback and then
+// with a default logger.
+func defaultExample(dir string) error {
+	var fallback bytes.Buffer
+	log.SetOutput(&fallback)
+	defer log.SetOutput(os.Stderr)
+	logger.Info("starting up")
+	logger.Debug("discarded without a default logger")
+
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithLevel(logWriter.DebugLevel))
+	if err != nil {
+		return err
+	}
+	logger.SetDefault(myLogger)
+	defer logger.SetDefault(nil)
+	logger.Infof("listening on %s", ":8080")
+	logger.Debug("config loaded")
+	logger.FromContext(context.Background()).Warn("from the context")
+	myLogger.CloseLogger()
+
+	if !strings.Contains(fallback.String(), "[INFO]  starting up\n") || strings.Contains(fallback.String(), "discarded") {
+		return fmt.Errorf("unexpected fallback output %q", fallback.String())
+	}
+	return expectFile(dir+"app.log", []string{"listening on :8080", "[DEBUG] ", "config loaded", "from the context"},
+		[]string{"starting up"})
+}
+
+//flushExample forces entries to the file with Flush and Sync while the logger stays open.
+func flushExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithFlushInterval(time.Hour))
+	if err != nil {
+		return err
+	}
+	defer myLogger.CloseLogger()
+	myLogger.Info("before flush")
+	if err = myLogger.Flush(); err != nil {
+		return err
+	}
+	if err = expectFile(dir+"app.log", []string{"before flush"}, nil); err != nil {
+		return err
+	}
+	myLogger.Warn("before sync")
+	if err = myLogger.Sync(); err != nil {
+		return err
+	}
+	return expectFile(dir+"app.log", []string{"before sync"}, nil)
+}
+
+//statsExample reads the running totals of a logger while it is in use.
+func statsExample(dir string) error {
+	var slowest time.Duration
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithDeliveryHook(func(d logWriter.Delivery) {
+		if d.Duration > slowest {
+			slowest = d.Duration
+		}
+	}))
+	if err != nil {
+		return err
+	}
+	defer myLogger.CloseLogger()
+	for i := 0; i < 10; i++ {
+		myLogger.Info("entry", i)
+	}
+	myLogger.Warn("warning")
+	if err = myLogger.Flush(); err != nil {
+		return err
+	}
+	stats := myLogger.Stats()
+	if stats.EntriesEnqueued != 11 || stats.EntriesWritten != 11 || stats.EntriesDropped != 0 ||
+		stats.BytesFlushed == 0 || stats.LastFlush.IsZero() || stats.QueueDepth != 0 || len(stats.Failures) != 0 {
+		return fmt.Errorf("unexpected stats %+v", stats)
+	}
+	if stats.EntriesByLevel[logWriter.InfoLevel] != 10 || stats.EntriesByLevel[logWriter.WarnLevel] != 1 || slowest <= 0 {
+		return fmt.Errorf("unexpected levels %v or write duration %v", stats.EntriesByLevel, slowest)
+	}
+	return nil
+}
+
+//pickyFormatter is a text formatter that fails for entries whose message contains "bad".
+type pickyFormatter struct{}
+
+func (pickyFormatter) Format(entry logWriter.Entry) ([]byte, error) {
+	if strings.Contains(entry.Message(), "bad") {
+		return nil, errors.New("unsupported message")
+	}
+	return logWriter.TextFormatter{}.Format(entry)
+}
+
+//errorHandlerExample tells a lost entry apart from a removed log file with an error handler.
+func errorHandlerExample(dir string) error {
+	var lock sync.Mutex
+	var lost []string
+	var missing bool
+	myLogger, err := logger.New(
+		logger.WithFile(dir+"app.log"),
+		logger.WithFormatter(pickyFormatter{}),
+		logger.WithErrorHandler(func(err error, op string, entry *logWriter.Entry) {
+			lock.Lock()
+			defer lock.Unlock()
+			switch {
+			case op == logWriter.OpFormat && entry != nil:
+				lost = append(lost, entry.Message())
+			case op == logWriter.OpWrite && errors.Is(err, logWriter.ErrFileMissing):
+				missing = true
+			}
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	myLogger.Info("a bad entry")
+	myLogger.Info("a good entry")
+	myLogger.Flush()
+	os.Remove(dir + "app.log")
+	myLogger.Info("written after the file was removed")
+	myLogger.CloseLogger()
+
+	lock.Lock()
+	defer lock.Unlock()
+	if len(lost) != 1 || !strings.Contains(lost[0], "a bad entry") || !missing {
+		return fmt.Errorf("unexpected failures: lost %q, missing file %v", lost, missing)
+	}
+	return nil
+}
+
+//errorsExample consumes the logger's failures from its error channel in a goroutine of its own.
+func errorsExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithFormatter(pickyFormatter{}))
+	if err != nil {
+		return err
+	}
+	failures := make(chan []string)
+	go func() {
+		var ops []string
+		for err := range myLogger.Errors() {
+			var opErr *logger.OpError
+			if errors.As(err, &opErr) && opErr.Entry != nil {
+				ops = append(ops, opErr.Op+" "+opErr.Entry.Message())
+			}
+		}
+		failures <- ops
+	}()
+	myLogger.Info("a bad entry")
+	myLogger.Info("a good entry")
+	myLogger.CloseLogger()
+	if ops := <-failures; len(ops) != 1 || !strings.HasPrefix(ops[0], "format a bad entry") {
+		return fmt.Errorf("unexpected failures %q", ops)
+	}
+	return nil
+}
+
+//retryExample retries writes to /dev/full, which always fail with "no space left on device", before the
+// failure is reported. It is skipped where there is no /dev/full.
+func retryExample(dir string) error {
+	if _, err := os.Stat("/dev/full"); err != nil {
+		return nil
+	}
+	myLogger, err := logger.New(logger.WithFile("/dev/full"), logger.WithRetry(2, 10*time.Millisecond))
+	if err != nil {
+		return err
+	}
+	defer myLogger.CloseLogger()
+	myLogger.Info("never written")
+	start := time.Now()
+	if err = myLogger.Flush(); err == nil || time.Since(start) < 30*time.Millisecond {
+		return fmt.Errorf("unexpected flush result %v after %v", err, time.Since(start))
+	}
+	return nil
+}
+
+//fsyncExample flushes an error entry at once and reports the syncs /dev/null refuses, where it exists.
+func fsyncExample(dir string) error {
+	delivered := make(chan logWriter.Delivery, 1)
+	myLogger, err := logger.New(logger.WithFile(dir+"fsync.log"), logger.WithSyncPolicy(logWriter.SyncOnError),
+		logger.WithDeliveryHook(func(delivery logWriter.Delivery) { delivered <- delivery }))
+	if err != nil {
+		return err
+	}
+	defer myLogger.CloseLogger()
+	myLogger.Info("routine")
+	myLogger.Error("payment failed")
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		return errors.New("the error entry was not flushed")
+	}
+	if err = expectFile(dir+"fsync.log", []string{"routine", "payment failed"}, nil); err != nil {
+		return err
+	}
+
+	if _, err = os.Stat("/dev/null"); err != nil {
+		return nil
+	}
+	var ops []string
+	myLogger, err = logger.New(logger.WithFile("/dev/null"), logger.WithSyncPolicy(logWriter.SyncEveryFlush),
+		logger.WithErrorHandler(func(err error, op string, entry *logWriter.Entry) { ops = append(ops, op) }))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("discarded")
+	myLogger.Flush()
+	myLogger.CloseLogger()
+	if len(ops) == 0 || ops[0] != logWriter.OpSync {
+		return fmt.Errorf("unexpected failures %q", ops)
+	}
+	return nil
+}
+
+//fallbackExample writes to /dev/full, which always fails, and finds the entries in the fallback file instead.
+// It is skipped where there is no /dev/full.
+func fallbackExample(dir string) error {
+	if _, err := os.Stat("/dev/full"); err != nil {
+		return nil
+	}
+	myLogger, err := logger.New(logger.WithFile("/dev/full"), logger.WithFallbackFile(dir+"fallback.log", 1))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("kept by the fallback")
+	if err = myLogger.Flush(); err == nil {
+		return fmt.Errorf("writing to /dev/full did not fail")
+	}
+	if report := myLogger.CloseLogger(); report.EntriesDropped != 0 {
+		return fmt.Errorf("unexpected close report %+v", report)
+	}
+	return expectFile(dir+"fallback.log", []string{"[INFO]", "kept by the fallback"}, nil)
+}
+
+//diskFullExample pauses logging while /dev/full reports a full disk: the buffered entry is kept for a later
+// flush and entries logged meanwhile are dropped. It is skipped where there is no /dev/full.
+func diskFullExample(dir string) error {
+	if _, err := os.Stat("/dev/full"); err != nil {
+		return nil
+	}
+	myLogger, err := logger.New(logger.WithFile("/dev/full"), logger.WithDiskFullPolicy(logWriter.DiskFullPause))
+	if err != nil {
+		return err
+	}
+	defer myLogger.CloseLogger()
+	myLogger.Info("kept in the buffer")
+	if err = myLogger.Flush(); !logWriter.IsDiskFull(err) {
+		return fmt.Errorf("unexpected flush error %v", err)
+	}
+	myLogger.Info("dropped while paused")
+	myLogger.Flush()
+	if stats := myLogger.Stats(); stats.EntriesDropped != 1 {
+		return fmt.Errorf("unexpected stats %+v", stats)
+	}
+	return nil
+}
+
+//largeEntryExample logs a payload a hundred times larger than the buffer between two small entries.
+func largeEntryExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithBufferSize(1024))
+	if err != nil {
+		return err
+	}
+	payload := strings.Repeat("0123456789", 10240)
+	myLogger.Info("before")
+	myLogger.Info("payload", payload)
+	myLogger.Info("after")
+	if report := myLogger.CloseLogger(); report.Err() != nil || report.EntriesFlushed != 3 {
+		return fmt.Errorf("unexpected close report %+v", report)
+	}
+	data, err := ioutil.ReadFile(dir + "app.log")
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], "before") || !strings.HasSuffix(lines[1], "payload "+payload) ||
+		!strings.HasSuffix(lines[2], "after") {
+		return fmt.Errorf("unexpected file contents of %d bytes", len(data))
+	}
+	return nil
+}
+
+//vectoredWritesExample gathers full buffers of two entries each and writes three of them with one write.
+func vectoredWritesExample(dir string) error {
+	var deliveries int32
+	myLogger, err := logger.New(logger.WithFile(dir+"vectored.log"), logger.WithBufferSize(512),
+		logger.WithVectoredWrites(4),
+		logger.WithDeliveryHook(func(logWriter.Delivery) { atomic.AddInt32(&deliveries, 1) }))
+	if err != nil {
+		return err
+	}
+	padding := strings.Repeat("x", 150)
+	for i := 0; i < 6; i++ {
+		myLogger.Info("entry", i, padding)
+	}
+	if err = myLogger.Flush(); err != nil {
+		return err
+	}
+	if n := atomic.LoadInt32(&deliveries); n != 1 {
+		return fmt.Errorf("%d writes instead of one", n)
+	}
+	for i := 6; i < 26; i++ {
+		myLogger.Info("entry", i, padding)
+	}
+	if report := myLogger.CloseLogger(); report.Err() != nil || report.EntriesFlushed != 26 {
+		return fmt.Errorf("unexpected close report %+v", report)
+	}
+	data, err := ioutil.ReadFile(dir + "vectored.log")
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	for i, line := range lines {
+		if !strings.Contains(line, fmt.Sprintf("entry %d x", i)) {
+			return fmt.Errorf("unexpected line %d: %q", i, line)
+		}
+	}
+	if len(lines) != 26 {
+		return fmt.Errorf("%d lines instead of 26", len(lines))
+	}
+	return nil
+}
+
+//stuckSink is a sink whose Close blocks until release is closed.
+type stuckSink struct {
+	release chan struct{}
+}
+
+func (s stuckSink) WriteEntry(entry logWriter.Entry) error {
+	return nil
+}
+
+func (s stuckSink) Close() error {
+	<-s.release
+	return nil
+}
+
+//closeExample bounds closing with a context and reports dropped entries as an error.
+func closeExample(dir string) error {
+	release := make(chan struct{})
+	stuck, err := logger.New(logger.WithFile(dir+"stuck.log"), logger.WithSink("stuck", stuckSink{release: release}))
+	if err != nil {
+		return err
+	}
+	err = stuck.CloseWithTimeout(50 * time.Millisecond)
+	close(release)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("unexpected error %v", err)
+	}
+
+	myLogger, err := logger.New(logger.WithFile(dir + "app.log"))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("last entry")
+	if err = myLogger.Close(context.Background()); err != nil {
+		return err
+	}
+	myLogger.Info("dropped: the logger is closed")
+	var closeErr *logger.CloseError
+	if err = myLogger.Close(context.Background()); !errors.As(err, &closeErr) || closeErr.Report.EntriesDropped != 1 {
+		return fmt.Errorf("unexpected error %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"github.com/shyamgrover/go-lite-logger/utils"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+)
+
+//configExample loads a config file and shows how problems are reported with their position.
+func configExample(dir string) error {
+	path := dir + "logger.json"
+	good := `{"level": "debug", "file": "app.log", "dir": "` + dir + `"}`
+	if err := ioutil.WriteFile(path, []byte(good), 0644); err != nil {
+		return err
+	}
+	config, err := logger.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	if config.Level != "debug" || config.File != "app.log" {
+		return fmt.Errorf("unexpected config %+v", config)
+	}
+	options, err := config.Options()
+	if err != nil {
+		return err
+	}
+	myLogger, err := logger.New(options...)
+	if err != nil {
+		return err
+	}
+	myLogger.Debug("configured from", path)
+	if err = myLogger.CloseLogger().Err(); err != nil {
+		return err
+	}
+	if err = expectFile(dir+"app.log", []string{"configured from " + path}, nil); err != nil {
+		return err
+	}
+
+	bad := "{\n  \"level\": \"debug\",\n  \"fiel\": \"app.log\"\n}"
+	if err = ioutil.WriteFile(path, []byte(bad), 0644); err != nil {
+		return err
+	}
+	_, err = logger.LoadConfig(path)
+	if err == nil || !strings.Contains(err.Error(), "logger.json:3:3: fiel: unknown key") {
+		return fmt.Errorf("expected an unknown key error, got %v", err)
+	}
+	return nil
+}
+
+//configFormatsExample creates loggers from YAML and TOML files, one of them with a sink configured by name.
+func configFormatsExample(dir string) error {
+	var received bytes.Buffer
+	err := logWriter.RegisterSink("example-buffer", func(options map[string]interface{}) (logWriter.Sink, error) {
+		received.WriteString(fmt.Sprint(options["prefix"]))
+		return nopCloser{&received}, nil
+	})
+	if err != nil {
+		return err
+	}
+	yaml := "level: debug\nfile: yaml.log\ndir: " + dir + "\nbuffer_size: 16KB\nsinks:\n  example-buffer:\n    prefix: '> '\n"
+	toml := "level = \"warn\"\nfile = \"toml.log\"\ndir = \"" + dir + "\"\nmax_size = \"10MB\"\nqueue = \"ring\"\n\n[sampling.info]\nfirst = 10\n"
+	for name, content := range map[string]string{"logger.yaml": yaml, "logger.toml": toml} {
+		if err = ioutil.WriteFile(dir+name, []byte(content), 0644); err != nil {
+			return err
+		}
+		myLogger, err := logger.NewFromConfig(dir + name)
+		if err != nil {
+			return err
+		}
+		myLogger.Debug("configured from", name)
+		myLogger.Warn("warning configured from", name)
+		myLogger.CloseLogger()
+	}
+	if err = expectFile(dir+"yaml.log", []string{"configured from logger.yaml"}, nil); err != nil {
+		return err
+	}
+	if err = expectFile(dir+"toml.log", []string{"warning configured from logger.toml"}, []string{"[DEBUG]"}); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(received.String(), "> ") || !strings.Contains(received.String(), "configured from logger.yaml") {
+		return fmt.Errorf("unexpected sink output %q", received.String())
+	}
+	return nil
+}
+
+//nopCloser is a Sink writing to a buffer.
+type nopCloser struct {
+	*bytes.Buffer
+}
+
+//This method does nothing; the buffer needs no closing.
+func (nopCloser) Close() error {
+	return nil
+}
+
+//registeredFormatExample chooses a formatter registered by name, in a config file with options and in the
+// environment.
+func registeredFormatExample(dir string) error {
+	err := logWriter.RegisterFormatter("example-tagged", func(options map[string]interface{}) (logWriter.Formatter, error) {
+		tag, _ := options["tag"].(string)
+		return taggedFormatter{tag: tag}, nil
+	})
+	if err != nil {
+		return err
+	}
+	content := "file: config.log\ndir: " + dir + "\nformat: example-tagged\nformat_options:\n  tag: orders\n"
+	if err = ioutil.WriteFile(dir+"logger.yaml", []byte(content), 0644); err != nil {
+		return err
+	}
+	myLogger, err := logger.NewFromConfig(dir + "logger.yaml")
+	if err != nil {
+		return err
+	}
+	myLogger.Info("formatted by name")
+	myLogger.CloseLogger()
+	os.Setenv("LOGGER_FORMAT", "example-tagged")
+	defer os.Unsetenv("LOGGER_FORMAT")
+	if myLogger, err = logger.New(logger.WithFile(dir+"env.log"), logger.WithEnvironment()); err != nil {
+		return err
+	}
+	myLogger.Info("formatted by the environment")
+	myLogger.CloseLogger()
+	if err = expectFile(dir+"config.log", []string{"orders|info|formatted by name\n"}, nil); err != nil {
+		return err
+	}
+	return expectFile(dir+"env.log", []string{"|info|formatted by the environment\n"}, nil)
+}
+
+//taggedFormatter writes entries as tag|level|message lines.
+type taggedFormatter struct {
+	tag string
+}
+
+//This method writes the entry behind the tag of the formatter.
+func (f taggedFormatter) Format(entry logWriter.Entry) ([]byte, error) {
+	return []byte(f.tag + "|" + entry.Level().String() + "|" + entry.Message() + "\n"), nil
+}
+
+//environmentExample overrides the level and format given in code with environment variables, as a container
+// deployment would, and shows that loggers without WithEnvironment ignore them. Keys of config files are
+// overridden by variables named after them.
+func environmentExample(dir string) error {
+	os.Setenv("LOGGER_LEVEL", "debug")
+	os.Setenv("LOGGER_FORMAT", "json")
+	defer os.Unsetenv("LOGGER_LEVEL")
+	defer os.Unsetenv("LOGGER_FORMAT")
+	for _, name := range []string{"env.log", "code.log"} {
+		opts := []logger.Option{logger.WithFile(dir + name), logger.WithLevel(logWriter.InfoLevel)}
+		if name == "env.log" {
+			opts = append(opts, logger.WithEnvironment())
+		}
+		myLogger, err := logger.New(opts...)
+		if err != nil {
+			return err
+		}
+		myLogger.Debug("debug enabled by the environment")
+		myLogger.Info("info")
+		myLogger.CloseLogger()
+	}
+	if err := expectFile(dir+"env.log", []string{`"msg":"debug enabled by the environment"`}, nil); err != nil {
+		return err
+	}
+	if err := expectFile(dir+"code.log", []string{"[INFO]  "}, []string{"debug enabled"}); err != nil {
+		return err
+	}
+	os.Setenv("LOGGER_MAX_SIZE", "200MB")
+	os.Setenv("LOGGER_REDACT_KEYS", `["password"]`)
+	defer os.Unsetenv("LOGGER_MAX_SIZE")
+	defer os.Unsetenv("LOGGER_REDACT_KEYS")
+	path := dir + "logger.json"
+	if err := ioutil.WriteFile(path, []byte(`{"file": "app.log", "max_size": "10MB"}`), 0644); err != nil {
+		return err
+	}
+	config, err := logger.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	if want, _ := utils.ParseSize("200MB"); int64(config.MaxSize) != want || len(config.RedactKeys) != 1 || config.RedactKeys[0] != "password" {
+		return fmt.Errorf("environment not applied: max_size %s, redact_keys %v", config.MaxSize, config.RedactKeys)
+	}
+	for _, interval := range []string{"NaN", "-5s", "1e30", "213504d"} {
+		os.Setenv("LOGGER_FLUSH_INTERVAL", interval)
+		_, err = logger.New(logger.WithFile(dir+"bad.log"), logger.WithEnvironment())
+		os.Unsetenv("LOGGER_FLUSH_INTERVAL")
+		if err == nil || !strings.Contains(err.Error(), "LOGGER_FLUSH_INTERVAL") {
+			return fmt.Errorf("expected an error for LOGGER_FLUSH_INTERVAL=%s, got %v", interval, err)
+		}
+	}
+	os.Setenv("LOGGER_LEVEL", "loud")
+	if _, err := logger.New(logger.WithFile(dir+"bad.log"), logger.WithEnvironment()); err == nil || !strings.Contains(err.Error(), "LOGGER_LEVEL") {
+		return fmt.Errorf("expected an error for LOGGER_LEVEL, got %v", err)
+	}
+	myLogger, err := logger.New(logger.WithFile(dir + "plain.log"))
+	if err != nil {
+		return fmt.Errorf("a logger without WithEnvironment read LOGGER_LEVEL: %v", err)
+	}
+	myLogger.CloseLogger()
+	return nil
+}
+
+//watchConfigExample edits the config file of a running logger, which turns on debug logging and adds a sink.
+func watchConfigExample(dir string) error {
+	var received bytes.Buffer
+	err := logWriter.RegisterSink("example-reload", func(options map[string]interface{}) (logWriter.Sink, error) {
+		return nopCloser{&received}, nil
+	})
+	if err != nil {
+		return err
+	}
+	path := dir + "logger.yaml"
+	content := "level: info\nfile: app.log\ndir: " + dir + "\n"
+	if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		return err
+	}
+	myLogger, err := logger.NewFromConfig(path)
+	if err != nil {
+		return err
+	}
+	stop, err := myLogger.WatchConfig(path, 10*time.Millisecond)
+	if err != nil {
+		return err
+	}
+	defer stop()
+	myLogger.Debug("debug before the edit")
+
+	content = strings.Replace(content, "level: info", "level: debug", 1) + "sinks:\n  example-reload: {}\n"
+	if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		return err
+	}
+	for i := 0; i < 100 && myLogger.GetLevel() != logWriter.DebugLevel; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	myLogger.Debug("debug after the edit")
+	myLogger.CloseLogger()
+	if !strings.Contains(received.String(), "debug after the edit") {
+		return fmt.Errorf("unexpected sink output %q", received.String())
+	}
+	return expectFile(dir+"app.log",
+		[]string{"reloaded: level info -> debug, sink example-reload added", "debug after the edit"},
+		[]string{"before the edit"})
+}
+
+//registryExample registers loggers by name and looks them up from elsewhere.
+func registryExample(dir string) error {
+	if _, err := logger.Register("payments", &logger.Config{File: "payments.log", Dir: dir, Format: "json"}); err != nil {
+		return err
+	}
+	audit, err := logger.New(logger.WithFile(dir + "audit.log"))
+	if err != nil {
+		return err
+	}
+	if err = logger.RegisterLogger("audit", audit); err != nil {
+		return err
+	}
+	if err = logger.RegisterLogger("audit", audit); err == nil {
+		return fmt.Errorf("registered a name twice")
+	}
+	chargeCaptured()
+	logger.Get("audit").Info("refund approved")
+	if names := logger.Registered(); len(names) != 2 || names[0] != "audit" || names[1] != "payments" {
+		return fmt.Errorf("unexpected names %q", names)
+	}
+	if reports := logger.CloseRegistered(); len(reports) != 2 || logger.Get("payments") != nil {
+		return fmt.Errorf("unexpected reports %+v", reports)
+	}
+	if err = expectFile(dir+"payments.log", []string{`"msg":"charge captured","amount":1250}`}, nil); err != nil {
+		return err
+	}
+	return expectFile(dir+"audit.log", []string{"refund approved"}, nil)
+}
+
+//chargeCaptured stands for a component that looks up its logger by name.
+func chargeCaptured() {
+	logger.Get("payments").WithField("amount", 1250).Info("charge captured")
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"os"
+)
+
+//destinationsExample sends some entries to a separate audit file with To.
+func destinationsExample(dir string) error {
+	myLogger, err := logger.CreateLogger(logWriter.InfoLevel, "app.log", dir, func() {})
+	if err != nil {
+		return err
+	}
+	if err = myLogger.AddDestination("audit", "audit.log", dir); err != nil {
+		return err
+	}
+	myLogger.Info("request served")
+	myLogger.To("audit").Info("user 42 deleted invoice 7")
+	myLogger.CloseLogger()
+
+	if err = expectFile(dir+"app.log", []string{"request served"}, []string{"invoice"}); err != nil {
+		return err
+	}
+	return expectFile(dir+"audit.log", []string{"user 42 deleted invoice 7"}, []string{"request served"})
+}
+
+//levelFilesExample keeps errors in a file of their own and everything else in the main file.
+func levelFilesExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile("app.log"), logger.WithDir(dir),
+		logger.WithLevelFile("error.log", logWriter.ErrorLevel, logWriter.FatalLevel, logWriter.PanicLevel))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("request served")
+	myLogger.Error("request failed")
+	myLogger.CloseLogger()
+
+	if err = expectFile(dir+"app.log", []string{"request served"}, []string{"failed"}); err != nil {
+		return err
+	}
+	return expectFile(dir+"error.log", []string{"[ERROR]", "request failed"}, []string{"served"})
+}
+
+//stderrExample tees warnings to stderr, here redirected to a file, while all entries go to the log file.
+func stderrExample(dir string) error {
+	console, err := os.Create(dir + "stderr.txt")
+	if err != nil {
+		return err
+	}
+	defer console.Close()
+	saved := os.Stderr
+	os.Stderr = console
+	defer func() { os.Stderr = saved }()
+
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithStderr(logWriter.WarnLevel))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("cache warmed")
+	myLogger.Warn("disk 91% full")
+	myLogger.CloseLogger()
+	if err = expectFile(dir+"app.log", []string{"cache warmed", "disk 91% full"}, nil); err != nil {
+		return err
+	}
+	return expectFile(dir+"stderr.txt", []string{"disk 91% full"}, []string{"cache warmed"})
+}
@@ -0,0 +1,16 @@
+package main
+
+import "testing"
+
+// TestExamples runs every registered example like the command does, each as a subtest, so that go test reports
+// a failing example with what it found in the log files.
+func TestExamples(t *testing.T) {
+	for _, ex := range examples {
+		ex := ex
+		t.Run(ex.name, func(t *testing.T) {
+			if err := runExample(ex); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
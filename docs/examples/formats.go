@@ -0,0 +1,719 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+//timestampExample holds the worker for a while and checks that the entry carries the time it was logged at, not
+// the time it was written.
+func timestampExample(dir string) error {
+	gate := make(chan struct{})
+	myLogger, err := logger.New(logger.WithFile(dir+"app.json"),
+		logger.WithFormatter(gatedFormatter{gate: gate, next: logWriter.JSONFormatter{}}))
+	if err != nil {
+		return err
+	}
+	logged := time.Now()
+	myLogger.Info("queued")
+	time.Sleep(100 * time.Millisecond)
+	close(gate)
+	myLogger.CloseLogger()
+
+	data, err := ioutil.ReadFile(dir + "app.json")
+	if err != nil {
+		return err
+	}
+	var record struct {
+		Time time.Time `json:"time"`
+	}
+	if err = json.Unmarshal(data, &record); err != nil {
+		return err
+	}
+	if delay := record.Time.Sub(logged); delay < 0 || delay > 50*time.Millisecond {
+		return fmt.Errorf("timestamp %v is %v after the entry was logged", record.Time, delay)
+	}
+	return nil
+}
+
+//timeLayoutExample writes text lines with RFC 3339 timestamps in UTC and JSON lines with Unix milliseconds.
+func timeLayoutExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithTimeLayout(time.RFC3339), logger.WithUTC())
+	if err != nil {
+		return err
+	}
+	myLogger.Info("in UTC")
+	myLogger.CloseLogger()
+	stamp := time.Now().UTC().Format("2006-01-02T")
+	if err = expectFile(dir+"app.log", []string{"[INFO]  " + stamp, "Z formats.go:"}, nil); err != nil {
+		return err
+	}
+
+	myLogger, err = logger.New(logger.WithFile(dir+"app.json"),
+		logger.WithFormatter(logWriter.JSONFormatter{TimestampFormat: logWriter.UnixMillis}))
+	if err != nil {
+		return err
+	}
+	before := time.Now().UnixNano() / int64(time.Millisecond)
+	myLogger.Info("in milliseconds")
+	myLogger.CloseLogger()
+	data, err := ioutil.ReadFile(dir + "app.json")
+	if err != nil {
+		return err
+	}
+	var record struct {
+		Time int64 `json:"time"`
+	}
+	if err = json.Unmarshal(data, &record); err != nil {
+		return err
+	}
+	if record.Time < before || record.Time > before+1000 {
+		return fmt.Errorf("unexpected time %d, logged at %d", record.Time, before)
+	}
+	return nil
+}
+
+//logFailure is a helper that logs through a logger skipping one more frame, so that its callers are named.
+func logFailure(l *logger.Logger, what string) {
+	l.AddCallerSkip(1).Error("failed:", what)
+}
+
+//callerExample names the call sites of entries: direct calls, calls through a helper and the package-level
+// functions, in text and in JSON.
+func callerExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir + "app.log"))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("direct")
+	logFailure(myLogger, "helper")
+	logger.SetDefault(myLogger)
+	logger.Warn("package-level")
+	logger.SetDefault(nil)
+	myLogger.CloseLogger()
+	data, err := ioutil.ReadFile(dir + "app.log")
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if !strings.Contains(line, " formats.go:") {
+			return fmt.Errorf("wrong caller in %q", line)
+		}
+	}
+
+	myLogger, err = logger.New(logger.WithFile(dir+"app.json"), logger.WithFormatter(logWriter.JSONFormatter{Caller: true}))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("direct")
+	myLogger.CloseLogger()
+	return expectFile(dir+"app.json", []string{`"caller":"formats.go:`}, nil)
+}
+
+//stackTraceExample records the stack of Error entries only, in text and in JSON.
+func stackTraceExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithStackTrace(logWriter.ErrorLevel, 8))
+	if err != nil {
+		return err
+	}
+	myLogger.Warn("no stack")
+	myLogger.Error("with stack")
+	myLogger.CloseLogger()
+	err = expectFile(dir+"app.log", []string{"with stack\n\t", ".stackTraceExample\n\t\t", "formats.go:"},
+		[]string{"no stack\n\t"})
+	if err != nil {
+		return err
+	}
+
+	myLogger, err = logger.New(logger.WithFile(dir+"app.json"), logger.WithFormatter(logWriter.JSONFormatter{}),
+		logger.WithStackTrace(logWriter.ErrorLevel, 1))
+	if err != nil {
+		return err
+	}
+	myLogger.Error("with stack")
+	myLogger.CloseLogger()
+	return expectFile(dir+"app.json", []string{`"stack":"`, `.stackTraceExample\n\t`}, []string{`main.main`})
+}
+
+//processFieldsExample adds the application name, host name and process ID to every entry.
+func processFieldsExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithProcessFields("billing"))
+	if err != nil {
+		return err
+	}
+	myLogger.WithField("user", 42).Info("login ok")
+	myLogger.CloseLogger()
+	host, _ := os.Hostname()
+	return expectFile(dir+"app.log", []string{fmt.Sprintf("login ok app=billing host=%s pid=%d user=42", host, os.Getpid())}, nil)
+}
+
+//sequenceExample numbers entries in text and JSON; loggers derived with WithField share the numbering.
+func sequenceExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithSequence())
+	if err != nil {
+		return err
+	}
+	myLogger.Info("first")
+	myLogger.WithField("user", 42).Info("second")
+	myLogger.CloseLogger()
+	if err = expectFile(dir+"app.log", []string{"first seq=1\n", "second user=42 seq=2\n"}, nil); err != nil {
+		return err
+	}
+
+	myLogger, err = logger.New(logger.WithFile(dir+"app.json"), logger.WithFormatter(logWriter.JSONFormatter{Sequence: true}))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("first")
+	myLogger.CloseLogger()
+	return expectFile(dir+"app.json", []string{`"level":"info","seq":1,"msg":"first"`}, nil)
+}
+
+//deployHook tags entries with the deployment and vetoes the ones mentioning a secret.
+type deployHook struct {
+	fired int
+}
+
+func (hook *deployHook) Levels() []logWriter.Level { return logWriter.AllLevels }
+
+func (hook *deployHook) Fire(entry *logWriter.Entry) error {
+	hook.fired++
+	if strings.Contains(entry.Message(), "secret") {
+		return logWriter.ErrVeto
+	}
+	entry.AddField("deploy", "canary")
+	return nil
+}
+
+//hooksExample adds a hook that enriches entries and vetoes one of them.
+func hooksExample(dir string) error {
+	hook := &deployHook{}
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithHook(hook))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("started")
+	myLogger.WithField("user", 42).Warn("the secret is 1234")
+	stats := myLogger.Stats()
+	myLogger.CloseLogger()
+	if hook.fired != 2 || stats.EntriesDropped != 0 {
+		return fmt.Errorf("hook fired %d times, %d entries dropped", hook.fired, stats.EntriesDropped)
+	}
+	return expectFile(dir+"app.log", []string{"started deploy=canary\n"}, []string{"secret"})
+}
+
+//deliveryExample counts the entries and bytes that reached the file.
+func deliveryExample(dir string) error {
+	var entries uint64
+	var bytes int
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithDeliveryHook(func(d logWriter.Delivery) {
+		entries += d.Entries
+		bytes += d.Bytes
+	}))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("first")
+	myLogger.Info("second")
+	if err = myLogger.Flush(); err != nil {
+		return err
+	}
+	myLogger.Info("third")
+	myLogger.CloseLogger()
+	info, err := os.Stat(dir + "app.log")
+	if err != nil {
+		return err
+	}
+	if entries != 3 || int64(bytes) != info.Size() {
+		return fmt.Errorf("delivered %d entries and %d bytes, file has %d bytes", entries, bytes, info.Size())
+	}
+	return nil
+}
+
+//redactionExample masks a password field and scrubs a card number and an email address from the messages and
+// from errors.
+func redactionExample(dir string) error {
+	redactor := &logWriter.Redactor{Keys: []string{"password"},
+		Patterns: []*regexp.Regexp{logWriter.CreditCardPattern, logWriter.EmailPattern}}
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithRedaction(redactor))
+	if err != nil {
+		return err
+	}
+	myLogger.WithFields(logWriter.Fields{"user": "ann", "Password": "hunter2"}).Info("login")
+	myLogger.Infof("charged card %s", "4111 1111 1111 1111")
+	myLogger.WithField("contact", "ann@example.com").Info("mail sent to ann@example.com")
+	failure := fmt.Errorf("notify ann@example.com: %w", errors.New("mailbox full"))
+	myLogger.WithError(failure).Error("notify failed")
+	myLogger.Errorw("retry failed", logWriter.Err(failure))
+	myLogger.CloseLogger()
+	return expectFile(dir+"app.log", []string{"login Password=[REDACTED] user=ann\n", "charged card [REDACTED]\n",
+		"mail sent to [REDACTED] contact=[REDACTED]\n", "notify failed", "retry failed", "mailbox full"},
+		[]string{"hunter2", "4111", "example.com"})
+}
+
+//encryptionExample writes an encrypted log file, checks that it holds no plain text and decrypts it.
+func encryptionExample(dir string) error {
+	key := logWriter.StaticKey("0123456789abcdef0123456789abcdef")
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithEncryption(key), logger.WithSelfCheck())
+	if err != nil {
+		return err
+	}
+	myLogger.Info("card on file")
+	if err = myLogger.Flush(); err != nil {
+		return err
+	}
+	myLogger.Info("payment done")
+	myLogger.CloseLogger()
+	if err = expectFile(dir+"app.log", nil, []string{"card on file", "payment done"}); err != nil {
+		return err
+	}
+	file, err := os.Open(dir + "app.log")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	plain, err := ioutil.ReadAll(logWriter.NewDecryptReader(file, key))
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(plain), "card on file\n") || !strings.Contains(string(plain), "payment done\n") {
+		return fmt.Errorf("decrypted %q", plain)
+	}
+	return nil
+}
+
+//auditExample writes an audit log in two runs, verifies its chain and detects a changed record.
+func auditExample(dir string) error {
+	key := []byte("audit key")
+	for _, message := range []string{"user 42 granted admin", "user 42 revoked admin"} {
+		myLogger, err := logger.New(logger.WithFile(dir+"audit.log"), logger.WithAudit(key),
+			logger.WithStackTrace(logWriter.ErrorLevel, 4))
+		if err != nil {
+			return err
+		}
+		myLogger.Info(message)
+		myLogger.Error("config reloaded by", "root")
+		myLogger.CloseLogger()
+	}
+	data, err := ioutil.ReadFile(dir + "audit.log")
+	if err != nil {
+		return err
+	}
+	if _, err = logWriter.VerifyAudit(bytes.NewReader(data), key, nil); err != nil {
+		return err
+	}
+	tampered := bytes.Replace(data, []byte("revoked"), []byte("granted"), 1)
+	_, err = logWriter.VerifyAudit(bytes.NewReader(tampered), key, nil)
+	if auditErr, ok := err.(*logWriter.AuditError); !ok || auditErr.Record != 3 {
+		return fmt.Errorf("tampered file verified with %v", err)
+	}
+
+	myLogger, err := logger.New(logger.WithFile(dir+"audit.json"), logger.WithAudit(key),
+		logger.WithFormatter(logWriter.JSONFormatter{}))
+	if err != nil {
+		return err
+	}
+	myLogger.WithField("user", 42).Info("login")
+	myLogger.CloseLogger()
+	file, err := os.Open(dir + "audit.json")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = logWriter.VerifyAudit(file, key, nil)
+	return err
+}
+
+//jsonExample writes JSON lines and parses them back.
+func jsonExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.json"), logger.WithFormatter(logWriter.JSONFormatter{}),
+		logger.WithSelfCheck())
+	if err != nil {
+		return err
+	}
+	myLogger.Info("service started")
+	myLogger.Errorf("request %d failed", 7)
+	if err = myLogger.CloseLogger().Err(); err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(dir + "app.json")
+	if err != nil {
+		return err
+	}
+	var levels, messages []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record map[string]interface{}
+		if err = json.Unmarshal([]byte(line), &record); err != nil {
+			return fmt.Errorf("not a JSON line %q: %v", line, err)
+		}
+		if _, ok := record["time"].(string); !ok {
+			return fmt.Errorf("no time in %q", line)
+		}
+		levels = append(levels, fmt.Sprint(record["level"]))
+		messages = append(messages, fmt.Sprint(record["msg"]))
+	}
+	if len(messages) != 3 || messages[1] != "service started" || messages[2] != "request 7 failed" ||
+		levels[2] != "error" {
+		return fmt.Errorf("unexpected records %q at levels %q", messages, levels)
+	}
+	return nil
+}
+
+//ecsExample writes Elastic Common Schema lines and checks the keys Kibana expects.
+func ecsExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.json"),
+		logger.WithFormatter(logWriter.ECSFormatter{Service: "orders", Caller: true}))
+	if err != nil {
+		return err
+	}
+	myLogger.WithFields(logWriter.Fields{"user": 42, "http.method": "GET", "error": errors.New("timeout")}).
+		Warn("request failed")
+	if err = myLogger.CloseLogger().Err(); err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(dir + "app.json")
+	if err != nil {
+		return err
+	}
+	var record struct {
+		Timestamp string `json:"@timestamp"`
+		Level     string `json:"log.level"`
+		Message   string `json:"message"`
+		Version   string `json:"ecs.version"`
+		Service   struct {
+			Name string `json:"name"`
+		} `json:"service"`
+		Origin struct {
+			File string `json:"file.name"`
+			Line int    `json:"file.line"`
+		} `json:"log.origin"`
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Labels map[string]interface{} `json:"labels"`
+	}
+	if err = json.Unmarshal(data, &record); err != nil {
+		return fmt.Errorf("not a JSON line %q: %v", data, err)
+	}
+	if _, err = time.Parse(time.RFC3339, record.Timestamp); err != nil || !strings.HasSuffix(record.Timestamp, "Z") {
+		return fmt.Errorf("unexpected @timestamp %q", record.Timestamp)
+	}
+	if record.Level != "warning" || record.Message != "request failed" || record.Version != logWriter.ECSVersion ||
+		record.Service.Name != "orders" || record.Origin.File != "formats.go" || record.Origin.Line == 0 ||
+		record.Error.Message != "timeout" {
+		return fmt.Errorf("unexpected record %q", data)
+	}
+	if len(record.Labels) != 2 || record.Labels["user"] != 42.0 || record.Labels["http_method"] != "GET" {
+		return fmt.Errorf("unexpected labels %v", record.Labels)
+	}
+	return nil
+}
+
+//csvExample writes CSV records with a column for one field and reads them back with encoding/csv.
+func csvExample(dir string) error {
+	formatter := logWriter.CSVFormatter{Columns: []string{"user"}}
+	myLogger, err := logger.New(logger.WithFile(dir+"app.csv"), logger.WithFormatter(formatter))
+	if err != nil {
+		return err
+	}
+	myLogger.WithFields(logWriter.Fields{"user": 42, "path": "/orders", "took": "3 ms"}).Info("request, handled")
+	myLogger.Warn("multi\nline \"quoted\"")
+	if err = myLogger.CloseLogger().Err(); err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(dir + "app.csv")
+	if err != nil {
+		return err
+	}
+	records, err := csv.NewReader(io.MultiReader(bytes.NewReader(formatter.Header()), bytes.NewReader(data))).ReadAll()
+	if err != nil {
+		return err
+	}
+	want := [][]string{
+		{"time", "level", "caller", "message", "user", "fields"},
+		{"info", "request, handled", "42", `path=/orders took="3 ms"`},
+		{"warning", "multi\nline \"quoted\"", "", ""},
+	}
+	if len(records) != len(want) || strings.Join(records[0], ",") != strings.Join(want[0], ",") {
+		return fmt.Errorf("unexpected records %q", records)
+	}
+	for i, record := range records[1:] {
+		got := []string{record[1], record[3], record[4], record[5]}
+		if strings.Join(got, "|") != strings.Join(want[i+1], "|") || !strings.HasPrefix(record[2], "formats.go:") {
+			return fmt.Errorf("unexpected record %q", record)
+		}
+		if _, err = time.Parse(time.RFC3339Nano, record[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//patternExample writes lines in a legacy layout with a pattern and with a template, and checks both.
+func patternExample(dir string) error {
+	pattern, err := logWriter.ParsePattern("%t{2006-01-02 15:04:05} %-7L [%c] user=%f{user} %m (%s)")
+	if err != nil {
+		return err
+	}
+	if _, err = logWriter.ParsePattern("%t %q"); err == nil {
+		return fmt.Errorf("unknown verb accepted")
+	}
+	template, err := logWriter.NewTemplateFormatter(`{{.Level}}|{{.Message}}{{range $k, $v := .Fields}}|{{$k}}={{$v}}{{end}}`)
+	if err != nil {
+		return err
+	}
+	for _, formatter := range []logWriter.Formatter{pattern, template} {
+		myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithFormatter(formatter))
+		if err != nil {
+			return err
+		}
+		myLogger.WithFields(logWriter.Fields{"user": 42, "path": "/orders"}).Warn("slow request")
+		if err = myLogger.CloseLogger().Err(); err != nil {
+			return err
+		}
+	}
+	data, err := ioutil.ReadFile(dir + "app.log")
+	if err != nil {
+		return err
+	}
+	if !regexp.MustCompile(`^\d{4}-\d\d-\d\d \d\d:\d\d:\d\d WARNING \[formats\.go:\d+\] user=42 slow request \(1\)\n` +
+		`warning\|slow request\|path=/orders\|user=42\n$`).Match(data) {
+		return fmt.Errorf("unexpected lines %q", data)
+	}
+	return nil
+}
+
+//binaryExample writes binary records, reads them back and renders one as text the way cmd/logcat does, then
+// cuts the file off in the middle of the last record.
+func binaryExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.bin"), logger.WithFormatter(logWriter.BinaryFormatter{}),
+		logger.WithSequence())
+	if err != nil {
+		return err
+	}
+	myLogger.WithFields(logWriter.Fields{"user": 42, "ok": true, "took": 1.5}).Info("login")
+	myLogger.Errorf("request %d failed", 7)
+	if err = myLogger.CloseLogger().Err(); err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(dir + "app.bin")
+	if err != nil {
+		return err
+	}
+	reader := logWriter.NewBinaryReader(bytes.NewReader(data))
+	var entries []logWriter.Entry
+	for {
+		entry, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 || entries[0].Message() != "login" || entries[1].Message() != "request 7 failed" ||
+		entries[1].Level() != logWriter.ErrorLevel || entries[1].Sequence() != entries[0].Sequence()+1 {
+		return fmt.Errorf("unexpected entries %v", entries)
+	}
+	fields := entries[0].Fields()
+	if fields["user"] != uint64(42) || fields["ok"] != true || fields["took"] != 1.5 {
+		return fmt.Errorf("unexpected fields %v", fields)
+	}
+	line, err := logWriter.TextFormatter{Caller: true}.Format(entries[0])
+	if err != nil {
+		return err
+	}
+	if !regexp.MustCompile(`^\[INFO\]  \S+ \S+ formats\.go:\d+: login ok=true took=1.5 user=42\n$`).Match(line) {
+		return fmt.Errorf("unexpected text %q", line)
+	}
+
+	reader = logWriter.NewBinaryReader(bytes.NewReader(data[:len(data)-3]))
+	if _, err = reader.Read(); err != nil {
+		return err
+	}
+	if _, err = reader.Read(); !errors.Is(err, logWriter.ErrCorruptRecord) {
+		return fmt.Errorf("cut off record read with %v", err)
+	}
+	return nil
+}
+
+//typedFieldsExample logs typed fields with the classic text lines, where they are appended as they are, and
+// with a hook that sees them as ordinary fields.
+func typedFieldsExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir + "app.log"))
+	if err != nil {
+		return err
+	}
+	myLogger.Infow("request handled", logWriter.String("path", "/orders list"), logWriter.Int("status", 200),
+		logWriter.Duration("took", 1500*time.Millisecond), logWriter.Bool("cached", false), logWriter.Err(nil),
+		logWriter.Int("status", 201))
+	myLogger.Errorw("request failed", logWriter.Err(errors.New("timeout")), logWriter.Float64("ratio", 0.5))
+	myLogger.Debugw("not logged", logWriter.String("path", "/"))
+	if err = myLogger.CloseLogger().Err(); err != nil {
+		return err
+	}
+	if err = expectFile(dir+"app.log", []string{
+		`: request handled cached=false path="/orders list" status=201 took=1.5s` + "\n",
+		`: request failed error=timeout ratio=0.5` + "\n",
+	}, []string{"not logged", "status=200"}); err != nil {
+		return err
+	}
+
+	hook := &deployHook{}
+	hooked, err := logger.New(logger.WithFile(dir+"hooked.log"), logger.WithHook(hook))
+	if err != nil {
+		return err
+	}
+	hooked.WithFields(logWriter.Fields{"user": 42}).Warnw("slow", logWriter.Int64("took_ms", 900))
+	if err = hooked.CloseLogger().Err(); err != nil {
+		return err
+	}
+	return expectFile(dir+"hooked.log", []string{": slow deploy=canary took_ms=900 user=42\n"}, nil)
+}
+
+//withErrorExample attaches a wrapped error and checks that both formats show its chain down to the root cause.
+func withErrorExample(dir string) error {
+	cause := fmt.Errorf("charge card: %w", errors.New("gateway timeout"))
+	failure := fmt.Errorf("checkout: %w", cause)
+
+	textLogger, err := logger.New(logger.WithFile(dir + "app.log"))
+	if err != nil {
+		return err
+	}
+	textLogger.WithError(failure).Error("order failed")
+	textLogger.WithError(nil).Info("order placed")
+	if err = textLogger.CloseLogger().Err(); err != nil {
+		return err
+	}
+	if err = expectFile(dir+"app.log", []string{
+		`: order failed error="checkout: charge card: gateway timeout" ` +
+			`error_chain="checkout -> charge card -> gateway timeout"` + "\n",
+		": order placed\n",
+	}, nil); err != nil {
+		return err
+	}
+
+	jsonLogger, err := logger.New(logger.WithFile(dir+"app.json"), logger.WithFormatter(logWriter.JSONFormatter{}))
+	if err != nil {
+		return err
+	}
+	jsonLogger.WithError(failure).Error("order failed")
+	if err = jsonLogger.CloseLogger().Err(); err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(dir + "app.json")
+	if err != nil {
+		return err
+	}
+	var record struct {
+		Error string   `json:"error"`
+		Chain []string `json:"error_chain"`
+	}
+	if err = json.Unmarshal(data, &record); err != nil {
+		return err
+	}
+	if record.Error != failure.Error() || len(record.Chain) != 3 || record.Chain[2] != "gateway timeout" {
+		return fmt.Errorf("unexpected error fields %q", data)
+	}
+	return nil
+}
+
+//fieldsExample attaches fields to entries and shows how both formats render them.
+func fieldsExample(dir string) error {
+	textLogger, err := logger.CreateLogger(logWriter.InfoLevel, "app.log", dir, func() {})
+	if err != nil {
+		return err
+	}
+	requestLogger := textLogger.WithFields(logWriter.Fields{"user": 42, "path": "/login"})
+	requestLogger.Info("login ok")
+	requestLogger.WithField("reason", "bad password").Warn("login failed")
+	textLogger.CloseLogger()
+	err = expectFile(dir+"app.log",
+		[]string{"login ok path=/login user=42", `login failed path=/login reason="bad password" user=42`}, nil)
+	if err != nil {
+		return err
+	}
+
+	jsonLogger, err := logger.New(logger.WithFile(dir+"app.json"), logger.WithFormatter(logWriter.JSONFormatter{}))
+	if err != nil {
+		return err
+	}
+	jsonLogger.WithFields(logWriter.Fields{"user": 42, "level": "admin"}).Info("login ok")
+	jsonLogger.CloseLogger()
+	return expectFile(dir+"app.json", []string{`"msg":"login ok","fields.level":"admin","user":42}`}, nil)
+}
+
+//userKey is the context key of the user ID in contextExample.
+type userKey struct{}
+
+//contextExample attaches request-scoped fields from a context: a request ID stored with ContextWithFields and a
+// user ID taken from the context by an extractor.
+func contextExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithFormatter(logWriter.TextFormatter{}),
+		logger.WithContextExtractor(func(ctx context.Context) logWriter.Fields {
+			if user, ok := ctx.Value(userKey{}).(int); ok {
+				return logWriter.Fields{"user": user}
+			}
+			return nil
+		}))
+	if err != nil {
+		return err
+	}
+	ctx := logger.ContextWithFields(context.Background(), logWriter.Fields{"request_id": "r-17"})
+	ctx = context.WithValue(ctx, userKey{}, 42)
+	myLogger.WithContext(ctx).Info("order created")
+
+	ctx = logger.NewContext(ctx, myLogger.WithField("component", "billing"))
+	logger.FromContext(ctx).Warn("card declined")
+	myLogger.WithContext(context.Background()).Info("no request")
+	myLogger.CloseLogger()
+	return expectFile(dir+"app.log", []string{
+		" order created request_id=r-17 user=42\n",
+		" card declined component=billing request_id=r-17 user=42\n",
+		" no request\n",
+	}, nil)
+}
+
+//withExample bakes service fields into a child logger; they lead the fields of every entry in both formats.
+func withExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithFormatter(logWriter.TextFormatter{}))
+	if err != nil {
+		return err
+	}
+	billing := myLogger.With("service", "billing", "version", "1.4.2")
+	billing.WithField("invoice", 17).Info("invoice paid")
+	billing.With("component", "pdf", "service", "billing-pdf").Warn("slow render")
+	myLogger.CloseLogger()
+	err = expectFile(dir+"app.log", []string{
+		" invoice paid service=billing version=1.4.2 invoice=17\n",
+		" slow render service=billing-pdf version=1.4.2 component=pdf\n",
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	jsonLogger, err := logger.New(logger.WithFile(dir+"app.json"), logger.WithFormatter(logWriter.JSONFormatter{}))
+	if err != nil {
+		return err
+	}
+	jsonLogger.With("service", "billing").WithField("amount", 5).Info("refund")
+	jsonLogger.CloseLogger()
+	return expectFile(dir+"app.json", []string{`"msg":"refund","service":"billing","amount":5}`}, nil)
+}
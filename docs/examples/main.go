@@ -0,0 +1,165 @@
+// Command examples runs a small, self-checking example for every major feature of the logger. Each example
+// writes to its own temporary directory and verifies what ended up in the log files, so running
+//
+//	go run ./docs/examples
+//
+// (e.g. in CI) fails as soon as a feature stops working as documented. Pass example names as arguments to
+// run only those. go test ./docs/examples runs them as well, each as a subtest of TestExamples.
+package main
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+//example is a named, runnable example. run gets an empty directory to write its files to.
+type example struct {
+	name string
+	run  func(dir string) error
+}
+
+//all examples, in the order they are run.
+var examples = []example{
+	{"basic", basicExample},
+	{"options", optionsExample},
+	{"permissions", permissionsExample},
+	{"file-lock", fileLockExample},
+	{"overflow", overflowExample},
+	{"ring queue", ringQueueExample},
+	{"synchronous", synchronousExample},
+	{"shards", shardsExample},
+	{"default", defaultExample},
+	{"flush", flushExample},
+	{"stats", statsExample},
+	{"sampling", samplingExample},
+	{"dedup", dedupExample},
+	{"rate-limit", rateLimitExample},
+	{"trace-on-error", traceOnErrorExample},
+	{"error-handler", errorHandlerExample},
+	{"errors", errorsExample},
+	{"retry", retryExample},
+	{"fallback", fallbackExample},
+	{"disk-full", diskFullExample},
+	{"large-entry", largeEntryExample},
+	{"vectored writes", vectoredWritesExample},
+	{"fsync", fsyncExample},
+	{"json", jsonExample},
+	{"ecs", ecsExample},
+	{"csv", csvExample},
+	{"pattern", patternExample},
+	{"binary", binaryExample},
+	{"timestamp", timestampExample},
+	{"time-layout", timeLayoutExample},
+	{"caller", callerExample},
+	{"stack-trace", stackTraceExample},
+	{"fields", fieldsExample},
+	{"typed-fields", typedFieldsExample},
+	{"with-error", withErrorExample},
+	{"context", contextExample},
+	{"with", withExample},
+	{"process-fields", processFieldsExample},
+	{"sequence", sequenceExample},
+	{"hooks", hooksExample},
+	{"delivery", deliveryExample},
+	{"redaction", redactionExample},
+	{"encryption", encryptionExample},
+	{"audit", auditExample},
+	{"writer", writerExample},
+	{"http", httpExample},
+	{"access-log", accessLogExample},
+	{"expvar", expvarExample},
+	{"rotation", rotationExample},
+	{"daily-rotation", dailyRotationExample},
+	{"sinks", sinksExample},
+	{"logtest", logtestExample},
+	{"clock", clockExample},
+	{"console", consoleExample},
+	{"syslog", syslogExample},
+	{"network", networkExample},
+	{"gelf", gelfExample},
+	{"loki", lokiExample},
+	{"fluentd", fluentdExample},
+	{"kafka", kafkaExample},
+	{"cloudwatch", cloudWatchExample},
+	{"elasticsearch", elasticsearchExample},
+	{"mmap", mmapExample},
+	{"destinations", destinationsExample},
+	{"level-files", levelFilesExample},
+	{"stderr", stderrExample},
+	{"verbosity", verbosityExample},
+	{"level-handler", levelHandlerExample},
+	{"levels", levelsExample},
+	{"level-flag", levelFlagExample},
+	{"custom-levels", customLevelsExample},
+	{"named", namedExample},
+	{"config", configExample},
+	{"config-formats", configFormatsExample},
+	{"registered-format", registeredFormatExample},
+	{"environment", environmentExample},
+	{"watch-config", watchConfigExample},
+	{"registry", registryExample},
+	{"close-report", closeReportExample},
+	{"close", closeExample},
+}
+
+func main() {
+	selected := make(map[string]bool)
+	for _, name := range os.Args[1:] {
+		selected[name] = true
+	}
+	failed := false
+	for _, ex := range examples {
+		if len(selected) > 0 && !selected[ex.name] {
+			continue
+		}
+		if err := runExample(ex); err != nil {
+			fmt.Printf("FAIL %s: %v\n", ex.name, err)
+			failed = true
+		} else {
+			fmt.Printf("ok   %s\n", ex.name)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+//This method runs an example in a fresh temporary directory and removes the directory afterwards.
+func runExample(ex example) error {
+	dir, err := ioutil.TempDir("", "go-lite-logger-"+ex.name)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	return ex.run(dir + string(os.PathSeparator))
+}
+
+//logScoped is a logging helper whose callers are named instead of itself, see AddCallerSkip. It lives in another
+// file than its callers, so that a scoped level matches the caller's file and not this one.
+func logScoped(l *logger.Logger, message string) {
+	l.Debug(message)
+}
+
+//This method checks that the file at path contains every one of the given strings, and none of the unwanted
+// ones.
+func expectFile(path string, wanted []string, unwanted []string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+	for _, w := range wanted {
+		if !strings.Contains(content, w) {
+			return fmt.Errorf("%s: missing %q in:\n%s", path, w, content)
+		}
+	}
+	for _, u := range unwanted {
+		if strings.Contains(content, u) {
+			return fmt.Errorf("%s: unexpected %q in:\n%s", path, u, content)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+//go:build unix
+
+package main
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"os"
+	"syscall"
+	"time"
+)
+
+//SIGHUP only exists on Unix, so the example is only registered there.
+func init() {
+	examples = append(examples, example{"reopen", reopenExample})
+}
+
+//reopenExample renames the log file like logrotate does and sends SIGHUP, after which logging continues in a
+// fresh file under the original name.
+func reopenExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir + "app.log"))
+	if err != nil {
+		return err
+	}
+	defer myLogger.CloseLogger()
+	stop := myLogger.ReopenOnSignal()
+	defer stop()
+
+	myLogger.Info("before rotation")
+	if err = myLogger.SelfCheck(); err != nil {
+		return err
+	}
+	if err = os.Rename(dir+"app.log", dir+"app.log.1"); err != nil {
+		return err
+	}
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		return err
+	}
+	if err = process.Signal(syscall.SIGHUP); err != nil {
+		return err
+	}
+	for i := 0; i < 100 && !exists(dir+"app.log"); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	myLogger.Info("after rotation")
+	if err = myLogger.SelfCheck(); err != nil {
+		return err
+	}
+	if err = expectFile(dir+"app.log.1", []string{"before rotation"}, []string{"after rotation"}); err != nil {
+		return err
+	}
+	return expectFile(dir+"app.log", []string{"after rotation"}, []string{"before rotation"})
+}
+
+//This method reports whether a file exists at path.
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
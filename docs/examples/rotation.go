@@ -0,0 +1,93 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//rotationExample rotates a small log file by size and checks that no entry was lost in the process.
+func rotationExample(dir string) error {
+	const entries = 500
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithMaxSize(8192), logger.WithBufferSize(1024))
+	if err != nil {
+		return err
+	}
+	for i := 0; i < entries; i++ {
+		myLogger.Infof("entry %04d", i)
+	}
+	if err = myLogger.CloseLogger().Err(); err != nil {
+		return err
+	}
+
+	rotated, err := filepath.Glob(dir + "app.log.*")
+	if err != nil {
+		return err
+	}
+	if len(rotated) == 0 {
+		return fmt.Errorf("no rotated files in %s", dir)
+	}
+	lines := 0
+	for _, path := range append(rotated, dir+"app.log") {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.Size() > 8192 {
+			return fmt.Errorf("%s has %d bytes, more than the maximum size", path, info.Size())
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		lines += strings.Count(string(data), "\n")
+	}
+	if lines != entries {
+		return fmt.Errorf("found %d entries in %d files, want %d", lines, len(rotated)+1, entries)
+	}
+	return nil
+}
+
+//dailyRotationExample starts a logger with daily rotation and compression on a file written yesterday, which
+// is rotated to a compressed file named after that day at the first flush.
+func dailyRotationExample(dir string) error {
+	yesterday := time.Now().AddDate(0, 0, -1)
+	if err := ioutil.WriteFile(dir+"app.log", []byte("[INFO]  written yesterday\n"), 0644); err != nil {
+		return err
+	}
+	if err := os.Chtimes(dir+"app.log", yesterday, yesterday); err != nil {
+		return err
+	}
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithRotation(logWriter.Daily),
+		logger.WithCompression())
+	if err != nil {
+		return err
+	}
+	myLogger.Info("written today")
+	if err = myLogger.CloseLogger().Err(); err != nil {
+		return err
+	}
+	compressed, err := os.Open(dir + "app.log." + yesterday.Format("2006-01-02") + ".gz")
+	if err != nil {
+		return err
+	}
+	defer compressed.Close()
+	reader, err := gzip.NewReader(compressed)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	if string(data) != "[INFO]  written yesterday\n" {
+		return fmt.Errorf("unexpected rotated content %q", data)
+	}
+	return expectFile(dir+"app.log", []string{"written today"}, []string{"written yesterday"})
+}
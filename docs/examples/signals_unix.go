@@ -0,0 +1,57 @@
+//go:build unix
+
+package main
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"os"
+	"syscall"
+	"time"
+)
+
+//SIGUSR1 and SIGUSR2 only exist on Unix, so the example is only registered there.
+func init() {
+	examples = append(examples, example{"level-signal", levelSignalExample})
+}
+
+//levelSignalExample turns on debug logging with SIGUSR1, as an on-call engineer would with kill -USR1, and turns it
+// off again with SIGUSR2.
+func levelSignalExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir + "app.log"))
+	if err != nil {
+		return err
+	}
+	stop := myLogger.LevelOnSignal(nil, nil)
+	defer stop()
+	myLogger.Debug("debug before the signal")
+
+	if err = signalAndWait(syscall.SIGUSR1, func() bool { return myLogger.GetLevel() == logWriter.DebugLevel }); err != nil {
+		return err
+	}
+	myLogger.Debug("debug after SIGUSR1")
+	if err = signalAndWait(syscall.SIGUSR2, func() bool { return myLogger.GetLevel() == logWriter.InfoLevel }); err != nil {
+		return err
+	}
+	myLogger.Debug("debug after SIGUSR2")
+	myLogger.CloseLogger()
+	return expectFile(dir+"app.log",
+		[]string{"debug after SIGUSR1", "level changed from info to debug on user defined signal 1",
+			"level changed from debug to info on user defined signal 2"},
+		[]string{"before the signal", "after SIGUSR2"})
+}
+
+//This method sends the signal to the process and waits until done reports that it was handled.
+func signalAndWait(sig os.Signal, done func() bool) error {
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		return err
+	}
+	if err = process.Signal(sig); err != nil {
+		return err
+	}
+	for i := 0; i < 100 && !done(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil
+}
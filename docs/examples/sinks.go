@@ -0,0 +1,603 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"github.com/shyamgrover/go-lite-logger/logtest"
+	"github.com/shyamgrover/go-lite-logger/sinks/cloudwatch"
+	"github.com/shyamgrover/go-lite-logger/sinks/console"
+	"github.com/shyamgrover/go-lite-logger/sinks/elasticsearch"
+	"github.com/shyamgrover/go-lite-logger/sinks/fluentd"
+	"github.com/shyamgrover/go-lite-logger/sinks/gelf"
+	"github.com/shyamgrover/go-lite-logger/sinks/kafka"
+	"github.com/shyamgrover/go-lite-logger/sinks/loki"
+	"github.com/shyamgrover/go-lite-logger/sinks/mmap"
+	"github.com/shyamgrover/go-lite-logger/sinks/network"
+	"github.com/shyamgrover/go-lite-logger/sinks/syslog"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+//memorySink collects entries in memory.
+type memorySink struct {
+	lock     sync.Mutex
+	messages []string
+}
+
+func (s *memorySink) WriteEntry(entry logWriter.Entry) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.messages = append(s.messages, entry.Message())
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	return nil
+}
+
+//failingSink fails every write.
+type failingSink struct{}
+
+func (failingSink) WriteEntry(entry logWriter.Entry) error {
+	return errors.New("collector unreachable")
+}
+
+func (failingSink) Close() error {
+	return errors.New("collector unreachable")
+}
+
+//bufferSink is a byte sink writing to memory.
+type bufferSink struct {
+	bytes.Buffer
+}
+
+func (*bufferSink) Close() error {
+	return nil
+}
+
+//sinksExample fans entries out to the file and two sinks while a third one fails.
+func sinksExample(dir string) error {
+	memory := &memorySink{}
+	buffer := &bufferSink{}
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"),
+		logger.WithSink("memory", memory),
+		logger.WithSink("json", logWriter.NewWriterSink(buffer, logWriter.JSONFormatter{})),
+		logger.WithSink("broken", failingSink{}))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("to every sink")
+	myLogger.WithField("user", 42).Warn("with fields")
+	report := myLogger.CloseLogger()
+
+	if report.SinkErrors["broken"] == nil || len(report.SinkErrors) != 1 {
+		return fmt.Errorf("expected only the broken sink to fail, got %v", report.SinkErrors)
+	}
+	if report.EntriesFlushed != 6 || report.EntriesDropped != 2 {
+		return fmt.Errorf("unexpected close report %+v", report)
+	}
+	if strings.Join(memory.messages, "|") != "to every sink|with fields" {
+		return fmt.Errorf("unexpected entries in the memory sink %q", memory.messages)
+	}
+	if !strings.Contains(buffer.String(), `"msg":"with fields","user":42}`) {
+		return fmt.Errorf("unexpected JSON sink output %q", buffer.String())
+	}
+	return expectFile(dir+"app.log", []string{"to every sink", "with fields user=42"}, nil)
+}
+
+//consoleExample colors levels for the terminal while the file keeps plain lines.
+func consoleExample(dir string) error {
+	terminal := &bytes.Buffer{}
+	consoleSink := console.New(terminal)
+	consoleSink.SetColor(true)
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithSink("console", consoleSink))
+	if err != nil {
+		return err
+	}
+	myLogger.Error("disk full")
+	myLogger.WithField("path", "/var").Warn("almost full")
+	myLogger.CloseLogger()
+
+	output := terminal.String()
+	if !strings.Contains(output, "\x1b[31mERROR\x1b[0m disk full") ||
+		!strings.Contains(output, "\x1b[33mWARN \x1b[0m almost full \x1b[90mpath=\x1b[0m/var") {
+		return fmt.Errorf("unexpected console output %q", output)
+	}
+	return expectFile(dir+"app.log", []string{"[ERROR] ", "disk full"}, []string{"\x1b["})
+}
+
+//syslogExample sends entries to a syslog daemon, played here by a local UDP socket.
+func syslogExample(dir string) error {
+	daemon, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer daemon.Close()
+	syslogSink, err := syslog.New("udp", daemon.LocalAddr().String())
+	if err != nil {
+		return err
+	}
+	syslogSink.SetFacility(syslog.Local0)
+	syslogSink.SetAppName("billing")
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithSink("syslog", syslogSink))
+	if err != nil {
+		return err
+	}
+	myLogger.WithField("invoice", 17).Error("payment failed")
+	myLogger.CloseLogger()
+
+	packet := make([]byte, 2048)
+	daemon.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := daemon.ReadFrom(packet)
+	if err != nil {
+		return err
+	}
+	message := string(packet[:n])
+	// <131> is facility local0 (16) * 8 + severity err (3)
+	if !strings.HasPrefix(message, "<131>1 ") ||
+		!strings.HasSuffix(message, ` billing `+fmt.Sprint(os.Getpid())+` - [fields@32473 invoice="17"] payment failed`) {
+		return fmt.Errorf("unexpected syslog message %q", message)
+	}
+	return nil
+}
+
+//networkExample logs while the collector is down; the entries are sent once it comes up.
+func networkExample(dir string) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	collector := network.New("tcp", address, logWriter.JSONFormatter{})
+	collector.SetBackoff(10*time.Millisecond, 50*time.Millisecond)
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithSink("collector", collector))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("sent after the outage")
+	time.Sleep(100 * time.Millisecond)
+
+	if listener, err = net.Listen("tcp", address); err != nil {
+		return err
+	}
+	defer listener.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			received <- err.Error()
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		received <- line
+	}()
+	select {
+	case line := <-received:
+		if !strings.Contains(line, `"msg":"sent after the outage"`) {
+			return fmt.Errorf("unexpected line %q", line)
+		}
+	case <-time.After(5 * time.Second):
+		return fmt.Errorf("nothing received after the collector came up")
+	}
+	return myLogger.CloseLogger().Err()
+}
+
+//gelfExample sends a GELF message to a UDP socket standing in for Graylog and decodes it.
+func gelfExample(dir string) error {
+	graylog, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer graylog.Close()
+	gelfSink, err := gelf.NewUDPSink(graylog.LocalAddr().String())
+	if err != nil {
+		return err
+	}
+	gelfSink.SetHost("web-1")
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithSink("graylog", gelfSink))
+	if err != nil {
+		return err
+	}
+	myLogger.WithFields(logWriter.Fields{"user": 42, "id": "abc"}).Warn("slow request")
+	myLogger.CloseLogger()
+
+	packet := make([]byte, 8192)
+	graylog.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := graylog.ReadFrom(packet)
+	if err != nil {
+		return err
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(packet[:n]))
+	if err != nil {
+		return err
+	}
+	var message map[string]interface{}
+	if err = json.NewDecoder(reader).Decode(&message); err != nil {
+		return err
+	}
+	if message["version"] != "1.1" || message["host"] != "web-1" || message["short_message"] != "slow request" ||
+		message["level"] != 4.0 || message["_user"] != 42.0 || message["_field_id"] != "abc" {
+		return fmt.Errorf("unexpected GELF message %v", message)
+	}
+
+	//a chunk size without room for data falls back to the default instead of failing every message
+	if gelfSink, err = gelf.NewUDPSink(graylog.LocalAddr().String()); err != nil {
+		return err
+	}
+	defer gelfSink.Close()
+	gelfSink.SetChunkSize(12)
+	gelfSink.SetCompression(false)
+	if err = gelfSink.WriteEntry(logWriter.NewEntry(logWriter.InfoLevel, strings.Repeat("x", 100))); err != nil {
+		return err
+	}
+	if n, _, err = graylog.ReadFrom(packet); err != nil {
+		return err
+	}
+	message = nil
+	if err = json.Unmarshal(packet[:n], &message); err != nil {
+		return fmt.Errorf("unexpected GELF datagram %q: %v", packet[:n], err)
+	}
+	if host, _ := os.Hostname(); message["host"] != host {
+		return fmt.Errorf("GELF host %v, want %s", message["host"], host)
+	}
+	return nil
+}
+
+//lokiExample pushes entries to a stand-in for Loki's push API and checks the streams it receives.
+func lokiExample(dir string) error {
+	pushes := make(chan map[string]interface{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		pushes <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	lokiSink, err := loki.New(loki.Config{
+		URL:        server.URL + "/loki/api/v1/push",
+		Labels:     map[string]string{"app": "billing"},
+		LevelLabel: true,
+	})
+	if err != nil {
+		return err
+	}
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithSink("loki", lokiSink))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("first")
+	myLogger.WithField("user", 42).Error("second")
+	myLogger.CloseLogger()
+
+	body := <-pushes
+	streams, _ := body["streams"].([]interface{})
+	if len(streams) != 2 {
+		return fmt.Errorf("expected a stream per level, got %v", body)
+	}
+	second, _ := streams[1].(map[string]interface{})
+	labels, _ := second["stream"].(map[string]interface{})
+	values, _ := second["values"].([]interface{})
+	if labels["app"] != "billing" || labels["level"] != "error" || len(values) != 1 ||
+		fmt.Sprint(values[0].([]interface{})[1]) != "second user=42" {
+		return fmt.Errorf("unexpected stream %v", second)
+	}
+	return nil
+}
+
+//fluentdExample sends an event to a stand-in for a fluentd forward input, which acknowledges it.
+func fluentdExample(dir string) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		event := make([]byte, 4096)
+		n, _ := conn.Read(event)
+		event = event[:n]
+		received <- event
+		// the chunk id is a 24 character string following the "chunk" key of the options map
+		key := []byte("\xa5chunk\xb8")
+		if at := bytes.Index(event, key); at >= 0 && len(event) >= at+len(key)+24 {
+			chunk := event[at+len(key) : at+len(key)+24]
+			conn.Write(append([]byte("\x81\xa3ack\xb8"), chunk...))
+		}
+	}()
+
+	fluentdSink := fluentd.New(fluentd.Config{Address: listener.Addr().String(), Tag: "app.billing", RequireAck: true})
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithSink("fluentd", fluentdSink))
+	if err != nil {
+		return err
+	}
+	myLogger.WithField("invoice", 17).Error("payment failed")
+	if report := myLogger.CloseLogger(); report.Err() != nil || report.EntriesDropped != 0 {
+		return fmt.Errorf("unexpected close report %+v", report)
+	}
+	event := <-received
+	for _, wanted := range []string{"\x94\xabapp.billing\xd7\x00", "\xa7message\xaepayment failed", "\xa7invoice\x11"} {
+		if !bytes.Contains(event, []byte(wanted)) {
+			return fmt.Errorf("missing %q in event %q", wanted, event)
+		}
+	}
+	return nil
+}
+
+//recordingProducer records the messages it is asked to publish.
+type recordingProducer struct {
+	messages []string
+}
+
+func (p *recordingProducer) Produce(topic string, key []byte, value []byte) error {
+	p.messages = append(p.messages, topic+" "+string(key)+" "+string(value))
+	return nil
+}
+
+func (p *recordingProducer) Close() error {
+	return nil
+}
+
+//kafkaExample publishes errors to their own topic, keyed by tenant.
+func kafkaExample(dir string) error {
+	producer := &recordingProducer{}
+	kafkaSink, err := kafka.New(producer, kafka.Config{
+		Topic:       "logs",
+		LevelTopics: map[logWriter.Level]string{logWriter.ErrorLevel: "logs-errors"},
+		KeyFields:   []string{"tenant"},
+	})
+	if err != nil {
+		return err
+	}
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithSink("kafka", kafkaSink))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("unkeyed")
+	myLogger.WithField("tenant", "acme").Error("keyed")
+	myLogger.CloseLogger()
+
+	if len(producer.messages) != 2 || !strings.HasPrefix(producer.messages[0], "logs  {") ||
+		!strings.HasPrefix(producer.messages[1], `logs-errors acme {`) ||
+		!strings.Contains(producer.messages[1], `"msg":"keyed","tenant":"acme"}`) {
+		return fmt.Errorf("unexpected messages %q", producer.messages)
+	}
+	return nil
+}
+
+//fakeCloudWatch accepts every second call, alternating between throttling and rejecting the sequence token.
+type fakeCloudWatch struct {
+	calls  int
+	tokens []string
+	events []cloudwatch.Event
+}
+
+func (c *fakeCloudWatch) PutLogEvents(group string, stream string, events []cloudwatch.Event, sequenceToken *string) (*string, error) {
+	c.calls++
+	switch c.calls {
+	case 1:
+		return nil, fmt.Errorf("%w: rate exceeded", cloudwatch.ErrThrottled)
+	case 2:
+		expected := "expected"
+		return nil, &cloudwatch.InvalidSequenceTokenError{ExpectedToken: &expected}
+	}
+	c.tokens = append(c.tokens, *sequenceToken)
+	c.events = append(c.events, events...)
+	next := "next"
+	return &next, nil
+}
+
+//cloudWatchExample ships a batch to CloudWatch Logs through a client that throttles once and then rejects the
+// sequence token.
+func cloudWatchExample(dir string) error {
+	client := &fakeCloudWatch{}
+	cloudWatchSink, err := cloudwatch.New(client, cloudwatch.Config{Group: "/app/billing", Stream: "host-1"})
+	if err != nil {
+		return err
+	}
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithSink("cloudwatch", cloudWatchSink))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("first")
+	myLogger.WithField("invoice", 17).Error("second")
+	if report := myLogger.CloseLogger(); report.Err() != nil {
+		return report.Err()
+	}
+	if client.calls != 3 || len(client.tokens) != 1 || client.tokens[0] != "expected" || len(client.events) != 2 ||
+		!strings.Contains(client.events[1].Message, `"msg":"second","invoice":17}`) {
+		return fmt.Errorf("unexpected calls %d, tokens %q, events %+v", client.calls, client.tokens, client.events)
+	}
+	return nil
+}
+
+//elasticsearchExample indexes two entries into a daily index through a cluster that rejects the first request
+// and then one of the documents with 429.
+func elasticsearchExample(dir string) error {
+	var lock sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		lock.Lock()
+		requests = append(requests, string(body))
+		count := len(requests)
+		lock.Unlock()
+		switch count {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			fmt.Fprint(w, `{"errors":true,"items":[{"index":{"status":201}},{"index":{"status":429}}]}`)
+		default:
+			fmt.Fprint(w, `{"errors":false,"items":[{"index":{"status":201}}]}`)
+		}
+	}))
+	defer server.Close()
+
+	esSink, err := elasticsearch.New(elasticsearch.Config{
+		URL:     server.URL,
+		Index:   "app-logs-{2006.01.02}",
+		Backoff: time.Millisecond,
+	})
+	if err != nil {
+		return err
+	}
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithSink("elasticsearch", esSink))
+	if err != nil {
+		return err
+	}
+	myLogger.Info("first")
+	myLogger.WithField("invoice", 17).Error("second")
+	if report := myLogger.CloseLogger(); report.Err() != nil {
+		return report.Err()
+	}
+	index := `{"index":{"_index":"app-logs-` + time.Now().UTC().Format("2006.01.02") + `"}}`
+	if len(requests) != 3 || strings.Count(requests[1], index) != 2 || strings.Count(requests[2], index) != 1 ||
+		!strings.Contains(requests[2], `"invoice":17`) || !strings.Contains(requests[2], `"message":"second"`) ||
+		esSink.Dropped() != 0 {
+		return fmt.Errorf("unexpected requests %q", requests)
+	}
+	return nil
+}
+
+//mmapExample appends entries to a memory-mapped file through regions of one page, and resumes after the zero
+// bytes a crash leaves behind.
+func mmapExample(dir string) error {
+	path := dir + "app.mmap.log"
+	if err := ioutil.WriteFile(path, append([]byte("before the crash\n"), make([]byte, 8192)...), 0644); err != nil {
+		return err
+	}
+	mmapSink, err := mmap.Open(path, nil)
+	if err != nil {
+		return err
+	}
+	mmapSink.SetRegionSize(1)
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithSink("mmap", mmapSink))
+	if err != nil {
+		return err
+	}
+	payload := strings.Repeat("x", 100)
+	for i := 0; i < 200; i++ {
+		myLogger.Info("entry", i, payload)
+	}
+	if err = myLogger.Flush(); err != nil {
+		return err
+	}
+	if report := myLogger.CloseLogger(); report.Err() != nil {
+		return report.Err()
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 201 || lines[0] != "before the crash" || !strings.Contains(lines[200], "entry 199 x") ||
+		bytes.IndexByte(data, 0) >= 0 || int64(len(data)) != mmapSink.Size() {
+		return fmt.Errorf("unexpected file contents of %d bytes", len(data))
+	}
+	return nil
+}
+
+//recordingT collects the failures reported by the logtest assertions.
+type recordingT struct {
+	failures []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+//logtestExample checks what was logged with the in-memory sink of logtest, as a unit test would.
+func logtestExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir + "app.log"))
+	if err != nil {
+		return err
+	}
+	defer myLogger.CloseLogger()
+	sink := logtest.NewSink()
+	if err = myLogger.AddSink("test", sink); err != nil {
+		return err
+	}
+	myLogger.With("order_id", 42).Info("order created")
+	if err = myLogger.Flush(); err != nil {
+		return err
+	}
+
+	t := &recordingT{}
+	sink.AssertLogged(t, logWriter.InfoLevel, "order created")
+	sink.AssertField(t, "order created", "order_id", 42)
+	sink.AssertNotLogged(t, logWriter.ErrorLevel, "")
+	if len(t.failures) > 0 {
+		return fmt.Errorf("unexpected failures %v", t.failures)
+	}
+	sink.AssertLogged(t, logWriter.WarnLevel, "order created")
+	if len(t.failures) != 1 || !strings.Contains(t.failures[0], "[info] order created") {
+		return fmt.Errorf("expected one failure listing the entries, got %v", t.failures)
+	}
+	if last, ok := sink.LastEntry(); !ok || last.Message() != "order created" {
+		return fmt.Errorf("unexpected last entry %v", last.Message())
+	}
+	return nil
+}
+
+//handledHook tells that the worker took an entry; the worker buffers it before it reads the next tick.
+type handledHook chan struct{}
+
+func (hook handledHook) Levels() []logWriter.Level { return logWriter.AllLevels }
+
+func (hook handledHook) Fire(*logWriter.Entry) error {
+	hook <- struct{}{}
+	return nil
+}
+
+//clockExample drives the timer based flush and the timestamps of a logger with the manual clock of logtest.
+func clockExample(dir string) error {
+	path := dir + "clock.log"
+	clock := logtest.NewClock(time.Date(2024, 5, 27, 7, 32, 0, 0, time.UTC))
+	delivered := make(chan logWriter.Delivery, 1)
+	handled := make(handledHook, 2)
+	myLogger, err := logger.New(logger.WithFile(path), logger.WithUTC(), logger.WithClock(clock),
+		logger.WithFlushInterval(time.Minute), logger.WithHook(handled),
+		logger.WithDeliveryHook(func(delivery logWriter.Delivery) { delivered <- delivery }))
+	if err != nil {
+		return err
+	}
+	defer myLogger.CloseLogger()
+	myLogger.Info("started")
+	clock.Advance(30 * time.Second)
+	myLogger.Info("half a minute later")
+	<-handled //otherwise the tick may reach the worker before the entries
+	<-handled
+	clock.Advance(30 * time.Second)
+	select {
+	case delivery := <-delivered:
+		if !delivery.Time.Equal(clock.Now()) {
+			return fmt.Errorf("unexpected delivery time %v", delivery.Time)
+		}
+	case <-time.After(5 * time.Second):
+		return errors.New("the timer flush did not run")
+	}
+	return expectFile(path, []string{"2024/05/27 07:32:00.000000", "started",
+		"2024/05/27 07:32:30.000000", "half a minute later"}, nil)
+}
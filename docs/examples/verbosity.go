@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+//verbosityExample raises the level for a limited time with EnableDebugFor and for this file only with
+// SetLevelFor. The file is scoped rather than the package, whose path differs between the command and the test
+// binary.
+func verbosityExample(dir string) error {
+	myLogger, err := logger.CreateLogger(logWriter.InfoLevel, "app.log", dir, func() {})
+	if err != nil {
+		return err
+	}
+	myLogger.EnableDebugFor(100 * time.Millisecond)
+	myLogger.Debug("debug inside the window")
+	time.Sleep(200 * time.Millisecond)
+	myLogger.Debug("debug after the window")
+
+	myLogger.SetLevelFor("examples/verbosity.go", logWriter.DebugLevel)
+	myLogger.Debug("debug from a scoped file")
+	logger.SetDefault(myLogger)
+	logger.Debug("debug through the default logger")
+	logger.SetDefault(nil)
+	logScoped(myLogger.AddCallerSkip(1), "debug through a helper")
+	myLogger.ResetLevelFor("examples/verbosity.go")
+	myLogger.Debug("debug after the scope was removed")
+	myLogger.CloseLogger()
+
+	return expectFile(dir+"app.log",
+		[]string{"debug inside the window", "debug from a scoped file", "debug through the default logger",
+			"debug through a helper"},
+		[]string{"after the window", "after the scope"})
+}
+
+//levelHandlerExample turns on debug logging through the HTTP handler, as an operator would with curl.
+func levelHandlerExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir + "app.log"))
+	if err != nil {
+		return err
+	}
+	server := httptest.NewServer(myLogger.LevelHandler())
+	defer server.Close()
+	myLogger.Debug("debug before the change")
+
+	request, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader(`{"level":"debug"}`))
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	body, _ := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK || !strings.Contains(string(body), `"level":"debug"`) {
+		return fmt.Errorf("unexpected response %d %s", response.StatusCode, body)
+	}
+	myLogger.Debug("debug after the change")
+
+	request, _ = http.NewRequest(http.MethodPut, server.URL, strings.NewReader(`{"level":"loud"}`))
+	if response, err = http.DefaultClient.Do(request); err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusBadRequest || myLogger.GetLevel() != logWriter.DebugLevel {
+		return fmt.Errorf("unknown level answered with %d", response.StatusCode)
+	}
+	myLogger.CloseLogger()
+	return expectFile(dir+"app.log", []string{"debug after the change"}, []string{"before the change"})
+}
+
+//levelsExample logs at Trace level and recovers from Panic, whose entry is flushed before it panics.
+func levelsExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithLevel(logWriter.TraceLevel))
+	if err != nil {
+		return err
+	}
+	myLogger.Trace("entering checkout")
+	recovered := func() (recovered interface{}) {
+		defer func() {
+			recovered = recover()
+		}()
+		myLogger.Panicf("inventory for %s is negative", "sku-17")
+		return nil
+	}()
+	if recovered != "inventory for sku-17 is negative" {
+		return fmt.Errorf("unexpected panic value %v", recovered)
+	}
+	if err = expectFile(dir+"app.log", []string{"[PANIC] ", "inventory for sku-17 is negative"}, nil); err != nil {
+		return err
+	}
+	myLogger.SetLevel(logWriter.ErrorLevel)
+	myLogger.Trace("not logged")
+	myLogger.CloseLogger()
+	if !logWriter.ErrorLevel.Enables(logWriter.FatalLevel) || logWriter.ErrorLevel.Enables(logWriter.WarnLevel) {
+		return fmt.Errorf("unexpected level order")
+	}
+	return expectFile(dir+"app.log", []string{"[TRACE] ", "entering checkout"}, []string{"not logged"})
+}
+
+//levelFlagExample binds a level to a command line flag and round-trips levels through JSON.
+func levelFlagExample(dir string) error {
+	level := logWriter.InfoLevel
+	flags := flag.NewFlagSet("service", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	flags.Var(&level, "log-level", "least severe level logged")
+	if err := flags.Parse([]string{"-log-level", "debug"}); err != nil {
+		return err
+	}
+	if level != logWriter.DebugLevel || flags.Lookup("log-level").DefValue != "info" {
+		return fmt.Errorf("unexpected level %v", level)
+	}
+	if err := flags.Parse([]string{"-log-level", "loud"}); err == nil || !strings.Contains(err.Error(), "not a valid level") {
+		return fmt.Errorf("invalid level accepted: %v", err)
+	}
+
+	type settings struct {
+		Level logWriter.Level `json:"level"`
+	}
+	data, err := json.Marshal(settings{Level: logWriter.WarnLevel})
+	if err != nil {
+		return err
+	}
+	if string(data) != `{"level":"warning"}` {
+		return fmt.Errorf("unexpected JSON %s", data)
+	}
+	var decoded settings
+	if err = json.Unmarshal([]byte(`{"level":"error"}`), &decoded); err != nil || decoded.Level != logWriter.ErrorLevel {
+		return fmt.Errorf("unexpected level %v: %v", decoded.Level, err)
+	}
+	if err = json.Unmarshal([]byte(`{"level":4}`), &decoded); err != nil || decoded.Level != logWriter.TraceLevel {
+		return fmt.Errorf("numeric level decoded as %v: %v", decoded.Level, err)
+	}
+	if _, err = logWriter.Level(99).MarshalText(); err == nil {
+		return fmt.Errorf("unknown level marshalled")
+	}
+	return nil
+}
+
+//customLevelsExample registers an audit level filtered like errors and a verbose security level, and logs at
+// both with a logger at Error level.
+func customLevelsExample(dir string) error {
+	audit, err := logWriter.RegisterLevel(logWriter.CustomLevel{Name: "audit", Verbosity: 2})
+	if err != nil {
+		return err
+	}
+	security, err := logWriter.RegisterLevel(logWriter.CustomLevel{Name: "security", Verbosity: 5, Prefix: "[SEC]   "})
+	if err != nil {
+		return err
+	}
+	if _, err = logWriter.RegisterLevel(logWriter.CustomLevel{Name: "Warn"}); err == nil {
+		return fmt.Errorf("registered a level named like a built-in one")
+	}
+	if parsed, err := logWriter.ParseLevel("AUDIT"); err != nil || parsed != audit || audit.Base() != logWriter.ErrorLevel {
+		return fmt.Errorf("unexpected level %v, %v", parsed, err)
+	}
+
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithLevel(logWriter.ErrorLevel))
+	if err != nil {
+		return err
+	}
+	myLogger.Log(audit, "user", 42, "deleted invoice", 17)
+	myLogger.Logf(security, "token refreshed for %d", 42)
+	myLogger.SetLevel(security)
+	myLogger.Logf(security, "login from %s", "10.0.0.7")
+	myLogger.Debug("debug is as verbose as security")
+	myLogger.CloseLogger()
+	return expectFile(dir+"app.log", []string{
+		"[AUDIT] ", "user 42 deleted invoice 17", "[SEC]   ", "login from 10.0.0.7", "debug is as verbose",
+	}, []string{"token refreshed"})
+}
+
+//namedExample logs from two modules, with debug enabled for one of them only.
+func namedExample(dir string) error {
+	myLogger, err := logger.New(logger.WithFile(dir+"app.log"), logger.WithFormatter(logWriter.TextFormatter{}))
+	if err != nil {
+		return err
+	}
+	db, httpLogger := myLogger.Named("db"), myLogger.Named("http")
+	db.SetLevel(logWriter.DebugLevel)
+	db.Debug("query took 3ms")
+	db.Named("pool").Debug("connection reused")
+	httpLogger.Debug("not logged")
+	httpLogger.Info("listening")
+	myLogger.Debug("not logged either")
+	if myLogger.GetLevel() != logWriter.InfoLevel || myLogger.Named("db").GetLevel() != logWriter.DebugLevel {
+		return fmt.Errorf("unexpected levels")
+	}
+	myLogger.CloseLogger()
+	return expectFile(dir+"app.log", []string{
+		" query took 3ms module=db\n", " connection reused module=db.pool\n", " listening module=http\n",
+	}, []string{"not logged"})
+}
@@ -0,0 +1,65 @@
+// Package echolog provides Echo middleware logging requests through a logger in place of Echo's logger
+// middleware:
+//
+//	e := echo.New()
+//	echolog.Install(e, myLogger)
+//	e.Use(echolog.Middleware(myLogger, httplog.Config{}), middleware.Recover())
+//
+// Every request is logged as e.g. "GET /orders/:id 200" with method, route, path, status, latency_ms, bytes
+// and client_ip fields, at the level httplog.Config picks for its status.
+package echolog
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/shyamgrover/go-lite-logger/integrations/httplog"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"log"
+	"time"
+)
+
+// Install sends the output of e.Logger, which Echo and its middleware use for their own messages, through l at
+// Info level and the errors of the HTTP server at Error level.
+func Install(e *echo.Echo, l *logger.Logger) {
+	e.Logger.SetOutput(l.WriterLevel(logWriter.InfoLevel))
+	e.StdLogger = log.New(l.WriterLevel(logWriter.ErrorLevel), "", 0)
+}
+
+// Middleware returns middleware logging every request through l once the handler returned. An error returned
+// by the handler is passed to Echo's error handler first, so that the logged status is the one sent, and is
+// added as the "error" field. It is still returned to outer middleware; the error handler ignores it then,
+// because the response was already sent. The fields of the request's context are attached as by
+// logger.WithContext.
+func Middleware(l *logger.Logger, config httplog.Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			request, response := c.Request(), c.Response()
+			route := c.Path()
+			if len(route) == 0 {
+				route = request.URL.Path //no route matched
+			}
+			fields := logWriter.Fields{
+				"method":     request.Method,
+				"route":      route,
+				"path":       request.URL.Path,
+				"status":     response.Status,
+				"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+				"bytes":      response.Size,
+				"client_ip":  c.RealIP(),
+			}
+			if err != nil {
+				fields["error"] = err
+			}
+			config.Log(l.WithContext(request.Context()).WithFields(fields), response.Status,
+				fmt.Sprintf("%s %s %d", request.Method, route, response.Status))
+			return err
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Package expvarlog publishes the internals of loggers through expvar, for teams that do not run Prometheus.
+// The totals appear in the "golitelogger" map at /debug/vars, one entry per published logger:
+//
+//	expvarlog.Publish("app", myLogger)
+//	http.ListenAndServe("localhost:6060", nil) //serves /debug/vars
+//
+// gives
+//
+//	"golitelogger": {"app": {"entries": {"info": 12, "warning": 1}, "entries_written": 13, "entries_dropped": 0,
+//		"entries_sampled": 0, "bytes_written": 1043, "queue_depth": 0, "errors": {}, "flushes": 2,
+//		"flush_seconds_total": 0.00012, "flush_seconds_max": 0.00009, "last_flush": "2024-05-27T07:32:00Z"}}
+//
+// The counters are the ones of promlog: entries by level, written, dropped and sampled entries, bytes written,
+// queue depth and failures by operation, plus the number of writes of the buffer and the time they took.
+package expvarlog
+
+import (
+	"expvar"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"sync"
+	"time"
+)
+
+// MapName is the name of the expvar map the loggers are published in.
+const MapName = "golitelogger"
+
+//the published map, created by the first Publish because expvar panics on names published twice.
+var (
+	loggers     *expvar.Map
+	loggersOnce sync.Once
+)
+
+//flushes sums up the writes of the buffer of a logger.
+type flushes struct {
+	lock  sync.Mutex
+	count uint64        //successful writes
+	total time.Duration //time they took together
+	max   time.Duration //time the slowest took
+}
+
+// Publish adds the totals of l to the "golitelogger" map under name; a logger published before under the same
+// name is replaced. It watches the writes of l from now on, so call it right after creating the logger and
+// only once per logger.
+func Publish(name string, l *logger.Logger) {
+	loggersOnce.Do(func() {
+		loggers = expvar.NewMap(MapName)
+	})
+	f := &flushes{}
+	l.OnDelivery(func(delivery logWriter.Delivery) {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		f.count++
+		f.total += delivery.Duration
+		if delivery.Duration > f.max {
+			f.max = delivery.Duration
+		}
+	})
+	loggers.Set(name, expvar.Func(func() interface{} {
+		return snapshot(l, f)
+	}))
+}
+
+//Util method that returns the totals of the logger as they are rendered at /debug/vars.
+func snapshot(l *logger.Logger, f *flushes) map[string]interface{} {
+	stats := l.Stats()
+	entries := make(map[string]uint64, len(stats.EntriesByLevel))
+	for level, n := range stats.EntriesByLevel {
+		entries[level.String()] = n
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	values := map[string]interface{}{
+		"entries":             entries,
+		"entries_written":     stats.EntriesWritten,
+		"entries_dropped":     stats.EntriesDropped,
+		"entries_sampled":     stats.EntriesSampled,
+		"bytes_written":       stats.BytesFlushed,
+		"queue_depth":         stats.QueueDepth,
+		"errors":              stats.Failures,
+		"flushes":             f.count,
+		"flush_seconds_total": f.total.Seconds(),
+		"flush_seconds_max":   f.max.Seconds(),
+	}
+	if !stats.LastFlush.IsZero() {
+		values["last_flush"] = stats.LastFlush.UTC().Format(time.RFC3339Nano)
+	}
+	return values
+}
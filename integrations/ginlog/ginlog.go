@@ -0,0 +1,56 @@
+// Package ginlog provides Gin middleware logging requests through a logger in place of Gin's default logger.
+// Create the engine with gin.New rather than gin.Default, which adds Gin's own logger:
+//
+//	ginlog.Install(myLogger)
+//	router := gin.New()
+//	router.Use(ginlog.Middleware(myLogger, httplog.Config{}), gin.Recovery())
+//
+// Every request is logged as e.g. "GET /orders/:id 200" with method, route, path, status, latency_ms, bytes
+// and client_ip fields, at the level httplog.Config picks for its status.
+package ginlog
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/shyamgrover/go-lite-logger/integrations/httplog"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"time"
+)
+
+// Install sends Gin's own output, its route table and warnings in debug mode and the messages of gin.Recovery,
+// through l at Info and Error level. It sets gin.DefaultWriter and gin.DefaultErrorWriter, so it must be called
+// before the engine is created.
+func Install(l *logger.Logger) {
+	gin.DefaultWriter = l.WriterLevel(logWriter.InfoLevel)
+	gin.DefaultErrorWriter = l.WriterLevel(logWriter.ErrorLevel)
+}
+
+// Middleware returns a handler logging every request through l once the following handlers returned. Errors
+// attached with c.Error are added as the "errors" field and the fields of the request's context are attached as
+// by logger.WithContext.
+func Middleware(l *logger.Logger, config httplog.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if len(route) == 0 {
+			route = c.Request.URL.Path //no route matched
+		}
+		status := c.Writer.Status()
+		fields := logWriter.Fields{
+			"method":     c.Request.Method,
+			"route":      route,
+			"path":       c.Request.URL.Path,
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"bytes":      c.Writer.Size(),
+			"client_ip":  c.ClientIP(),
+		}
+		if len(c.Errors) > 0 {
+			fields["errors"] = c.Errors.String()
+		}
+		config.Log(l.WithContext(c.Request.Context()).WithFields(fields), status, fmt.Sprintf("%s %s %d", c.Request.Method, route, status))
+	}
+}
@@ -0,0 +1,104 @@
+// Package grpcinterceptor provides gRPC server interceptors logging one entry per RPC, with the method, peer,
+// status code and duration as fields, and recovering from panics in handlers. The fields of the RPC's context
+// are attached as by logger.WithContext, e.g. trace IDs:
+//
+//	server := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(grpcinterceptor.UnaryServerInterceptor(myLogger)),
+//		grpc.ChainStreamInterceptor(grpcinterceptor.StreamServerInterceptor(myLogger)),
+//	)
+package grpcinterceptor
+
+import (
+	"context"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"runtime/debug"
+	"time"
+)
+
+//levels maps status codes to the level of their entries, following the defaults of go-grpc-middleware: codes
+// pointing at a server problem are logged at Error, codes that may need attention at Warn and others at Info.
+var levels = map[codes.Code]logWriter.Level{
+	codes.Unknown:            logWriter.ErrorLevel,
+	codes.Unimplemented:      logWriter.ErrorLevel,
+	codes.Internal:           logWriter.ErrorLevel,
+	codes.DataLoss:           logWriter.ErrorLevel,
+	codes.DeadlineExceeded:   logWriter.WarnLevel,
+	codes.PermissionDenied:   logWriter.WarnLevel,
+	codes.ResourceExhausted:  logWriter.WarnLevel,
+	codes.FailedPrecondition: logWriter.WarnLevel,
+	codes.Aborted:            logWriter.WarnLevel,
+	codes.OutOfRange:         logWriter.WarnLevel,
+	codes.Unavailable:        logWriter.WarnLevel,
+}
+
+// UnaryServerInterceptor returns an interceptor logging every unary RPC through l. A panic in the handler is
+// logged at Error level with its stack trace and turned into an Internal error.
+func UnaryServerInterceptor(l *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (resp interface{}, err error) {
+		start := time.Now()
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = panicked(ctx, l, info.FullMethod, recovered)
+			}
+			logCall(ctx, l, info.FullMethod, start, err)
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns an interceptor logging every streaming RPC through l once the stream ended.
+// A panic in the handler is logged at Error level with its stack trace and turned into an Internal error.
+func StreamServerInterceptor(l *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) (err error) {
+		start := time.Now()
+		ctx := stream.Context()
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = panicked(ctx, l, info.FullMethod, recovered)
+			}
+			logCall(ctx, l, info.FullMethod, start, err)
+		}()
+		return handler(srv, stream)
+	}
+}
+
+//This method returns the logger with the fields describing an RPC.
+func callLogger(ctx context.Context, l *logger.Logger, method string) *logger.Logger {
+	fields := logWriter.Fields{"grpc.method": method}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields["peer"] = p.Addr.String()
+	}
+	return l.WithContext(ctx).WithFields(fields)
+}
+
+//This method logs a recovered panic with the stack trace and returns the error sent to the client.
+func panicked(ctx context.Context, l *logger.Logger, method string, recovered interface{}) error {
+	callLogger(ctx, l, method).WithField("stack", string(debug.Stack())).Error("panic in handler: ", recovered)
+	return status.Errorf(codes.Internal, "panic in handler: %v", recovered)
+}
+
+//This method logs a finished RPC at the level of its status code.
+func logCall(ctx context.Context, l *logger.Logger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	entry := callLogger(ctx, l, method).WithFields(logWriter.Fields{
+		"grpc.code":   code.String(),
+		"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+	})
+	message := fmt.Sprintf("%s %s", method, code)
+	if err != nil {
+		entry = entry.WithField("error", err)
+	}
+	level, ok := levels[code]
+	if !ok {
+		level = logWriter.InfoLevel
+	}
+	entry.Log(level, message)
+}
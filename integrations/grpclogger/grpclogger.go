@@ -0,0 +1,94 @@
+// Package grpclogger adapts a logger to grpclog.LoggerV2, so that gRPC's internal logging goes to the log file
+// and sinks instead of stderr:
+//
+//	grpclog.SetLoggerV2(grpclogger.New(myLogger.WithField("system", "grpc"), 0))
+//
+// SetLoggerV2 must be called before any gRPC function, typically in main or an init function.
+package grpclogger
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"google.golang.org/grpc/grpclog"
+)
+
+// Logger implements grpclog.LoggerV2. gRPC's info, warning and error messages are logged at the logger's Info,
+// Warn and Error levels and its fatal messages at Fatal level, after which the logger is closed and the process
+// exits with status 1.
+type Logger struct {
+	logger    *logger.Logger //logger the messages are logged through
+	verbosity int            //largest verbosity level V reports as enabled
+}
+
+var _ grpclog.LoggerV2 = (*Logger)(nil)
+
+// New returns an adapter logging through l. verbosity is the largest level for which V returns true, the
+// equivalent of GRPC_GO_LOG_VERBOSITY_LEVEL; 0 keeps gRPC's verbose messages out.
+func New(l *logger.Logger, verbosity int) *Logger {
+	return &Logger{logger: l, verbosity: verbosity}
+}
+
+// Info logs at Info level, with the arguments formatted like fmt.Sprint.
+func (g *Logger) Info(args ...interface{}) {
+	g.logger.Info(fmt.Sprint(args...))
+}
+
+// Infoln logs at Info level, with the arguments formatted like fmt.Sprintln.
+func (g *Logger) Infoln(args ...interface{}) {
+	g.logger.Info(args...)
+}
+
+// Infof logs at Info level, with the arguments formatted like fmt.Sprintf.
+func (g *Logger) Infof(format string, args ...interface{}) {
+	g.logger.Infof(format, args...)
+}
+
+// Warning logs at Warn level, with the arguments formatted like fmt.Sprint.
+func (g *Logger) Warning(args ...interface{}) {
+	g.logger.Warn(fmt.Sprint(args...))
+}
+
+// Warningln logs at Warn level, with the arguments formatted like fmt.Sprintln.
+func (g *Logger) Warningln(args ...interface{}) {
+	g.logger.Warn(args...)
+}
+
+// Warningf logs at Warn level, with the arguments formatted like fmt.Sprintf.
+func (g *Logger) Warningf(format string, args ...interface{}) {
+	g.logger.Warnf(format, args...)
+}
+
+// Error logs at Error level, with the arguments formatted like fmt.Sprint.
+func (g *Logger) Error(args ...interface{}) {
+	g.logger.Error(fmt.Sprint(args...))
+}
+
+// Errorln logs at Error level, with the arguments formatted like fmt.Sprintln.
+func (g *Logger) Errorln(args ...interface{}) {
+	g.logger.Error(args...)
+}
+
+// Errorf logs at Error level, with the arguments formatted like fmt.Sprintf.
+func (g *Logger) Errorf(format string, args ...interface{}) {
+	g.logger.Errorf(format, args...)
+}
+
+// Fatal logs at Fatal level, closes the logger and exits.
+func (g *Logger) Fatal(args ...interface{}) {
+	g.logger.Fatal(fmt.Sprint(args...))
+}
+
+// Fatalln logs at Fatal level, closes the logger and exits.
+func (g *Logger) Fatalln(args ...interface{}) {
+	g.logger.Fatal(args...)
+}
+
+// Fatalf logs at Fatal level, closes the logger and exits.
+func (g *Logger) Fatalf(format string, args ...interface{}) {
+	g.logger.Fatalf(format, args...)
+}
+
+// V reports whether verbosity level l is enabled.
+func (g *Logger) V(l int) bool {
+	return l <= g.verbosity
+}
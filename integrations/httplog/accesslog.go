@@ -0,0 +1,169 @@
+package httplog
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats of Config.Format writing the access log lines of the Apache web server, which log analytics tools
+// such as GoAccess or AWStats read.
+const (
+	// CommonLogFormat is the Common Log Format, e.g.
+	//	10.0.0.7 - alice [10/Oct/2020:13:55:36 -0700] "GET /orders?page=2 HTTP/1.1" 200 512
+	CommonLogFormat = `%h %l %u %t "%r" %>s %b`
+	// CombinedLogFormat is the Combined Log Format, the Common Log Format followed by the referer and user agent.
+	CombinedLogFormat = CommonLogFormat + ` "%{Referer}i" "%{User-Agent}i"`
+)
+
+//accessFormats are the names Config.Format accepts for the standard formats.
+var accessFormats = map[string]string{"common": CommonLogFormat, "combined": CombinedLogFormat}
+
+//accessPart is a directive of an access log format, or literal text if directive is 0.
+type accessPart struct {
+	directive byte   //directive character, 0 for literal text
+	arg       string //name given in braces, or the literal text
+}
+
+//accessRequest is what an access log line is written from.
+type accessRequest struct {
+	request *http.Request
+	header  http.Header   //header of the response
+	start   time.Time     //time the request arrived
+	latency time.Duration //time the handler took
+	status  int           //status of the response
+	bytes   int64         //body bytes of the response
+}
+
+//This method compiles an access log format of Apache's LogFormat syntax. The directives are %h (remote host),
+// %l (always -), %u (user of basic authentication), %t (time the request arrived), %r (request line), %s and %>s
+// (status), %b and %B (response bytes, - for none with %b), %D and %T (latency in microseconds and seconds),
+// %m (method), %U (path), %q (query string with its ?), %H (protocol), %{Name}i and %{Name}o (request and
+// response header) and %%. Unknown directives are written as they are.
+func compileAccessFormat(format string) []accessPart {
+	if named, ok := accessFormats[strings.ToLower(format)]; ok {
+		format = named
+	}
+	var parts []accessPart
+	var literal strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			literal.WriteByte(format[i])
+			continue
+		}
+		if format[i+1] == '%' {
+			literal.WriteByte('%')
+			i++
+			continue
+		}
+		j := i + 1
+		var arg string
+		if format[j] == '{' {
+			end := strings.IndexByte(format[j:], '}')
+			if end < 0 {
+				literal.WriteString(format[i:])
+				break
+			}
+			arg = format[j+1 : j+end]
+			j += end + 1
+		}
+		if j < len(format) && format[j] == '>' {
+			j++
+		}
+		if j == len(format) || !strings.ContainsRune("hlutrsbBDTmUqHio", rune(format[j])) {
+			literal.WriteByte('%')
+			continue
+		}
+		if literal.Len() > 0 {
+			parts = append(parts, accessPart{arg: literal.String()})
+			literal.Reset()
+		}
+		parts = append(parts, accessPart{directive: format[j], arg: arg})
+		i = j
+	}
+	if literal.Len() > 0 {
+		parts = append(parts, accessPart{arg: literal.String()})
+	}
+	return parts
+}
+
+//This method writes the access log line of a request in the compiled format.
+func formatAccess(parts []accessPart, a accessRequest) string {
+	var b strings.Builder
+	r := a.request
+	for _, part := range parts {
+		switch part.directive {
+		case 0:
+			b.WriteString(part.arg)
+		case 'h':
+			host, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				host = r.RemoteAddr
+			}
+			b.WriteString(orDash(host))
+		case 'l':
+			b.WriteByte('-')
+		case 'u':
+			user := ""
+			if r.URL.User != nil {
+				user = r.URL.User.Username()
+			} else if name, _, ok := r.BasicAuth(); ok {
+				user = name
+			}
+			b.WriteString(orDash(escapeAccess(user)))
+		case 't':
+			b.WriteString(a.start.Format("[02/Jan/2006:15:04:05 -0700]"))
+		case 'r':
+			b.WriteString(escapeAccess(r.Method + " " + r.URL.RequestURI() + " " + r.Proto))
+		case 's':
+			b.WriteString(strconv.Itoa(a.status))
+		case 'b':
+			if a.bytes == 0 {
+				b.WriteByte('-')
+			} else {
+				b.WriteString(strconv.FormatInt(a.bytes, 10))
+			}
+		case 'B':
+			b.WriteString(strconv.FormatInt(a.bytes, 10))
+		case 'D':
+			b.WriteString(strconv.FormatInt(a.latency.Microseconds(), 10))
+		case 'T':
+			b.WriteString(strconv.FormatInt(int64(a.latency/time.Second), 10))
+		case 'm':
+			b.WriteString(escapeAccess(r.Method))
+		case 'U':
+			b.WriteString(r.URL.EscapedPath())
+		case 'q':
+			if len(r.URL.RawQuery) > 0 {
+				b.WriteString("?" + escapeAccess(r.URL.RawQuery))
+			}
+		case 'H':
+			b.WriteString(escapeAccess(r.Proto))
+		case 'i':
+			b.WriteString(orDash(escapeAccess(r.Header.Get(part.arg))))
+		case 'o':
+			b.WriteString(orDash(escapeAccess(a.header.Get(part.arg))))
+		}
+	}
+	return b.String()
+}
+
+//This method returns s, or "-" if it is empty, the way Apache writes missing values.
+func orDash(s string) string {
+	if len(s) == 0 {
+		return "-"
+	}
+	return s
+}
+
+//This method escapes quotes, backslashes and control characters the way Apache does, so that a client cannot
+// forge fields or lines of the access log.
+func escapeAccess(s string) string {
+	if !strings.ContainsAny(s, "\"\\") && strings.IndexFunc(s, func(c rune) bool { return c < ' ' || c == 0x7f }) < 0 {
+		return s
+	}
+	quoted := strconv.QuoteToASCII(s)
+	return quoted[1 : len(quoted)-1]
+}
@@ -0,0 +1,132 @@
+// Package httplog provides net/http middleware logging one entry per request, with the method, path, status,
+// latency, response size and remote address as fields:
+//
+//	handler := httplog.New(myLogger, httplog.Config{})(mux)
+//	http.ListenAndServe(":8080", handler)
+//
+// logs e.g. "GET /orders 200" with method=GET path=/orders status=200 latency_ms=1.25 bytes=512
+// remote_addr=10.0.0.7:5123. With Config.Format the message is an access log line in the format of the Apache
+// web server instead, e.g. for analytics that read the Combined Log Format:
+//
+//	accessLogger, err := logger.New(logger.WithFile("access.log"), logger.WithFormatter(pattern)) // pattern "%m"
+//	handler := httplog.New(accessLogger, httplog.Config{Format: "combined"})(mux)
+package httplog
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Config configures the middleware.
+type Config struct {
+	Levels map[int]logWriter.Level //level per status class, 5 for 5xx and so on; Error for 5xx, Warn for 4xx and Info for others by default
+	Format string                  //access log format of the message: common, combined or an Apache LogFormat; "METHOD path status" if empty
+}
+
+//defaultLevels are the levels of status classes missing from Config.Levels.
+var defaultLevels = map[int]logWriter.Level{5: logWriter.ErrorLevel, 4: logWriter.WarnLevel}
+
+// New returns middleware logging every request handled by the wrapped handler through l once the handler
+// returned, with the fields of the request's context attached as by logger.WithContext, e.g. trace IDs. A
+// handler that never writes a status is logged with 200.
+func New(l *logger.Logger, config Config) func(http.Handler) http.Handler {
+	var access []accessPart
+	if len(config.Format) > 0 {
+		access = compileAccessFormat(config.Format)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+			latency := time.Since(start)
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			entry := l.WithContext(r.Context()).WithFields(logWriter.Fields{
+				"method":      r.Method,
+				"path":        r.URL.Path,
+				"status":      status,
+				"latency_ms":  float64(latency.Microseconds()) / 1000,
+				"bytes":       recorder.bytes,
+				"remote_addr": r.RemoteAddr,
+			})
+			message := fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, status)
+			if access != nil {
+				message = formatAccess(access, accessRequest{request: r, header: w.Header(), start: start,
+					latency: latency, status: status, bytes: recorder.bytes})
+			}
+			config.Log(entry, status, message)
+		})
+	}
+}
+
+// Level returns the level of a response with the given status.
+func (config Config) Level(status int) logWriter.Level {
+	if level, ok := config.Levels[status/100]; ok {
+		return level
+	}
+	if level, ok := defaultLevels[status/100]; ok {
+		return level
+	}
+	return logWriter.InfoLevel
+}
+
+// Log logs message through l at the level of a response with the given status. It is used by the middleware
+// of other frameworks to pick levels the same way.
+func (config Config) Log(l *logger.Logger, status int, message string) {
+	l.Log(config.Level(status), message)
+}
+
+//responseRecorder records the status and size of a response.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int   //status written, 0 before the header is written
+	bytes  int64 //body bytes written
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher if the wrapped writer does.
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker if the wrapped writer does.
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("httplog: %T does not implement http.Hijacker", r.ResponseWriter)
+	}
+	if r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
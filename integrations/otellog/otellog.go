@@ -0,0 +1,28 @@
+// Package otellog adds the trace and span IDs of an active OpenTelemetry span to the entries logged with a
+// context, so that backends such as Tempo or Jaeger can link logs and traces:
+//
+//	myLogger, err := logger.New(logger.WithFile("app.log"), logger.WithContextExtractor(otellog.TraceFields))
+//	...
+//	ctx, span := tracer.Start(ctx, "checkout")
+//	defer span.End()
+//	myLogger.WithContext(ctx).Info("order created") //has trace_id and span_id fields
+package otellog
+
+import (
+	"context"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceFields is a logger.ContextExtractor returning the trace_id and span_id fields, as hex strings, of the span
+// in ctx. It returns nil if ctx carries no valid span context.
+func TraceFields(ctx context.Context) logWriter.Fields {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+	return logWriter.Fields{
+		"trace_id": spanContext.TraceID().String(),
+		"span_id":  spanContext.SpanID().String(),
+	}
+}
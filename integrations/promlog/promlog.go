@@ -0,0 +1,95 @@
+// Package promlog exposes the internals of a logger as Prometheus metrics, so that the logging pipeline itself
+// can be watched and alerted on, e.g. when entries are dropped or writes fail:
+//
+//	prometheus.MustRegister(promlog.NewCollector(myLogger, "app"))
+//
+// The metrics carry a logger label with the given name, so that the collectors of several loggers can be
+// registered together:
+//
+//	lite_logger_entries_total{logger,level}     entries handed to the worker
+//	lite_logger_entries_written_total{logger}   entries written to the files and delivered to sinks
+//	lite_logger_entries_dropped_total{logger}   entries lost to failed flushes, the overflow policy or closing
+//	lite_logger_entries_sampled_total{logger}   entries left out by sampling
+//	lite_logger_bytes_written_total{logger}     bytes written to the log files
+//	lite_logger_queue_depth{logger}             entries waiting for the worker
+//	lite_logger_errors_total{logger,op}         failures reported to the error handler, e.g. op="write"
+//	lite_logger_flush_duration_seconds{logger}  histogram of the time writes of the buffer to a file took
+package promlog
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+)
+
+//prefix of the metric names.
+const namespace = "lite_logger"
+
+// Collector is a prometheus.Collector reporting the Stats of a logger. The counters are read from Stats when
+// Prometheus scrapes, so they cost nothing in between; the flush latency is observed on every write.
+type Collector struct {
+	logger   *logger.Logger       //logger whose totals are reported
+	entries  *prometheus.Desc     //entries_total
+	written  *prometheus.Desc     //entries_written_total
+	dropped  *prometheus.Desc     //entries_dropped_total
+	sampled  *prometheus.Desc     //entries_sampled_total
+	bytes    *prometheus.Desc     //bytes_written_total
+	queue    *prometheus.Desc     //queue_depth
+	errors   *prometheus.Desc     //errors_total
+	duration prometheus.Histogram //flush_duration_seconds
+}
+
+// NewCollector returns a collector for l, whose metrics get the label logger=name. It watches the writes of l
+// from now on, so create it right after the logger and only once per logger.
+func NewCollector(l *logger.Logger, name string) *Collector {
+	labels := prometheus.Labels{"logger": name}
+	desc := func(metric string, help string, variable ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", metric), help, variable, labels)
+	}
+	c := &Collector{
+		logger:  l,
+		entries: desc("entries_total", "Entries handed to the worker, by level.", "level"),
+		written: desc("entries_written_total", "Entries written to the log files and delivered to sinks."),
+		dropped: desc("entries_dropped_total", "Entries lost to failed flushes, the overflow policy or closing."),
+		sampled: desc("entries_sampled_total", "Entries left out by sampling."),
+		bytes:   desc("bytes_written_total", "Bytes written to the log files."),
+		queue:   desc("queue_depth", "Entries waiting on the channel for the worker."),
+		errors:  desc("errors_total", "Failures reported to the error handler, by operation.", "op"),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "flush_duration_seconds",
+			Help:        "Time writes of the buffer to a log file took, including retries.",
+			ConstLabels: labels,
+			Buckets:     prometheus.ExponentialBuckets(0.0001, 4, 10),
+		}),
+	}
+	l.OnDelivery(func(delivery logWriter.Delivery) {
+		c.duration.Observe(delivery.Duration.Seconds())
+	})
+	return c
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{c.entries, c.written, c.dropped, c.sampled, c.bytes, c.queue, c.errors} {
+		ch <- d
+	}
+	c.duration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.logger.Stats()
+	for level, n := range stats.EntriesByLevel {
+		ch <- prometheus.MustNewConstMetric(c.entries, prometheus.CounterValue, float64(n), level.String())
+	}
+	ch <- prometheus.MustNewConstMetric(c.written, prometheus.CounterValue, float64(stats.EntriesWritten))
+	ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(stats.EntriesDropped))
+	ch <- prometheus.MustNewConstMetric(c.sampled, prometheus.CounterValue, float64(stats.EntriesSampled))
+	ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.CounterValue, float64(stats.BytesFlushed))
+	ch <- prometheus.MustNewConstMetric(c.queue, prometheus.GaugeValue, float64(stats.QueueDepth))
+	for op, n := range stats.Failures {
+		ch <- prometheus.MustNewConstMetric(c.errors, prometheus.CounterValue, float64(n), op)
+	}
+	c.duration.Collect(ch)
+}
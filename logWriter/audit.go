@@ -0,0 +1,160 @@
+package logWriter
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+//annotations carrying the HMAC of a record at the end of its last line: a key of JSON objects and a key=value
+// pair otherwise.
+const (
+	auditJSONKey = `,"hmac":"`
+	auditTextKey = " hmac="
+)
+
+//length of a hex encoded HMAC-SHA256.
+const auditSumSize = 2 * sha256.Size
+
+//bytes of the end of a log file searched for the last HMAC when a worker resumes its chain.
+const auditResumeWindow = 1 << 16
+
+// AuditError reports where the HMAC chain of an audit log breaks: a record was changed, removed, inserted or
+// reordered, or the file was written with another key.
+type AuditError struct {
+	Record int    //1-based number of the first record that does not verify
+	Line   int    //1-based line the record ends on
+	Reason string //what is wrong with the record
+}
+
+func (e *AuditError) Error() string {
+	return fmt.Sprintf("audit: record %d (line %d): %s", e.Record, e.Line, e.Reason)
+}
+
+//auditor chains the records written by a worker: every record gets the HMAC of the previous record's HMAC and
+// the record itself.
+type auditor struct {
+	key    []byte //HMAC key
+	digest []byte //HMAC of the last record, nil before the first one
+}
+
+//This method returns the record with its HMAC appended to its last line and makes it the digest the next
+// record is chained to.
+func (a *auditor) seal(record []byte) []byte {
+	if len(record) == 0 || record[len(record)-1] != '\n' {
+		record = append(record[:len(record):len(record)], '\n')
+	}
+	a.digest = chainSum(a.key, a.digest, record)
+	sum := hex.EncodeToString(a.digest)
+	body := record[:len(record)-1]
+	sealed := make([]byte, 0, len(record)+len(auditJSONKey)+auditSumSize+2)
+	if bytes.HasPrefix(body, []byte("{")) && bytes.HasSuffix(body, []byte("}")) {
+		sealed = append(sealed, body[:len(body)-1]...)
+		sealed = append(sealed, auditJSONKey...)
+		sealed = append(sealed, sum...)
+		return append(sealed, "\"}\n"...)
+	}
+	sealed = append(sealed, body...)
+	sealed = append(sealed, auditTextKey...)
+	sealed = append(sealed, sum...)
+	return append(sealed, '\n')
+}
+
+//This method returns the HMAC of a record chained to the previous record's HMAC.
+func chainSum(key []byte, previous []byte, record []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(previous)
+	mac.Write(record)
+	return mac.Sum(nil)
+}
+
+//This method splits a line ending a record into the record's last line as it was sealed and its HMAC. It
+// returns false if the line carries no HMAC.
+func unseal(line []byte) (record []byte, sum []byte, ok bool) {
+	body := bytes.TrimSuffix(line, []byte("\n"))
+	if n := len(body) - auditSumSize - len(auditTextKey); n >= 0 && bytes.HasPrefix(body[n:], []byte(auditTextKey)) {
+		if sum, err := hex.DecodeString(string(body[n+len(auditTextKey):])); err == nil {
+			return append(body[:n:n], '\n'), sum, true
+		}
+	}
+	n := len(body) - auditSumSize - len(auditJSONKey) - 2
+	if n >= 0 && bytes.HasPrefix(body[n:], []byte(auditJSONKey)) && bytes.HasSuffix(body, []byte("\"}")) {
+		if sum, err := hex.DecodeString(string(body[n+len(auditJSONKey) : len(body)-2])); err == nil {
+			return append(body[:n:n], "}\n"...), sum, true
+		}
+	}
+	return nil, nil, false
+}
+
+// VerifyAudit checks the HMAC chain of a log file written in audit mode with the given key and returns the HMAC
+// of its last record. The chain of a file continues the one of the file rotated before it, so previous is nil
+// for the oldest file and the result of verifying the previous file otherwise; a logger appending to an existing
+// file continues its chain as well. Decompress rotated files and decrypt encrypted ones first, e.g. with
+// NewDecryptReader. The first broken record is reported as an *AuditError.
+func VerifyAudit(source io.Reader, key []byte, previous []byte) ([]byte, error) {
+	reader := bufio.NewReader(source)
+	digest := previous
+	var pending []byte
+	records, lines := 0, 0
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			lines++
+			if last, sum, ok := unseal(line); ok {
+				records++
+				expected := chainSum(key, digest, append(pending, last...))
+				if !hmac.Equal(sum, expected) {
+					return digest, &AuditError{Record: records, Line: lines, Reason: "HMAC does not match"}
+				}
+				digest, pending = expected, nil
+			} else {
+				pending = append(pending, line...)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return digest, err
+		}
+	}
+	if len(pending) > 0 {
+		return digest, &AuditError{Record: records + 1, Line: lines, Reason: "file ends with data that is not sealed"}
+	}
+	return digest, nil
+}
+
+//This method returns the HMAC of the last record in the file at path, so that a worker appending to the file
+// continues its chain, or nil if the file holds no sealed record. Encrypted files are decrypted with keys.
+func resumeDigest(path string, keys KeyProvider) []byte {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	var tail []byte
+	if keys != nil {
+		tail, _ = ioutil.ReadAll(NewDecryptReader(file, keys))
+	} else if info, err := file.Stat(); err == nil {
+		offset := info.Size() - auditResumeWindow
+		if offset < 0 {
+			offset = 0
+		}
+		tail = make([]byte, info.Size()-offset)
+		n, _ := file.ReadAt(tail, offset)
+		tail = tail[:n]
+	}
+	lines := bytes.SplitAfter(tail, []byte("\n"))
+	for i := len(lines) - 1; i >= 0; i-- {
+		if _, sum, ok := unseal(lines[i]); ok {
+			return sum
+		}
+	}
+	return nil
+}
@@ -0,0 +1,428 @@
+package logWriter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// BinaryFormatter writes every entry as a compact binary record instead of a line of text: the length of the
+// record as an unsigned varint, followed by a MessagePack array of the format version, the time in Unix
+// nanoseconds, the level, the sequence number, the message, the call site as "file:line", the fields as a map
+// in output order and the stack. Strings, integers, floats, booleans, byte slices and errors are stored as they
+// are, without fmt; other field values are stored as their text. Files written with it are read back with
+// BinaryReader, or rendered as text with cmd/logcat.
+type BinaryFormatter struct{}
+
+//version of the records written by BinaryFormatter.
+const binaryVersion = 1
+
+//elements of a record after its length.
+const binaryElements = 8
+
+//largest record BinaryReader accepts, so that a corrupt length does not allocate huge buffers.
+const maxBinaryRecord = 64 << 20
+
+// ErrCorruptRecord is returned by BinaryReader.Read for data that is not a record written by BinaryFormatter,
+// and wrapped for records cut off at the end of the file, e.g. by a crash.
+var ErrCorruptRecord = errors.New("corrupt binary log record")
+
+// Format implements Formatter.
+func (BinaryFormatter) Format(entry Entry) ([]byte, error) {
+	var body bytes.Buffer
+	body.WriteByte(0x90 | binaryElements)
+	appendMsgpackUint(&body, binaryVersion)
+	appendMsgpackInt(&body, entry.logged.UnixNano())
+	appendMsgpackInt(&body, int64(entry.level))
+	appendMsgpackUint(&body, entry.sequence)
+	var message bytes.Buffer
+	entry.appendMessage(&message)
+	appendMsgpackString(&body, message.Bytes())
+	if caller := entry.shortCaller(); caller != unknownCaller {
+		appendMsgpackString(&body, []byte(caller))
+	} else {
+		appendMsgpackString(&body, nil)
+	}
+	keys := entry.fieldKeys()
+	appendMsgpackHeader(&body, 0x80, 0xde, 0xdf, len(keys))
+	for _, key := range keys {
+		appendMsgpackString(&body, []byte(key))
+		appendMsgpackValue(&body, entry.fields[key])
+	}
+	appendMsgpackString(&body, []byte(entry.Stack()))
+
+	record := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+body.Len())
+	n := binary.PutUvarint(record, uint64(body.Len()))
+	return append(record[:n], body.Bytes()...), nil
+}
+
+//recordedSite is the call site and stack of an entry read back by a BinaryReader, which has no program counters.
+type recordedSite struct {
+	caller string //call site as "file:line", "" if none was recorded
+	stack  string //stack as Entry.Stack returns it
+}
+
+// BinaryReader reads the entries of a file written with BinaryFormatter. The entries it returns have the time,
+// level, sequence number, message, fields, call site and stack that were written, so that any Formatter renders
+// them as it would have rendered the original entries.
+type BinaryReader struct {
+	reader *bufio.Reader
+	body   []byte //buffer of the record being decoded
+}
+
+// NewBinaryReader returns a reader decoding the records read from r.
+func NewBinaryReader(r io.Reader) *BinaryReader {
+	return &BinaryReader{reader: bufio.NewReader(r)}
+}
+
+// Read returns the next entry. It returns io.EOF after the last complete record, and an error wrapping
+// ErrCorruptRecord for data that cannot be decoded, including a record cut off at the end.
+func (r *BinaryReader) Read() (Entry, error) {
+	length, err := binary.ReadUvarint(r.reader)
+	if err == io.EOF {
+		return Entry{}, io.EOF
+	}
+	if err != nil {
+		return Entry{}, fmt.Errorf("%w: reading the length: %v", ErrCorruptRecord, err)
+	}
+	if length > maxBinaryRecord {
+		return Entry{}, fmt.Errorf("%w: record of %d bytes", ErrCorruptRecord, length)
+	}
+	if uint64(cap(r.body)) < length {
+		r.body = make([]byte, length)
+	}
+	r.body = r.body[:length]
+	if _, err = io.ReadFull(r.reader, r.body); err != nil {
+		return Entry{}, fmt.Errorf("%w: record of %d bytes cut off", ErrCorruptRecord, length)
+	}
+	decoder := msgpackDecoder{data: r.body}
+	value := decoder.value()
+	elements, ok := value.([]interface{})
+	if decoder.err != nil || !ok || len(elements) < binaryElements {
+		return Entry{}, fmt.Errorf("%w: unexpected record layout", ErrCorruptRecord)
+	}
+	if version, _ := elements[0].(uint64); version != binaryVersion {
+		return Entry{}, fmt.Errorf("%w: unknown record version %v", ErrCorruptRecord, elements[0])
+	}
+	nanos := msgpackInt(elements[1])
+	level := msgpackInt(elements[2])
+	sequence, _ := elements[3].(uint64)
+	message, _ := elements[4].(string)
+	caller, _ := elements[5].(string)
+	fields, _ := elements[6].([]msgpackPair)
+	stack, _ := elements[7].(string)
+
+	entry := NewEntry(Level(level), message)
+	entry.logged = time.Unix(0, nanos)
+	entry.sequence = sequence
+	if len(caller) > 0 || len(stack) > 0 {
+		entry.recorded = &recordedSite{caller: caller, stack: stack}
+	}
+	if len(fields) > 0 {
+		entry.fields = make(Fields, len(fields))
+		entry.leading = make([]string, 0, len(fields))
+		for _, pair := range fields {
+			entry.fields[pair.key] = pair.value
+			entry.leading = append(entry.leading, pair.key)
+		}
+	}
+	return entry, nil
+}
+
+//This method appends a MessagePack header of the given length, in the fixed form with the given prefix for
+// lengths below 16, and with the 16 and 32 bit prefixes otherwise.
+func appendMsgpackHeader(b *bytes.Buffer, fixed byte, prefix16 byte, prefix32 byte, n int) {
+	var scratch [4]byte
+	switch {
+	case n < 16:
+		b.WriteByte(fixed | byte(n))
+	case n <= math.MaxUint16:
+		b.WriteByte(prefix16)
+		binary.BigEndian.PutUint16(scratch[:2], uint16(n))
+		b.Write(scratch[:2])
+	default:
+		b.WriteByte(prefix32)
+		binary.BigEndian.PutUint32(scratch[:], uint32(n))
+		b.Write(scratch[:])
+	}
+}
+
+//This method appends a MessagePack string.
+func appendMsgpackString(b *bytes.Buffer, s []byte) {
+	var scratch [4]byte
+	switch n := len(s); {
+	case n < 32:
+		b.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		b.WriteByte(0xd9)
+		b.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		b.WriteByte(0xda)
+		binary.BigEndian.PutUint16(scratch[:2], uint16(n))
+		b.Write(scratch[:2])
+	default:
+		b.WriteByte(0xdb)
+		binary.BigEndian.PutUint32(scratch[:], uint32(n))
+		b.Write(scratch[:])
+	}
+	b.Write(s)
+}
+
+//This method appends a MessagePack unsigned integer in its shortest form.
+func appendMsgpackUint(b *bytes.Buffer, v uint64) {
+	var scratch [8]byte
+	switch {
+	case v < 0x80:
+		b.WriteByte(byte(v))
+	case v <= math.MaxUint8:
+		b.WriteByte(0xcc)
+		b.WriteByte(byte(v))
+	case v <= math.MaxUint16:
+		b.WriteByte(0xcd)
+		binary.BigEndian.PutUint16(scratch[:2], uint16(v))
+		b.Write(scratch[:2])
+	case v <= math.MaxUint32:
+		b.WriteByte(0xce)
+		binary.BigEndian.PutUint32(scratch[:4], uint32(v))
+		b.Write(scratch[:4])
+	default:
+		b.WriteByte(0xcf)
+		binary.BigEndian.PutUint64(scratch[:], v)
+		b.Write(scratch[:])
+	}
+}
+
+//This method appends a MessagePack signed integer in its shortest form.
+func appendMsgpackInt(b *bytes.Buffer, v int64) {
+	var scratch [8]byte
+	switch {
+	case v >= 0:
+		appendMsgpackUint(b, uint64(v))
+	case v >= -32:
+		b.WriteByte(byte(v))
+	case v >= math.MinInt8:
+		b.WriteByte(0xd0)
+		b.WriteByte(byte(v))
+	case v >= math.MinInt16:
+		b.WriteByte(0xd1)
+		binary.BigEndian.PutUint16(scratch[:2], uint16(v))
+		b.Write(scratch[:2])
+	case v >= math.MinInt32:
+		b.WriteByte(0xd2)
+		binary.BigEndian.PutUint32(scratch[:4], uint32(v))
+		b.Write(scratch[:4])
+	default:
+		b.WriteByte(0xd3)
+		binary.BigEndian.PutUint64(scratch[:], uint64(v))
+		b.Write(scratch[:])
+	}
+}
+
+//This method appends a field value as MessagePack: strings, integers, floats, booleans, nil and byte slices as
+// they are, errors as their message and anything else as its unquoted text.
+func appendMsgpackValue(b *bytes.Buffer, value interface{}) {
+	var scratch [8]byte
+	switch v := value.(type) {
+	case nil:
+		b.WriteByte(0xc0)
+	case bool:
+		if v {
+			b.WriteByte(0xc3)
+		} else {
+			b.WriteByte(0xc2)
+		}
+	case string:
+		appendMsgpackString(b, []byte(v))
+	case int:
+		appendMsgpackInt(b, int64(v))
+	case int8:
+		appendMsgpackInt(b, int64(v))
+	case int16:
+		appendMsgpackInt(b, int64(v))
+	case int32:
+		appendMsgpackInt(b, int64(v))
+	case int64:
+		appendMsgpackInt(b, v)
+	case uint:
+		appendMsgpackUint(b, uint64(v))
+	case uint8:
+		appendMsgpackUint(b, uint64(v))
+	case uint16:
+		appendMsgpackUint(b, uint64(v))
+	case uint32:
+		appendMsgpackUint(b, uint64(v))
+	case uint64:
+		appendMsgpackUint(b, v)
+	case float32:
+		appendMsgpackFloat(b, float64(v), &scratch)
+	case float64:
+		appendMsgpackFloat(b, v, &scratch)
+	case []byte:
+		appendMsgpackBin(b, v)
+	case error:
+		appendMsgpackString(b, []byte(v.Error()))
+	default:
+		appendMsgpackString(b, []byte(fieldString(v)))
+	}
+}
+
+//This method appends a MessagePack float 64.
+func appendMsgpackFloat(b *bytes.Buffer, v float64, scratch *[8]byte) {
+	b.WriteByte(0xcb)
+	binary.BigEndian.PutUint64(scratch[:], math.Float64bits(v))
+	b.Write(scratch[:])
+}
+
+//This method appends MessagePack binary data.
+func appendMsgpackBin(b *bytes.Buffer, data []byte) {
+	var scratch [4]byte
+	switch n := len(data); {
+	case n <= math.MaxUint8:
+		b.WriteByte(0xc4)
+		b.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		b.WriteByte(0xc5)
+		binary.BigEndian.PutUint16(scratch[:2], uint16(n))
+		b.Write(scratch[:2])
+	default:
+		b.WriteByte(0xc6)
+		binary.BigEndian.PutUint32(scratch[:], uint32(n))
+		b.Write(scratch[:])
+	}
+	b.Write(data)
+}
+
+//msgpackPair is a key and value of a decoded MessagePack map, kept in the order they were written.
+type msgpackPair struct {
+	key   string
+	value interface{}
+}
+
+//msgpackDecoder decodes the MessagePack values written by BinaryFormatter. Positive integers decode as uint64,
+// negative ones as int64, maps as []msgpackPair with string keys, binary data as []byte. The first error is
+// kept in err and ends decoding.
+type msgpackDecoder struct {
+	data []byte
+	err  error
+}
+
+//This method takes n bytes off the data, or sets err if there are fewer.
+func (d *msgpackDecoder) take(n int) []byte {
+	if d.err != nil || n < 0 || n > len(d.data) {
+		if d.err == nil {
+			d.err = io.ErrUnexpectedEOF
+		}
+		return nil
+	}
+	taken := d.data[:n]
+	d.data = d.data[n:]
+	return taken
+}
+
+//This method reads a big endian unsigned integer of n bytes.
+func (d *msgpackDecoder) uint(n int) uint64 {
+	var v uint64
+	for _, c := range d.take(n) {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+//This method decodes the next value.
+func (d *msgpackDecoder) value() interface{} {
+	prefix := d.take(1)
+	if prefix == nil {
+		return nil
+	}
+	c := prefix[0]
+	switch {
+	case c < 0x80:
+		return uint64(c)
+	case c >= 0xe0:
+		return int64(int8(c))
+	case c&0xf0 == 0x80:
+		return d.pairs(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return d.array(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		return string(d.take(int(c & 0x1f)))
+	}
+	switch c {
+	case 0xc0:
+		return nil
+	case 0xc2:
+		return false
+	case 0xc3:
+		return true
+	case 0xc4, 0xc5, 0xc6:
+		return append([]byte(nil), d.take(int(d.uint(1<<(c-0xc4))))...)
+	case 0xca:
+		return float64(math.Float32frombits(uint32(d.uint(4))))
+	case 0xcb:
+		return math.Float64frombits(d.uint(8))
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return d.uint(1 << (c - 0xcc))
+	case 0xd0:
+		return int64(int8(d.uint(1)))
+	case 0xd1:
+		return int64(int16(d.uint(2)))
+	case 0xd2:
+		return int64(int32(d.uint(4)))
+	case 0xd3:
+		return int64(d.uint(8))
+	case 0xd9, 0xda, 0xdb:
+		return string(d.take(int(d.uint(1 << (c - 0xd9)))))
+	case 0xdc, 0xdd:
+		return d.array(int(d.uint(2 << (c - 0xdc))))
+	case 0xde, 0xdf:
+		return d.pairs(int(d.uint(2 << (c - 0xde))))
+	}
+	d.err = fmt.Errorf("unsupported MessagePack prefix 0x%02x", c)
+	return nil
+}
+
+//This method returns a decoded integer as an int64, whether it was written as a positive or a negative one.
+func msgpackInt(value interface{}) int64 {
+	switch v := value.(type) {
+	case uint64:
+		return int64(v)
+	case int64:
+		return v
+	}
+	return 0
+}
+
+//This method decodes the n elements of an array.
+func (d *msgpackDecoder) array(n int) []interface{} {
+	if n > len(d.data) {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	elements := make([]interface{}, 0, n)
+	for i := 0; i < n && d.err == nil; i++ {
+		elements = append(elements, d.value())
+	}
+	return elements
+}
+
+//This method decodes the n pairs of a map with string keys.
+func (d *msgpackDecoder) pairs(n int) []msgpackPair {
+	if n > len(d.data) {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	pairs := make([]msgpackPair, 0, n)
+	for i := 0; i < n && d.err == nil; i++ {
+		key, ok := d.value().(string)
+		if !ok && d.err == nil {
+			d.err = errors.New("map key is not a string")
+		}
+		pairs = append(pairs, msgpackPair{key: key, value: d.value()})
+	}
+	return pairs
+}
@@ -0,0 +1,44 @@
+package logWriter
+
+import "time"
+
+// Clock is the source of time of a worker: it stamps flushes, failures and deliveries and drives the timer based
+// flush. Tests can set WorkerOptions.Clock to a clock they advance by hand, e.g. the one of the logtest package,
+// to trigger timer flushes and check timestamps without sleeping.
+type Clock interface {
+	Now() time.Time                   //current time
+	NewTicker(d time.Duration) Ticker //ticker delivering ticks every d, like time.NewTicker
+}
+
+// Ticker delivers the ticks of a Clock, like time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time //channel the ticks are delivered on
+	Stop()               //turns the ticker off; no more ticks are sent after it returns
+}
+
+// SystemClock is the Clock of the time package, used by workers whose options do not set one.
+var SystemClock Clock = systemClock{}
+
+//systemClock reads the wall clock and ticks with time.Ticker.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+//systemTicker adapts a time.Ticker to Ticker.
+type systemTicker struct {
+	ticker *time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t systemTicker) Stop() {
+	t.ticker.Stop()
+}
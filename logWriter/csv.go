@@ -0,0 +1,80 @@
+package logWriter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"time"
+)
+
+// CSVFormatter writes every entry as one CSV record, for logs that are post-processed in spreadsheets or loaded
+// into data warehouses:
+//
+//	time,level,caller,message,<Columns...>,fields
+//
+// The values of the fields named in Columns get columns of their own, empty for entries without them, and the
+// other fields are flattened into the last column as key=value pairs in output order. Values are quoted as
+// RFC 4180 requires; stacks are not written. Header returns the header record, to be written once at the top of
+// a file by tools that need one. A Comma that CSV cannot use, e.g. a quote, makes Format fail.
+type CSVFormatter struct {
+	TimestampFormat string   //layout of the time, time.RFC3339Nano by default
+	UTC             bool     //write the time in UTC instead of local time
+	Columns         []string //fields written in columns of their own, in this order
+	Comma           rune     //field delimiter, ',' by default
+}
+
+// Header returns the header record naming the columns of the records Format writes.
+func (f CSVFormatter) Header() []byte {
+	record := append([]string{"time", "level", "caller", "message"}, f.Columns...)
+	header, _ := f.write(append(record, "fields"))
+	return header
+}
+
+// Format implements Formatter.
+func (f CSVFormatter) Format(entry Entry) ([]byte, error) {
+	layout := f.TimestampFormat
+	if len(layout) == 0 {
+		layout = time.RFC3339Nano
+	}
+	caller := entry.shortCaller()
+	if caller == unknownCaller {
+		caller = ""
+	}
+	record := make([]string, 0, len(f.Columns)+5)
+	record = append(record, FormatTime(entry.Time(), layout, f.UTC), entry.level.String(), caller, entry.Message())
+	for _, column := range f.Columns {
+		value, ok := entry.fields[column]
+		if !ok {
+			record = append(record, "")
+			continue
+		}
+		record = append(record, fieldString(value))
+	}
+	var rest strings.Builder
+	for _, key := range entry.fieldKeys() {
+		if containsKey(f.Columns, key) {
+			continue
+		}
+		if rest.Len() > 0 {
+			rest.WriteByte(' ')
+		}
+		rest.WriteString(key)
+		rest.WriteByte('=')
+		rest.WriteString(textValue(entry.fields[key]))
+	}
+	return f.write(append(record, rest.String()))
+}
+
+//This method encodes a record with the formatter's delimiter, and fails if the delimiter cannot be used.
+func (f CSVFormatter) write(record []string) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if f.Comma != 0 {
+		w.Comma = f.Comma
+	}
+	if err := w.Write(record); err != nil {
+		return nil, err
+	}
+	w.Flush()
+	return b.Bytes(), w.Error()
+}
@@ -0,0 +1,109 @@
+package logWriter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// CustomLevel describes a level registered with RegisterLevel, e.g. an AUDIT or SECURITY category.
+type CustomLevel struct {
+	Name      string //name returned by String and accepted by ParseLevel, e.g. "audit"
+	Verbosity uint32 //filtered like the built-in level of the same verbosity: 0 Panic, 1 Fatal, 2 Error, 3 Warn, 4 Info, 5 Debug, 6 Trace
+	Prefix    string //prefix of the text output, the upper case name in brackets by default, e.g. "[AUDIT] "
+	Color     string //ANSI escape sequence coloring the level in terminals, e.g. "\x1b[35m", none by default
+}
+
+//number of the first custom level; the numbers below are kept for built-in levels.
+const firstCustomLevel Level = 100
+
+//registry of custom levels.
+var levelRegistry = struct {
+	lock   sync.RWMutex
+	levels map[Level]CustomLevel
+	names  map[string]Level
+	next   Level
+}{levels: make(map[Level]CustomLevel), names: make(map[string]Level), next: firstCustomLevel}
+
+// RegisterLevel registers a custom level and returns it. Entries at the level are filtered like entries at the
+// built-in level of the same verbosity, so an audit level with the verbosity of ErrorLevel is logged whenever
+// errors are; it has no other behavior of that level, e.g. a level with the verbosity of FatalLevel does not
+// exit. It returns an error if the name is empty or names a built-in or registered level, or if the verbosity
+// is larger than that of TraceLevel. Levels are best registered at program start, before they are logged or
+// parsed from configuration.
+func RegisterLevel(level CustomLevel) (Level, error) {
+	name := strings.ToLower(level.Name)
+	if len(name) == 0 {
+		return 0, fmt.Errorf("level name must not be empty")
+	}
+	if _, err := ParseLevel(name); err == nil {
+		return 0, fmt.Errorf("level %q already exists", name)
+	}
+	if level.Verbosity > TraceLevel.verbosity() {
+		return 0, fmt.Errorf("verbosity %d of level %q is out of range", level.Verbosity, name)
+	}
+	level.Name = name
+	if len(level.Prefix) == 0 {
+		level.Prefix = fmt.Sprintf("%-8s", "["+strings.ToUpper(name)+"]")
+	}
+	levelRegistry.lock.Lock()
+	defer levelRegistry.lock.Unlock()
+	if _, exists := levelRegistry.names[name]; exists {
+		return 0, fmt.Errorf("level %q already exists", name)
+	}
+	registered := levelRegistry.next
+	levelRegistry.next++
+	levelRegistry.levels[registered] = level
+	levelRegistry.names[name] = registered
+	return registered, nil
+}
+
+//This method returns the registration of a custom level.
+func customLevel(level Level) (CustomLevel, bool) {
+	levelRegistry.lock.RLock()
+	defer levelRegistry.lock.RUnlock()
+	custom, ok := levelRegistry.levels[level]
+	return custom, ok
+}
+
+//This method returns the custom level registered under name.
+func parseCustomLevel(name string) (Level, bool) {
+	levelRegistry.lock.RLock()
+	defer levelRegistry.lock.RUnlock()
+	level, ok := levelRegistry.names[name]
+	return level, ok
+}
+
+// Base returns the built-in level whose verbosity the level has: the level itself for built-in levels and the
+// level a custom level is filtered like for custom levels. Sinks use it to map custom levels to severities.
+func (level Level) Base() Level {
+	if level < firstCustomLevel {
+		return level
+	}
+	verbosity := level.verbosity()
+	for _, builtIn := range AllLevels {
+		if builtIn.verbosity() == verbosity {
+			return builtIn
+		}
+	}
+	return level
+}
+
+// Prefix returns the prefix of the level in the text output, e.g. "[WARN]  ", padded to the same width for the
+// built-in levels.
+func (level Level) Prefix() string {
+	if prefix, ok := levelPrefixes[level]; ok {
+		return prefix
+	}
+	if custom, ok := customLevel(level); ok {
+		return custom.Prefix
+	}
+	return "[" + strings.ToUpper(level.String()) + "] "
+}
+
+// Color returns the ANSI escape sequence set for a custom level, or "" for built-in levels and custom levels
+// without a color.
+func (level Level) Color() string {
+	custom, _ := customLevel(level)
+	return custom.Color
+}
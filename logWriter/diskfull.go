@@ -0,0 +1,71 @@
+package logWriter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// DiskFullPolicy decides what a worker does when writing to the file fails because the disk is full (ENOSPC).
+// Other write failures are not affected.
+type DiskFullPolicy int
+
+const (
+	// DiskFullDiscard handles a full disk like any other write failure: the buffer goes to the fallback writer
+	// after enough failures, is kept if RetainOnFailure is set and is discarded otherwise. It is the default.
+	DiskFullDiscard DiskFullPolicy = iota
+	// DiskFullPause keeps the buffer and pauses logging: entries logged while the disk is full are dropped
+	// until a flush succeeds again, which is tried at every flush interval.
+	DiskFullPause
+	// DiskFullFallback sends the buffer to the fallback writer at the first failure, e.g. a file on another
+	// volume. Without a fallback writer it is DiskFullDiscard.
+	DiskFullFallback
+	// DiskFullPurge removes rotated files of the log file, the oldest first, until the buffer fits. If it still
+	// does not fit once none are left, the failure is handled like DiskFullDiscard.
+	DiskFullPurge
+)
+
+//This method writes the rest of data, from written on, after removing the oldest rotated file, and again after
+// removing the next one, until the write succeeds, fails for another reason than a full disk, or no rotated file
+// is left. It returns the bytes of data written in total and the error of the last write. It must be called with
+// lock held.
+func (w *Worker) purgeRotated(data []byte, written int, err error) (int, error) {
+	for IsDiskFull(err) {
+		oldest := w.oldestRotated()
+		if len(oldest) == 0 || os.Remove(oldest) != nil {
+			return written, err
+		}
+		var n int
+		n, err = w.fileRoot.Write(data[written:])
+		written += n
+	}
+	return written, err
+}
+
+//This method returns the oldest rotated file of the log file, e.g. app.log.2020-05-01.gz, or "" if there is
+// none. Files still being compressed are left alone.
+func (w *Worker) oldestRotated() string {
+	path := w.fileRoot.Name()
+	candidates, _ := filepath.Glob(path + ".*")
+	oldest := ""
+	var oldestInfo os.FileInfo
+	for _, candidate := range candidates {
+		if strings.HasSuffix(candidate, ".tmp") {
+			continue
+		}
+		info, err := os.Stat(candidate)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if oldestInfo == nil || info.ModTime().Before(oldestInfo.ModTime()) {
+			oldest, oldestInfo = candidate, info
+		}
+	}
+	return oldest
+}
+
+//This method counts an entry logged while logging is paused by DiskFullPause as dropped.
+func (w *Worker) dropPaused() {
+	atomic.AddUint64(&w.dropped, 1)
+}
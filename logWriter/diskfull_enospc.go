@@ -0,0 +1,13 @@
+//go:build !plan9
+
+package logWriter
+
+import (
+	"errors"
+	"syscall"
+)
+
+// IsDiskFull reports whether err, e.g. one passed to an ErrorHandler, is caused by a full disk.
+func IsDiskFull(err error) bool {
+	return errors.Is(err, syscall.ENOSPC)
+}
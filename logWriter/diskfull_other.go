@@ -0,0 +1,9 @@
+//go:build plan9
+
+package logWriter
+
+// IsDiskFull reports whether err, e.g. one passed to an ErrorHandler, is caused by a full disk. It is always
+// false on this system, which has no ENOSPC, so DiskFullPause, DiskFullFallback and DiskFullPurge never apply.
+func IsDiskFull(err error) bool {
+	return false
+}
@@ -0,0 +1,115 @@
+package logWriter
+
+import (
+	"bytes"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ECSVersion is the version of the Elastic Common Schema ECSFormatter writes, as its "ecs.version" value.
+const ECSVersion = "1.12.0"
+
+// ECSFormatter writes every entry as one JSON object per line in the layout of the Elastic Common Schema, e.g.
+//
+//	{"@timestamp":"2020-05-01T10:00:00.123Z","log.level":"info","message":"login ok","ecs.version":"1.12.0"}
+//
+// so that Filebeat or Elastic Agent ship it into the indices and dashboards of Kibana without an ingest
+// pipeline. The time is written in UTC with millisecond precision. The fields go under "labels", with dots in
+// their keys replaced by underscores as ECS requires, except for a field named "error", which is written as
+// "error.message"; a recorded stack becomes "error.stack_trace".
+type ECSFormatter struct {
+	Service  string //"service.name" of every entry, omitted if empty
+	Caller   bool   //add "log.origin" with the file name, line and function of the call site
+	Sequence bool   //add "event.sequence" with the entry's sequence number, see Entry.Sequence
+}
+
+//layout of "@timestamp", the ISO 8601 form Elasticsearch parses as a date.
+const ecsTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// Format implements Formatter.
+func (f ECSFormatter) Format(entry Entry) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(`{"@timestamp":`)
+	writeJSON(&b, entry.Time().UTC().Format(ecsTimeLayout))
+	b.WriteString(`,"log.level":`)
+	writeJSON(&b, entry.level.String())
+	b.WriteString(`,"message":`)
+	writeJSON(&b, entry.Message())
+	b.WriteString(`,"ecs.version":"` + ECSVersion + `"`)
+	if len(f.Service) > 0 {
+		b.WriteString(`,"service":{"name":`)
+		writeJSON(&b, f.Service)
+		b.WriteByte('}')
+	}
+	if f.Sequence {
+		b.WriteString(`,"event":{"sequence":`)
+		b.WriteString(strconv.FormatUint(entry.sequence, 10))
+		b.WriteByte('}')
+	}
+	if f.Caller {
+		writeECSOrigin(&b, entry)
+	}
+	fault, hasFault := entry.fields["error"]
+	if stack := entry.Stack(); hasFault || len(stack) > 0 {
+		b.WriteString(`,"error":{`)
+		if hasFault {
+			b.WriteString(`"message":`)
+			writeJSON(&b, fieldString(fault))
+			if len(stack) > 0 {
+				b.WriteByte(',')
+			}
+		}
+		if len(stack) > 0 {
+			b.WriteString(`"stack_trace":`)
+			writeJSON(&b, stack)
+		}
+		b.WriteByte('}')
+	}
+	labels := 0
+	for _, key := range entry.fieldKeys() {
+		if key == "error" {
+			continue
+		}
+		if labels == 0 {
+			b.WriteString(`,"labels":{`)
+		} else {
+			b.WriteByte(',')
+		}
+		labels++
+		writeJSON(&b, strings.Replace(key, ".", "_", -1))
+		b.WriteByte(':')
+		writeJSONValue(&b, entry.fields[key])
+	}
+	if labels > 0 {
+		b.WriteByte('}')
+	}
+	b.WriteString("}\n")
+	return b.Bytes(), nil
+}
+
+//This method writes the "log.origin" object of an entry in the layout of the ECS loggers of Elastic: the file
+// name, line and function of its call site, or the file and line recorded for an entry read by a BinaryReader.
+// Entries without a call site get none.
+func writeECSOrigin(b *bytes.Buffer, entry Entry) {
+	var file, function string
+	var line int
+	if frame, ok := entry.Caller(); ok {
+		file, line, function = filepath.Base(frame.File), frame.Line, frame.Function
+	} else if caller := entry.shortCaller(); caller != unknownCaller && strings.Contains(caller, ":") {
+		i := strings.LastIndexByte(caller, ':')
+		file = caller[:i]
+		line, _ = strconv.Atoi(caller[i+1:])
+	} else {
+		return
+	}
+	b.WriteString(`,"log.origin":{"file.name":`)
+	writeJSON(b, file)
+	b.WriteString(`,"file.line":`)
+	b.WriteString(strconv.Itoa(line))
+	if len(function) > 0 {
+		b.WriteString(`,"function":`)
+		writeJSON(b, function)
+	}
+	b.WriteByte('}')
+}
@@ -0,0 +1,158 @@
+package logWriter
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+//classicBuffers holds the buffers the classic text lines are encoded in. Entries themselves travel through the
+// channel by value and need no pooling.
+var classicBuffers = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+//largest buffer put back into classicBuffers, so that one huge entry does not pin its memory.
+const maxPooledBuffer = 64 * 1024
+
+//most fields that are sorted on the stack by appendFields; entries with more take the path of fieldText.
+const maxInlineFields = 8
+
+//This method writes an entry as a classic "[LEVEL]  date time file:line: message" text line, the layout of the
+// log handles, stamped with the time the entry was logged at rather than the time it is written, in the
+// worker's time layout, and naming the call site that logged the entry. A recorded stack follows on indented
+// lines. The line is encoded in a pooled buffer; plain string messages and the common field types are appended
+// without allocating, other messages and values go through fmt.
+func (w *Worker) encodeClassic(event Entry) {
+	b := classicBuffers.Get().(*bytes.Buffer)
+	b.WriteString(event.level.Prefix())
+	var scratch [64]byte
+	b.Write(appendTime(scratch[:0], event.Time(), w.timeLayout, w.utc))
+	b.WriteByte(' ')
+	b.WriteString(event.shortCaller())
+	b.WriteString(": ")
+	start := b.Len()
+	event.appendMessage(b)
+	if len(event.fields) > 0 {
+		b.WriteByte(' ')
+		event.appendFields(b)
+	} else if len(event.typed) > 0 {
+		event.appendTyped(b)
+	}
+	if w.sequence {
+		b.WriteString(" seq=")
+		b.Write(strconv.AppendUint(scratch[:0], event.sequence, 10))
+	}
+	if line := b.Bytes(); len(line) == start || line[len(line)-1] != '\n' {
+		b.WriteByte('\n')
+	}
+	writeStack(b, event)
+	w.writeEntry(b.Bytes(), event.sequence)
+	if b.Cap() <= maxPooledBuffer {
+		b.Reset()
+		classicBuffers.Put(b)
+	}
+}
+
+//This method appends the entry's message as Message returns it, without building the string first.
+func (entry Entry) appendMessage(b *bytes.Buffer) {
+	if text, ok := entry.message.(string); ok && len(entry.format) == 0 {
+		b.WriteString(text)
+		return
+	}
+	args, ok := entry.message.([]interface{})
+	if !ok {
+		args = []interface{}{entry.message}
+	}
+	if len(entry.format) > 0 {
+		fmt.Fprintf(b, entry.format, args...)
+		return
+	}
+	if len(args) == 1 {
+		if text, ok := args[0].(string); ok {
+			b.WriteString(text)
+			return
+		}
+	}
+	fmt.Fprintln(b, args...)
+	b.Truncate(b.Len() - 1)
+}
+
+//This method appends the entry's fields like fieldText. The keys of up to maxInlineFields fields are put in
+// output order on the stack and their strings, integers, booleans and errors appended as they are.
+func (entry Entry) appendFields(b *bytes.Buffer) {
+	if len(entry.fields) > maxInlineFields {
+		b.WriteString(entry.fieldText())
+		return
+	}
+	var ordered [maxInlineFields]string
+	keys := entry.inlineKeys(&ordered)
+	var scratch [32]byte
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		switch value := entry.fields[key].(type) {
+		case string:
+			writeTextValue(b, value)
+		case error:
+			writeTextValue(b, value.Error())
+		case int:
+			b.Write(strconv.AppendInt(scratch[:0], int64(value), 10))
+		case int64:
+			b.Write(strconv.AppendInt(scratch[:0], value, 10))
+		case uint64:
+			b.Write(strconv.AppendUint(scratch[:0], value, 10))
+		case bool:
+			b.Write(strconv.AppendBool(scratch[:0], value))
+		default:
+			b.WriteString(textValue(value))
+		}
+	}
+}
+
+//This method puts the keys of the entry's fields in output order into keys, like fieldKeys, and returns them. The
+// entry must have at most maxInlineFields fields.
+func (entry Entry) inlineKeys(keys *[maxInlineFields]string) []string {
+	n := 0
+	for _, key := range entry.leading {
+		if _, ok := entry.fields[key]; ok && !containsKey(keys[:n], key) {
+			keys[n] = key
+			n++
+		}
+	}
+	leading := n
+	for key := range entry.fields {
+		if containsKey(keys[:leading], key) {
+			continue
+		}
+		i := n
+		for ; i > leading && keys[i-1] > key; i-- {
+			keys[i] = keys[i-1]
+		}
+		keys[i] = key
+		n++
+	}
+	return keys[:n]
+}
+
+//This method reports whether keys holds key.
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+//This method appends a text field value, quoted if needed.
+func writeTextValue(b *bytes.Buffer, value string) {
+	if !needsQuoting(value) {
+		b.WriteString(value)
+		return
+	}
+	var scratch [64]byte
+	b.Write(strconv.AppendQuote(scratch[:0], value))
+}
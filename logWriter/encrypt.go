@@ -0,0 +1,166 @@
+package logWriter
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// KeyProvider supplies the AES keys of encrypted log files, 16, 24 or 32 bytes long for AES-128, AES-192 or
+// AES-256. Keys are identified by a number stored with every block, so that keys can be rotated and files
+// written with older keys can still be read.
+type KeyProvider interface {
+	// CurrentKey returns the key new blocks are encrypted with and its id.
+	CurrentKey() (id uint32, key []byte, err error)
+	// Key returns the key with the given id, to decrypt blocks.
+	Key(id uint32) ([]byte, error)
+}
+
+// StaticKey is a KeyProvider holding a single key with id 0.
+type StaticKey []byte
+
+// CurrentKey returns the key with id 0.
+func (key StaticKey) CurrentKey() (uint32, []byte, error) {
+	return 0, key, nil
+}
+
+// Key returns the key if id is 0.
+func (key StaticKey) Key(id uint32) ([]byte, error) {
+	if id != 0 {
+		return nil, fmt.Errorf("unknown key id %d", id)
+	}
+	return key, nil
+}
+
+//size of the header of an encrypted block: the length of the rest of the block and the key id, both big
+// endian uint32s, which are authenticated together with the ciphertext.
+const blockHeaderSize = 8
+
+//largest encrypted block a reader accepts, to fail on corrupt lengths instead of allocating them.
+const maxBlockSize = 1 << 30
+
+//encrypter seals the buffers of a worker into encrypted blocks: header, 12 byte random nonce and the AES-GCM
+// ciphertext of the buffer.
+type encrypter struct {
+	keys KeyProvider //supplies the current key
+	id   uint32      //id of the key aead was made with
+	aead cipher.AEAD //cipher of the current key, nil until the first block
+}
+
+//This method encrypts data into a block with the provider's current key.
+func (e *encrypter) seal(data []byte) ([]byte, error) {
+	id, key, err := e.keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if e.aead == nil || id != e.id {
+		if e.aead, err = newAEAD(key); err != nil {
+			return nil, err
+		}
+		e.id = id
+	}
+	nonceSize := e.aead.NonceSize()
+	block := make([]byte, blockHeaderSize+nonceSize, blockHeaderSize+nonceSize+len(data)+e.aead.Overhead())
+	binary.BigEndian.PutUint32(block[0:4], uint32(cap(block)-4))
+	binary.BigEndian.PutUint32(block[4:8], id)
+	if _, err = io.ReadFull(rand.Reader, block[blockHeaderSize:]); err != nil {
+		return nil, err
+	}
+	return e.aead.Seal(block, block[blockHeaderSize:], data, block[:blockHeaderSize]), nil
+}
+
+//This method returns the AES-GCM cipher of the key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+//This method returns the data to write for the buffered data: the data itself, or its encrypted block if the
+// worker encrypts its files.
+func (w *Worker) block(data []byte) ([]byte, error) {
+	if w.encrypter == nil {
+		return data, nil
+	}
+	block, err := w.encrypter.seal(data)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt: %w", err)
+	}
+	return block, nil
+}
+
+//decryptReader reads the plain text of an encrypted log file.
+type decryptReader struct {
+	source io.Reader              //the encrypted file
+	keys   KeyProvider            //supplies the keys of the blocks
+	aeads  map[uint32]cipher.AEAD //ciphers of the keys used so far
+	plain  []byte                 //decrypted data not read yet
+}
+
+// NewDecryptReader returns a reader of the plain text of a log file written with encryption, e.g.
+//
+//	file, _ := os.Open("app.log")
+//	io.Copy(os.Stdout, logWriter.NewDecryptReader(file, logWriter.StaticKey(key)))
+//
+// Read fails if a block cannot be authenticated, and with io.ErrUnexpectedEOF if the file ends in the middle of a
+// block, e.g. after a crash during a write.
+func NewDecryptReader(source io.Reader, keys KeyProvider) io.Reader {
+	return &decryptReader{source: source, keys: keys, aeads: make(map[uint32]cipher.AEAD)}
+}
+
+//This method returns decrypted data, decrypting the next block when the previous one was read.
+func (r *decryptReader) Read(p []byte) (int, error) {
+	for len(r.plain) == 0 {
+		if err := r.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+//This method reads and decrypts the next block. It returns io.EOF at the end of the file.
+func (r *decryptReader) next() error {
+	header := make([]byte, blockHeaderSize)
+	if _, err := io.ReadFull(r.source, header); err != nil {
+		return err
+	}
+	length := binary.BigEndian.Uint32(header[0:4])
+	id := binary.BigEndian.Uint32(header[4:8])
+	if length < blockHeaderSize-4 || length > maxBlockSize {
+		return fmt.Errorf("decrypt: invalid block length %d", length)
+	}
+	body := make([]byte, length-(blockHeaderSize-4))
+	if _, err := io.ReadFull(r.source, body); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	aead, ok := r.aeads[id]
+	if !ok {
+		key, err := r.keys.Key(id)
+		if err != nil {
+			return fmt.Errorf("decrypt: %w", err)
+		}
+		if aead, err = newAEAD(key); err != nil {
+			return fmt.Errorf("decrypt: %w", err)
+		}
+		r.aeads[id] = aead
+	}
+	if len(body) < aead.NonceSize() {
+		return fmt.Errorf("decrypt: block too short")
+	}
+	plain, err := aead.Open(nil, body[:aead.NonceSize()], body[aead.NonceSize():], header)
+	if err != nil {
+		return fmt.Errorf("decrypt: %w", err)
+	}
+	r.plain = plain
+	return nil
+}
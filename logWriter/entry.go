@@ -1,18 +1,47 @@
 package logWriter
 
-import ()
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
 
 type Entry struct {
 	level   Level       //Level the log entry was logged at: Debug, Info, Warn or Error.
-	message interface{} // Message passed to Debug, Info, Warn or Error
+	message interface{} // Message passed to Debug, Info, Warn or Error, a []interface{} holding their arguments
 	format  string      //format with which logger string would be printed
+	flushed chan error  //set for flush requests: the worker saves its buffer and sends the result on it
+	logged  time.Time   //time the entry was created, i.e. logged
+	caller  uintptr     //program counter of the call site that logged the entry, 0 if not captured
+	stack   []uintptr   //program counters of the stack of the call site, nil if not captured
+
+	destination string   //name of the route the entry should be written to, empty for the worker's own file
+	fileOnly    bool     //written to the file but not handed to the sinks, see SetFileOnly
+	sequence    uint64   //number assigned by the logger in the order entries are logged, 0 if not assigned
+	fields      Fields   //key/value pairs attached with WithFields, nil if there are none
+	leading     []string //keys of the fields written before the others, set for loggers returned by With
+
+	recorded *recordedSite //call site and stack of an entry read by a BinaryReader, nil otherwise
+	typed    []TypedField  //fields set with SetTypedFields, merged into fields by the worker
 }
 
+//text of shortCaller for entries whose call site is not known.
+const unknownCaller = "???:0"
+
+//callers caches the file:line text of call sites by program counter. There are as many as logging statements in
+// the program, so the cache stays small.
+var callers sync.Map
+
 //This method creates and returns new log entry having level and message args.
 func NewEntry(level Level, message interface{}) (entry Entry) {
 	return Entry{
 		level:   level,
-		message: message}
+		message: message,
+		logged:  time.Now()}
 }
 
 //This method creates and returns new formatted log entry having level, format and message args.
@@ -20,5 +49,159 @@ func NewFormattedEntry(level Level, format string, message interface{}) (entry E
 	return Entry{
 		level:   level,
 		message: message,
-		format:  format}
+		format:  format,
+		logged:  time.Now()}
+}
+
+//This method creates and returns an entry that asks the worker to flush its buffer. Because it travels through
+// the same channel as log entries, everything sent before it is flushed too. The result of the flush is sent
+// on done, which should be buffered.
+func NewFlushEntry(done chan error) (entry Entry) {
+	return Entry{flushed: done}
+}
+
+// IsFlush reports whether the entry is a flush request created by NewFlushEntry rather than a log entry.
+func (entry Entry) IsFlush() bool {
+	return entry.flushed != nil
+}
+
+// Time returns the time the entry was logged at, which formatters and sinks render instead of the time it is
+// written, so that queueing in the channel does not shift timestamps.
+func (entry Entry) Time() time.Time {
+	return entry.logged
+}
+
+// SetTime replaces the time the entry was logged at, e.g. with the time of the Clock of the logger.
+func (entry *Entry) SetTime(logged time.Time) {
+	entry.logged = logged
+}
+
+// SetCaller records the call site that logged the entry as a program counter returned by runtime.Callers.
+func (entry *Entry) SetCaller(pc uintptr) {
+	entry.caller = pc
+}
+
+// Caller returns the call site that logged the entry, and false if it was not recorded.
+func (entry Entry) Caller() (runtime.Frame, bool) {
+	if entry.caller == 0 {
+		return runtime.Frame{}, false
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{entry.caller}).Next()
+	return frame, frame.Line > 0
+}
+
+// CallerPC returns the program counter of the call site recorded with SetCaller, or 0 if none was recorded.
+func (entry Entry) CallerPC() uintptr {
+	return entry.caller
+}
+
+// SetStack records the stack of the call site that logged the entry as program counters returned by
+// runtime.Callers, the call site first.
+func (entry *Entry) SetStack(pcs []uintptr) {
+	entry.stack = pcs
+}
+
+// Stack returns the stack recorded with SetStack as text, one "function\n\tfile:line\n" pair per frame like
+// runtime/debug.Stack, or "" if none was recorded.
+func (entry Entry) Stack() string {
+	if len(entry.stack) == 0 {
+		if entry.recorded != nil {
+			return entry.recorded.stack
+		}
+		return ""
+	}
+	var b strings.Builder
+	frames := runtime.CallersFrames(entry.stack)
+	for {
+		frame, more := frames.Next()
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+		b.WriteByte('\n')
+		if !more {
+			return b.String()
+		}
+	}
+}
+
+//This method returns the call site that logged the entry as "file.go:line", or "???:0" if it is unknown, the
+// way log.Lshortfile writes it.
+func (entry Entry) shortCaller() string {
+	if entry.caller == 0 {
+		if entry.recorded != nil && len(entry.recorded.caller) > 0 {
+			return entry.recorded.caller
+		}
+		return unknownCaller
+	}
+	if text, ok := callers.Load(entry.caller); ok {
+		return text.(string)
+	}
+	text := unknownCaller
+	if frame, ok := entry.Caller(); ok {
+		text = filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+	}
+	callers.Store(entry.caller, text)
+	return text
+}
+
+// Level returns the level the entry was logged at.
+func (entry Entry) Level() Level {
+	return entry.level
+}
+
+// SetDestination sets the name of the route the entry should be written to. Entries whose destination has no
+// route are written to the worker's own file.
+func (entry *Entry) SetDestination(destination string) {
+	entry.destination = destination
+}
+
+// Destination returns the name of the route the entry should be written to.
+func (entry Entry) Destination() string {
+	return entry.destination
+}
+
+// SetFileOnly keeps the entry from the worker's sinks, so that it is only written to the file, e.g. for the
+// probe record of a self-check.
+func (entry *Entry) SetFileOnly() {
+	entry.fileOnly = true
+}
+
+// FileOnly reports whether the entry is kept from the worker's sinks, see SetFileOnly.
+func (entry Entry) FileOnly() bool {
+	return entry.fileOnly
+}
+
+// SetSequence sets the sequence number of the entry.
+func (entry *Entry) SetSequence(sequence uint64) {
+	entry.sequence = sequence
+}
+
+// Sequence returns the sequence number of the entry. Loggers number their entries 1, 2, 3... in the order they
+// are logged.
+func (entry Entry) Sequence() uint64 {
+	return entry.sequence
+}
+
+// Format returns the format of an entry logged with one of the formatting methods, e.g. Infof, and "" for
+// the others.
+func (entry Entry) Format() string {
+	return entry.format
+}
+
+// Message returns the entry's message as it is printed: the format applied to the arguments for formatted
+// entries and the arguments separated by spaces otherwise.
+func (entry Entry) Message() string {
+	if text, ok := entry.message.(string); ok && len(entry.format) == 0 {
+		return text
+	}
+	args, ok := entry.message.([]interface{})
+	if !ok {
+		args = []interface{}{entry.message}
+	}
+	if len(entry.format) > 0 {
+		return fmt.Sprintf(entry.format, args...)
+	}
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
 }
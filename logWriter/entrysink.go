@@ -0,0 +1,237 @@
+package logWriter
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// EntrySink receives log entries next to the worker's file, e.g. a console or a network service. Every sink
+// attached to a worker gets every entry, whatever its destination, and decides itself how to render it; use
+// NewWriterSink to attach a plain Sink with a Formatter. WriteEntry is only called from one goroutine at a time.
+type EntrySink interface {
+	WriteEntry(entry Entry) error
+	Close() error
+}
+
+// Flusher is implemented by entry sinks that buffer entries themselves. Flush is called when the worker
+// is flushed, e.g. by SelfCheck, after all earlier entries were handed to the sink.
+type Flusher interface {
+	Flush() error
+}
+
+//size of the queue between the worker and a sink.
+const sinkQueueSize = 1024
+
+//sinkRunner feeds one sink from its own goroutine, so that a slow or failing sink neither blocks the worker's
+// file nor the other sinks. Entries that do not fit in the queue are dropped, except for those of filtering
+// sinks, see sinkFilter.
+type sinkRunner struct {
+	delivered uint64        //entries written to the sink, first for 64-bit atomic alignment
+	dropped   uint64        //entries dropped because the queue was full or the sink failed
+	name      string        //name the sink was added under
+	sink      EntrySink     //the sink
+	filter    sinkFilter    //the sink if it only takes some entries, nil otherwise
+	queue     chan Entry    //entries waiting for the sink
+	stopped   chan struct{} //closed when the goroutine returns
+}
+
+//sinkFilter is implemented by entry sinks that only take some entries, such as those of NewLevelSink. The worker
+// asks them before queueing an entry, so that the entries they leave out neither take room in the queue nor
+// count as delivered, and waits for room in the queue for the few entries they take instead of dropping them.
+type sinkFilter interface {
+	takes(entry Entry) bool
+}
+
+//This method starts a runner for the sink.
+func newSinkRunner(name string, sink EntrySink, w *Worker) *sinkRunner {
+	runner := &sinkRunner{
+		name:    name,
+		sink:    sink,
+		queue:   make(chan Entry, sinkQueueSize),
+		stopped: make(chan struct{}),
+	}
+	runner.filter, _ = sink.(sinkFilter)
+	go runner.run(w)
+	return runner
+}
+
+//This method writes queued entries to the sink until the queue is closed. Flush requests are answered once the
+// entries queued before them were written.
+func (r *sinkRunner) run(w *Worker) {
+	defer close(r.stopped)
+	for entry := range r.queue {
+		if entry.flushed != nil {
+			var err error
+			if flusher, ok := r.sink.(Flusher); ok {
+				err = flusher.Flush()
+			}
+			entry.flushed <- err
+			continue
+		}
+		if err := r.sink.WriteEntry(entry); err != nil {
+			atomic.AddUint64(&r.dropped, 1)
+			err = fmt.Errorf("sink %s: %w", r.name, err)
+			w.lastError.Store(&FlushError{Err: err, FirstSequence: entry.sequence, LastSequence: entry.sequence,
+				Time: w.clock.Now()})
+			w.fail(err, OpSink, &entry)
+			continue
+		}
+		atomic.AddUint64(&r.delivered, 1)
+	}
+}
+
+//This method queues the entry for the sink, or drops it if the queue is full. Entries a filtering sink leaves
+// out are skipped, and those it takes wait for room in the queue.
+func (r *sinkRunner) send(entry Entry) {
+	if r.filter != nil {
+		if r.filter.takes(entry) {
+			r.queue <- entry
+		}
+		return
+	}
+	select {
+	case r.queue <- entry:
+	default:
+		atomic.AddUint64(&r.dropped, 1)
+	}
+}
+
+//This method waits until the entries queued so far were written to the sink and flushes the sink.
+func (r *sinkRunner) flush() error {
+	done := make(chan error, 1)
+	r.queue <- NewFlushEntry(done)
+	return <-done
+}
+
+//This method writes the queued entries, stops the runner and closes the sink.
+func (r *sinkRunner) close() error {
+	close(r.queue)
+	<-r.stopped
+	return r.sink.Close()
+}
+
+// AddSink attaches a sink that receives every entry the worker receives from now on, in addition to the
+// worker's file. The sink is closed together with the worker. It returns an error if a sink or route of that
+// name already exists.
+func (w *Worker) AddSink(name string, sink EntrySink) error {
+	w.routeLock.Lock()
+	defer w.routeLock.Unlock()
+	if _, exists := w.routes[name]; exists {
+		return fmt.Errorf("destination %q already exists", name)
+	}
+	for _, runner := range w.sinks {
+		if runner.name == name {
+			return fmt.Errorf("sink %q already exists", name)
+		}
+	}
+	w.sinks = append(w.sinks, newSinkRunner(name, sink, w))
+	return nil
+}
+
+// RemoveSink detaches the sink added under name, writes the entries queued for it and closes it. Its totals stay
+// part of Counters. It returns an error if there is no sink of that name, or the error of closing the sink.
+func (w *Worker) RemoveSink(name string) error {
+	w.routeLock.Lock()
+	var removed *sinkRunner
+	for i, runner := range w.sinks {
+		if runner.name == name {
+			removed = runner
+			w.sinks = append(w.sinks[:i:i], w.sinks[i+1:]...)
+			break
+		}
+	}
+	w.routeLock.Unlock()
+	if removed == nil {
+		return fmt.Errorf("no sink named %q", name)
+	}
+	err := removed.close()
+	atomic.AddUint64(&w.flushed, atomic.LoadUint64(&removed.delivered))
+	atomic.AddUint64(&w.dropped, atomic.LoadUint64(&removed.dropped))
+	return err
+}
+
+//This method hands the entry to every sink.
+func (w *Worker) fanOut(event Entry) {
+	base := w.base()
+	base.routeLock.RLock()
+	defer base.routeLock.RUnlock()
+	for _, runner := range base.sinks {
+		runner.send(event)
+	}
+}
+
+// WriterSink writes entries to a Sink, such as a file or a network connection, rendered by a Formatter.
+type WriterSink struct {
+	lock      sync.Mutex //guards writes against Close
+	sink      Sink       //destination of the formatted entries
+	formatter Formatter  //renders the entries
+}
+
+// NewWriterSink returns an entry sink writing to sink, rendered by formatter or by TextFormatter if formatter
+// is nil.
+func NewWriterSink(sink Sink, formatter Formatter) *WriterSink {
+	if formatter == nil {
+		formatter = TextFormatter{}
+	}
+	return &WriterSink{sink: sink, formatter: formatter}
+}
+
+// WriteEntry implements EntrySink.
+func (s *WriterSink) WriteEntry(entry Entry) error {
+	data, err := s.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, err = s.sink.Write(data)
+	return err
+}
+
+// Close implements EntrySink.
+func (s *WriterSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.sink.Close()
+}
+
+//levelSink hands the entries at a level or more severe to another sink.
+type levelSink struct {
+	next  EntrySink //sink receiving the entries
+	level Level     //least severe level passed on
+}
+
+// NewLevelSink returns an entry sink handing next only the entries at level or more severe, e.g. to show just the
+// problems on a console. Flushing and closing it flushes and closes next. Attached to a worker, the other entries
+// are left out before they are queued for the sink and are not counted as delivered; the entries it takes are
+// never dropped for a full queue, the worker waits for the sink instead.
+func NewLevelSink(next EntrySink, level Level) EntrySink {
+	return &levelSink{next: next, level: level}
+}
+
+// WriteEntry implements EntrySink.
+func (s *levelSink) WriteEntry(entry Entry) error {
+	if !s.takes(entry) {
+		return nil
+	}
+	return s.next.WriteEntry(entry)
+}
+
+//This method reports whether the entry is at the sink's level or more severe.
+func (s *levelSink) takes(entry Entry) bool {
+	return s.level.Enables(entry.level)
+}
+
+// Flush implements Flusher.
+func (s *levelSink) Flush() error {
+	if flusher, ok := s.next.(Flusher); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// Close implements EntrySink.
+func (s *levelSink) Close() error {
+	return s.next.Close()
+}
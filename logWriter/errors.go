@@ -0,0 +1,50 @@
+package logWriter
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrorHandler is told about every failure of a worker, next to its error callback. Op names what failed, see
+// OpWrite and the other Op constants, and entry is the entry that was lost when a single one was, e.g. one that
+// could not be formatted, nil otherwise. A failed write of the buffer is reported as a *FlushError naming the
+// sequence numbers of the lost entries. The handler runs on the worker's goroutine, so it must not wait for the
+// logger it belongs to.
+type ErrorHandler func(err error, op string, entry *Entry)
+
+// Operations reported to an ErrorHandler.
+const (
+	OpWrite    = "write"    //writing the buffer to the file
+	OpFormat   = "format"   //formatting an entry
+	OpRotate   = "rotate"   //rotating the file
+	OpCompress = "compress" //compressing a rotated file
+	OpSink     = "sink"     //writing an entry to a sink
+	OpReopen   = "reopen"   //reopening the file, e.g. on SIGHUP
+	OpReload   = "reload"   //reloading a watched config file, see Logger.WatchConfig
+	OpLock     = "lock"     //locking the file for a write, see WorkerOptions.FileLock
+	OpSync     = "sync"     //committing the file to stable storage, see WorkerOptions.Sync
+)
+
+// ErrFileMissing is the error, wrapped in a *FlushError, of a write that failed because the log file was
+// removed, e.g. by a cleanup job. It can be told apart from other write failures with errors.Is.
+var ErrFileMissing = errors.New("log file does not exist")
+
+// FlushError is recorded when the worker fails to write its buffer to the file. The entries of the failed
+// buffer are identified by their sequence numbers (see Entry.Sequence), so that an application can tell
+// exactly which messages were affected and log them again if needed.
+type FlushError struct {
+	Err           error     //underlying write error
+	FirstSequence uint64    //sequence number of the first entry in the failed buffer
+	LastSequence  uint64    //sequence number of the last entry in the failed buffer
+	Time          time.Time //time of the failure
+}
+
+func (e *FlushError) Error() string {
+	return fmt.Sprintf("flush of entries %d-%d failed: %v", e.FirstSequence, e.LastSequence, e.Err)
+}
+
+// Unwrap returns the underlying write error.
+func (e *FlushError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,54 @@
+package logWriter_test
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"os"
+)
+
+//stdout is a Sink writing to standard output that is not closed with the sink using it.
+type stdout struct{}
+
+//This method writes p to standard output.
+func (stdout) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
+
+//This method does nothing; standard output stays open.
+func (stdout) Close() error {
+	return nil
+}
+
+func ExampleParsePattern() {
+	formatter, err := logWriter.ParsePattern("%-7L [%f{user}] %m")
+	if err != nil {
+		panic(err)
+	}
+	entry := logWriter.NewEntry(logWriter.WarnLevel, "password expires soon")
+	entry.SetFields(logWriter.Fields{"user": "alice"})
+	line, err := formatter.Format(entry)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Print(string(line))
+	// Output: WARNING [alice] password expires soon
+}
+
+func ExampleNewLevelSink() {
+	formatter, err := logWriter.ParsePattern("%L: %m")
+	if err != nil {
+		panic(err)
+	}
+	sink := logWriter.NewLevelSink(logWriter.NewWriterSink(stdout{}, formatter), logWriter.ErrorLevel)
+	for _, entry := range []logWriter.Entry{
+		logWriter.NewEntry(logWriter.InfoLevel, "request handled"),
+		logWriter.NewEntry(logWriter.ErrorLevel, "request failed"),
+		logWriter.NewEntry(logWriter.DebugLevel, "cache miss"),
+	} {
+		if err := sink.WriteEntry(entry); err != nil {
+			panic(err)
+		}
+	}
+	sink.Close()
+	// Output: ERROR: request failed
+}
@@ -0,0 +1,151 @@
+package logWriter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fields are key/value pairs attached to an entry, e.g. the user a request was made for. The text output
+// appends them to the message as key=value pairs and the JSON output adds them as keys of the object.
+type Fields map[string]interface{}
+
+// SetFields sets the fields of the entry. The entry keeps the map, so it must not be modified afterwards.
+func (entry *Entry) SetFields(fields Fields) {
+	entry.fields = fields
+}
+
+// Fields returns the fields of the entry. The map must not be modified.
+func (entry Entry) Fields() Fields {
+	return entry.fields
+}
+
+// SetLeadingKeys sets the keys of the fields written before the others, in the given order; the remaining
+// fields follow in key order. Keys without a field are ignored. The entry keeps the slice, so it must not be
+// modified afterwards.
+func (entry *Entry) SetLeadingKeys(keys []string) {
+	entry.leading = keys
+}
+
+// Field is a key of an entry's fields with its value in text form.
+type Field struct {
+	Key   string //the key
+	Value string //the value as written in text output, quoted if needed
+}
+
+// SortedFields returns the entry's fields in output order, the leading keys first and the others in key order,
+// with their values in the form the text output uses, for sinks that render entries themselves.
+func (entry Entry) SortedFields() []Field {
+	keys := entry.fieldKeys()
+	sorted := make([]Field, len(keys))
+	for i, key := range keys {
+		sorted[i] = Field{Key: key, Value: textValue(entry.fields[key])}
+	}
+	return sorted
+}
+
+//This method returns the keys of the fields in sorted order, so that the output is stable.
+func (fields Fields) keys() []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+//This method returns the keys of the entry's fields in output order: the leading keys that have a field, in
+// their order, followed by the other keys in sorted order.
+func (entry Entry) fieldKeys() []string {
+	if len(entry.leading) == 0 {
+		return entry.fields.keys()
+	}
+	keys := make([]string, 0, len(entry.fields))
+	seen := make(map[string]bool, len(entry.leading))
+	for _, key := range entry.leading {
+		if _, ok := entry.fields[key]; ok && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range entry.fields.keys() {
+		if !seen[key] {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+//This method renders the entry's fields as space separated key=value pairs in output order, quoting values
+// that are empty or contain spaces, quotes or control characters. Errors are rendered with their message.
+func (entry Entry) fieldText() string {
+	var b strings.Builder
+	for i, key := range entry.fieldKeys() {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(textValue(entry.fields[key]))
+	}
+	return b.String()
+}
+
+//This method returns a field value as written in text output, quoted if needed.
+func textValue(value interface{}) string {
+	text := fieldString(value)
+	if needsQuoting(text) {
+		return strconv.Quote(text)
+	}
+	return text
+}
+
+//This method returns the string form of a field value.
+func fieldString(value interface{}) string {
+	if err, ok := value.(error); ok {
+		return err.Error()
+	}
+	return fmt.Sprint(value)
+}
+
+//This method reports whether a text field value must be quoted to be read back unambiguously.
+func needsQuoting(value string) bool {
+	if len(value) == 0 {
+		return true
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '"' || r == '=' || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorChain is the chain of an error and the errors it wraps, outermost first, each as its own part of the
+// message: the chain of fmt.Errorf("load config: %w", fmt.Errorf("read app.yaml: %w", fs.ErrNotExist)) is
+// ["load config", "read app.yaml", "file does not exist"], so that the root cause is the last element. The
+// JSON output writes it as an array, the text output as the parts joined by " -> ".
+type ErrorChain []string
+
+// NewErrorChain returns the chain of err, following errors.Unwrap. Errors wrapping several errors, such as
+// those of errors.Join, end the chain.
+func NewErrorChain(err error) ErrorChain {
+	var chain ErrorChain
+	for err != nil {
+		message := err.Error()
+		next := errors.Unwrap(err)
+		if next != nil {
+			message = strings.TrimSuffix(message, ": "+next.Error())
+		}
+		chain = append(chain, message)
+		err = next
+	}
+	return chain
+}
+
+// String returns the parts of the chain joined by " -> ".
+func (chain ErrorChain) String() string {
+	return strings.Join(chain, " -> ")
+}
@@ -0,0 +1,25 @@
+package logWriter
+
+import (
+	"os"
+)
+
+// DefaultFileMode is the mode log files are created with unless WorkerOptions say otherwise.
+const DefaultFileMode os.FileMode = 0644
+
+// OpenFile opens the file at path for appending, creating it with the given mode if it does not exist. Unlike
+// os.OpenFile, a created file gets exactly that mode, whatever the process' umask.
+func OpenFile(path string, mode os.FileMode) (*os.File, error) {
+	_, statErr := os.Stat(path)
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, mode)
+	if err != nil {
+		return nil, err
+	}
+	if os.IsNotExist(statErr) {
+		if err = file.Chmod(mode); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	return file, nil
+}
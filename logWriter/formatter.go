@@ -0,0 +1,161 @@
+package logWriter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatter renders an entry as the bytes written to the log file, including the trailing newline. A worker
+// without a formatter writes the classic "[LEVEL]  date time file:line: message" text lines.
+type Formatter interface {
+	Format(entry Entry) ([]byte, error)
+}
+
+// JSONFormatter writes every entry as one JSON object per line, e.g.
+//
+//	{"time":"2020-05-01T10:00:00.123456Z","level":"info","msg":"login ok","user":42}
+//
+// which log shippers such as Filebeat or Fluent Bit can forward without parsing. Fields follow the fixed keys
+// in output order, see SortedFields; a field named like a fixed key is written as "fields.<key>" so that it does not shadow it.
+// An entry with a recorded stack gets a "stack" key.
+type JSONFormatter struct {
+	TimestampFormat string //layout of the "time" value, time.RFC3339Nano by default; Unix layouts give a number
+	UTC             bool   //write the time in UTC instead of local time
+	Caller          bool   //add a "caller" key naming the call site, e.g. "handler.go:42"
+	Sequence        bool   //add a "seq" key with the entry's sequence number, see Entry.Sequence
+}
+
+//keys written by JSONFormatter for every entry.
+var jsonFixedKeys = map[string]bool{"time": true, "level": true, "msg": true}
+
+// Format implements Formatter.
+func (f JSONFormatter) Format(entry Entry) ([]byte, error) {
+	layout := f.TimestampFormat
+	if len(layout) == 0 {
+		layout = time.RFC3339Nano
+	}
+	var b bytes.Buffer
+	b.WriteString(`{"time":`)
+	if isUnixLayout(layout) {
+		b.WriteString(FormatTime(entry.Time(), layout, f.UTC))
+	} else {
+		writeJSON(&b, FormatTime(entry.Time(), layout, f.UTC))
+	}
+	b.WriteString(`,"level":`)
+	writeJSON(&b, entry.level.String())
+	if f.Sequence {
+		b.WriteString(`,"seq":`)
+		b.WriteString(strconv.FormatUint(entry.sequence, 10))
+	}
+	b.WriteString(`,"msg":`)
+	writeJSON(&b, entry.Message())
+	if f.Caller {
+		b.WriteString(`,"caller":`)
+		writeJSON(&b, entry.shortCaller())
+	}
+	if stack := entry.Stack(); len(stack) > 0 {
+		b.WriteString(`,"stack":`)
+		writeJSON(&b, stack)
+	}
+	for _, key := range entry.fieldKeys() {
+		name := key
+		if jsonFixedKeys[key] || f.Caller && key == "caller" || f.Sequence && key == "seq" ||
+			key == "stack" && len(entry.stack) > 0 {
+			name = "fields." + key
+		}
+		b.WriteByte(',')
+		writeJSON(&b, name)
+		b.WriteByte(':')
+		writeJSONValue(&b, entry.fields[key])
+	}
+	b.WriteString("}\n")
+	return b.Bytes(), nil
+}
+
+//This method writes a string as a JSON string.
+func writeJSON(b *bytes.Buffer, s string) {
+	data, _ := json.Marshal(s)
+	b.Write(data)
+}
+
+//This method writes a field value as JSON. Errors are written as their message and values that cannot be
+// marshalled, e.g. channels, as their fmt representation, so that a single odd field never loses the entry.
+func writeJSONValue(b *bytes.Buffer, value interface{}) {
+	if err, ok := value.(error); ok {
+		if _, isMarshaler := value.(json.Marshaler); !isMarshaler {
+			writeJSON(b, err.Error())
+			return
+		}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		writeJSON(b, fmt.Sprint(value))
+		return
+	}
+	b.Write(data)
+}
+
+// TextFormatter writes entries as "[LEVEL]  date time message key=value..." lines, the layout of the default
+// file output without its file:line part unless Caller is set. It is the formatter used for sinks that are
+// given none.
+type TextFormatter struct {
+	TimestampFormat string //layout of the timestamp, "2006/01/02 15:04:05.000000" by default
+	UTC             bool   //write the time in UTC instead of local time
+	Caller          bool   //write the call site before the message as "file:line: ", like the default file output
+	Sequence        bool   //append the entry's sequence number as seq=N
+}
+
+//prefixes of the text output, padded to the same width.
+var levelPrefixes = map[Level]string{
+	PanicLevel: "[PANIC] ",
+	FatalLevel: "[FATAL] ",
+	ErrorLevel: "[ERROR] ",
+	WarnLevel:  "[WARN]  ",
+	InfoLevel:  "[INFO]  ",
+	DebugLevel: "[DEBUG] ",
+	TraceLevel: "[TRACE] ",
+}
+
+//This method writes the stack recorded for the entry, if any, with every line indented by a tab so that it is
+// not mistaken for entries.
+func writeStack(b *bytes.Buffer, entry Entry) {
+	stack := entry.Stack()
+	for len(stack) > 0 {
+		i := strings.IndexByte(stack, '\n')
+		b.WriteByte('\t')
+		b.WriteString(stack[:i+1])
+		stack = stack[i+1:]
+	}
+}
+
+// Format implements Formatter.
+func (f TextFormatter) Format(entry Entry) ([]byte, error) {
+	layout := f.TimestampFormat
+	if len(layout) == 0 {
+		layout = "2006/01/02 15:04:05.000000"
+	}
+	var b bytes.Buffer
+	b.WriteString(entry.level.Prefix())
+	b.WriteString(FormatTime(entry.Time(), layout, f.UTC))
+	b.WriteByte(' ')
+	if f.Caller {
+		b.WriteString(entry.shortCaller())
+		b.WriteString(": ")
+	}
+	b.WriteString(entry.Message())
+	if len(entry.fields) > 0 {
+		b.WriteByte(' ')
+		b.WriteString(entry.fieldText())
+	}
+	if f.Sequence {
+		b.WriteString(" seq=")
+		b.WriteString(strconv.FormatUint(entry.sequence, 10))
+	}
+	b.WriteByte('\n')
+	writeStack(&b, entry)
+	return b.Bytes(), nil
+}
@@ -0,0 +1,70 @@
+package logWriter
+
+import "fmt"
+
+// SyncPolicy decides when a worker commits its file to stable storage with fsync. Written entries sit in the page
+// cache of the operating system until it writes them back, and are lost if the machine loses power meanwhile;
+// syncing closes that window at the cost of waiting for the disk. Failed syncs are reported with OpSync.
+type SyncPolicy int
+
+const (
+	// SyncNever leaves writing back to the operating system. It is the default.
+	SyncNever SyncPolicy = iota
+	// SyncInterval syncs the file after a flush once WorkerOptions.SyncInterval has passed since the last sync,
+	// and on the flush timer, so entries are committed within the longer of the sync and the flush interval.
+	SyncInterval
+	// SyncEveryFlush syncs the file after every flush of the buffer.
+	SyncEveryFlush
+	// SyncOnError flushes the buffer and syncs the file right after writing an Error, Fatal or Panic entry, so
+	// that the entries explaining a crash survive it. Other entries are left to the operating system.
+	SyncOnError
+)
+
+//This method records that a flush wrote to the file and syncs it as the sync policy says. It must be called with
+// lock held, after a successful write.
+func (w *Worker) wrote() {
+	w.unsynced = true
+	switch w.syncPolicy {
+	case SyncEveryFlush:
+		w.syncFile()
+	case SyncInterval:
+		w.syncIfDue()
+	}
+}
+
+//This method syncs the file if the sync interval passed since the last sync. It must be called with lock held.
+func (w *Worker) syncIfDue() {
+	if w.unsynced && w.clock.Now().Sub(w.lastSync) >= w.syncInterval {
+		w.syncFile()
+	}
+}
+
+//This method writes the buffer of the worker, or of the worker whose file a shard shares, and syncs the file, as
+// SyncOnError does after a severe entry.
+func (w *Worker) flushAndSync() {
+	target := w
+	if w.into != nil {
+		target = w.into
+	}
+	target.lock.Lock()
+	defer target.lock.Unlock()
+	if _, err := target.save(); err != nil {
+		target.fail(target.flushError(), OpWrite, nil)
+		return
+	}
+	target.syncFile()
+}
+
+//This method commits the file to stable storage if entries were written since the last sync. It must be called
+// with lock held.
+func (w *Worker) syncFile() {
+	if !w.unsynced {
+		return
+	}
+	if err := w.fileRoot.Sync(); err != nil {
+		w.fail(fmt.Errorf("syncing %s: %w", w.fileRoot.Name(), err), OpSync, nil)
+		return
+	}
+	w.unsynced = false
+	w.lastSync = w.clock.Now()
+}
@@ -0,0 +1,119 @@
+package logWriter
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Hook is run by the worker for every entry at one of its levels before the entry is formatted, written or
+// handed to the sinks, so that it can enrich the entry, e.g. with deployment metadata, count it, or veto it.
+// Hooks run on the worker's goroutine, one entry at a time and in the order they were added, so they must be
+// fast and must not wait for the logger.
+type Hook interface {
+	// Levels returns the levels the hook runs for, AllLevels for all of them.
+	Levels() []Level
+	// Fire is called with the entry, which it may modify. Returning ErrVeto drops the entry; any other error is
+	// reported to the error callback with OpHook and the entry is still written.
+	Fire(entry *Entry) error
+}
+
+// ErrVeto is returned by a Hook to drop the entry it was given. Vetoed entries are not counted as dropped.
+var ErrVeto = errors.New("entry vetoed by hook")
+
+// OpHook is the operation reported to an ErrorHandler when a Hook fails.
+const OpHook = "hook"
+
+//hooks holds the hooks of a worker. The worker loads the current slice without locking; AddHook replaces it
+// under lock.
+type hooks struct {
+	lock       sync.Mutex   //serializes AddHook and OnDelivery
+	hooks      atomic.Value //[]Hook
+	deliveries atomic.Value //[]DeliveryHook
+}
+
+// Delivery describes a buffer of entries that was written to a worker's file.
+type Delivery struct {
+	File          string        //name of the file
+	Entries       uint64        //entries in the buffer
+	Bytes         int           //bytes written
+	FirstSequence uint64        //sequence number of the first entry
+	LastSequence  uint64        //sequence number of the last entry
+	Time          time.Time     //time the write completed
+	Duration      time.Duration //time the write took, including retries
+}
+
+// DeliveryHook is told about every buffer a worker writes to its file successfully, e.g. to count delivered
+// bytes, checkpoint the last sequence number or ship the file elsewhere. It runs while the worker holds its lock,
+// so it must be fast and must not log to the same logger.
+type DeliveryHook func(delivery Delivery)
+
+// AddHook makes the worker run hook for the entries at its levels, after the hooks added before it.
+func (w *Worker) AddHook(hook Hook) {
+	w.hooks.lock.Lock()
+	defer w.hooks.lock.Unlock()
+	current, _ := w.hooks.hooks.Load().([]Hook)
+	updated := append(append([]Hook(nil), current...), hook)
+	w.hooks.hooks.Store(updated)
+}
+
+// OnDelivery makes the worker call hook after every successful write of its buffer to the file, including
+// those of the routes added to it.
+func (w *Worker) OnDelivery(hook DeliveryHook) {
+	w.hooks.lock.Lock()
+	defer w.hooks.lock.Unlock()
+	current, _ := w.hooks.deliveries.Load().([]DeliveryHook)
+	updated := append(append([]DeliveryHook(nil), current...), hook)
+	w.hooks.deliveries.Store(updated)
+}
+
+//This method reports a successful write of n bytes holding the buffered entries, finished at now, to the delivery
+// hooks of the worker and of the worker the worker is a route of. It must be called with lock held.
+func (w *Worker) deliver(n int, now time.Time, took time.Duration) {
+	delivery := Delivery{File: w.fileRoot.Name(), Entries: w.pending, Bytes: n, FirstSequence: w.firstSeq,
+		LastSequence: w.lastSeq, Time: now, Duration: took}
+	for worker := w; worker != nil; worker = worker.owner {
+		current, _ := worker.hooks.deliveries.Load().([]DeliveryHook)
+		for _, hook := range current {
+			hook(delivery)
+		}
+	}
+}
+
+//This method runs the hooks for the entry and reports whether it should still be logged.
+func (w *Worker) fireHooks(entry *Entry) bool {
+	current, _ := w.base().hooks.hooks.Load().([]Hook)
+	for _, hook := range current {
+		if !firesFor(hook, entry.level) {
+			continue
+		}
+		if err := hook.Fire(entry); err == ErrVeto {
+			return false
+		} else if err != nil {
+			w.fail(err, OpHook, entry)
+		}
+	}
+	return true
+}
+
+//This method reports whether the hook runs for entries at the given level.
+func firesFor(hook Hook, level Level) bool {
+	for _, l := range hook.Levels() {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// AddField sets a field of the entry, e.g. from a Hook. The entry's fields may be shared with other entries,
+// so they are copied first.
+func (entry *Entry) AddField(key string, value interface{}) {
+	fields := make(Fields, len(entry.fields)+1)
+	for k, v := range entry.fields {
+		fields[k] = v
+	}
+	fields[key] = value
+	entry.fields = fields
+}
@@ -1,6 +1,7 @@
 package logWriter
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -11,6 +12,8 @@ type Level uint32
 // Convert the Level to a string. E.g. DebugLevel becomes "debug".
 func (level Level) String() string {
 	switch level {
+	case TraceLevel:
+		return "trace"
 	case DebugLevel:
 		return "debug"
 	case InfoLevel:
@@ -19,14 +22,26 @@ func (level Level) String() string {
 		return "warning"
 	case ErrorLevel:
 		return "error"
+	case FatalLevel:
+		return "fatal"
+	case PanicLevel:
+		return "panic"
+	}
+	if custom, ok := customLevel(level); ok {
+		return custom.Name
 	}
 
 	return "unknown"
 }
 
-// ParseLevel takes a string level and returns the log level constant.
+// ParseLevel takes a string level and returns the log level constant, or the custom level registered under
+// that name.
 func ParseLevel(lvl string) (Level, error) {
 	switch strings.ToLower(lvl) {
+	case "panic":
+		return PanicLevel, nil
+	case "fatal":
+		return FatalLevel, nil
 	case "error":
 		return ErrorLevel, nil
 	case "warn", "warning":
@@ -35,21 +50,82 @@ func ParseLevel(lvl string) (Level, error) {
 		return InfoLevel, nil
 	case "debug":
 		return DebugLevel, nil
+	case "trace":
+		return TraceLevel, nil
+	}
+	if level, ok := parseCustomLevel(strings.ToLower(lvl)); ok {
+		return level, nil
 	}
 
 	var l Level
-	return l, fmt.Errorf("not a valid logrus Level: %q", lvl)
+	return l, fmt.Errorf("not a valid level: %q", lvl)
 }
 
-// A constant exposing all logging levels
+// MarshalText implements encoding.TextMarshaler, writing the level as its name so that levels round-trip
+// through config files and other text formats. It fails for levels that are neither built in nor registered.
+func (level Level) MarshalText() ([]byte, error) {
+	name := level.String()
+	if name == "unknown" {
+		return nil, fmt.Errorf("not a valid level: %d", uint32(level))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting the names ParseLevel accepts.
+func (level *Level) UnmarshalText(text []byte) error {
+	parsed, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*level = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, writing the level as its name, e.g. "debug".
+func (level Level) MarshalJSON() ([]byte, error) {
+	name, err := level.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(name))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting a level name, or the number levels were written as before
+// they had names in JSON. Like names, numbers must be those of built-in or registered levels.
+func (level *Level) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		return level.UnmarshalText([]byte(name))
+	}
+	var number uint32
+	if err := json.Unmarshal(data, &number); err != nil || Level(number).String() == "unknown" {
+		return fmt.Errorf("not a valid level: %s", data)
+	}
+	*level = Level(number)
+	return nil
+}
+
+// Set implements flag.Value together with String, so that a level is bound to a command line flag with
+//
+//	level := logWriter.InfoLevel
+//	flag.Var(&level, "log-level", "least severe level logged")
+func (level *Level) Set(name string) error {
+	return level.UnmarshalText([]byte(name))
+}
+
+// A constant exposing all built-in logging levels, from the most severe to the most verbose
 var AllLevels = []Level{
+	PanicLevel,
+	FatalLevel,
 	ErrorLevel,
 	WarnLevel,
 	InfoLevel,
 	DebugLevel,
+	TraceLevel,
 }
 
-// These are the different logging levels.
+// These are the different logging levels. Levels added after DebugLevel are numbered after it so that the
+// numbers of the existing levels keep their values; compare levels with Enables rather than with < or >.
 const (
 	// ErrorLevel level. Logs. Used for errors that should definitely be noted.
 	ErrorLevel Level = iota
@@ -60,4 +136,39 @@ const (
 	InfoLevel
 	// DebugLevel level. Usually only enabled when debugging. Very verbose logging.
 	DebugLevel
+	// TraceLevel level. Designates finer-grained informational events than the Debug.
+	TraceLevel
+	// FatalLevel level. Logs and then exits the process with status 1. More severe than ErrorLevel.
+	FatalLevel
+	// PanicLevel level. Highest level of severity. Logs and then panics with the message.
+	PanicLevel
 )
+
+//verbosity of every level, from 0 for PanicLevel to 6 for TraceLevel.
+var verbosity = [...]uint32{
+	ErrorLevel: 2,
+	WarnLevel:  3,
+	InfoLevel:  4,
+	DebugLevel: 5,
+	TraceLevel: 6,
+	FatalLevel: 1,
+	PanicLevel: 0,
+}
+
+//This method returns the verbosity of the level: the larger, the more verbose.
+func (level Level) verbosity() uint32 {
+	if int(level) < len(verbosity) {
+		return verbosity[level]
+	}
+	if custom, ok := customLevel(level); ok {
+		return custom.Verbosity
+	}
+	return 0
+}
+
+// Enables reports whether a logger at this level logs entries at the given level, i.e. whether entry is at
+// most as verbose as level. An ErrorLevel logger logs Error, Fatal and Panic entries, a TraceLevel logger
+// logs everything.
+func (level Level) Enables(entry Level) bool {
+	return level.verbosity() >= entry.verbosity()
+}
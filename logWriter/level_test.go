@@ -0,0 +1,36 @@
+package logWriter_test
+
+import (
+	"encoding/json"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"strconv"
+	"testing"
+)
+
+// TestLevelUnmarshalJSON checks that levels are read from JSON names and from the numbers of built-in and
+// registered levels, and that other numbers are rejected like unknown names.
+func TestLevelUnmarshalJSON(t *testing.T) {
+	audit, err := logWriter.RegisterLevel(logWriter.CustomLevel{Name: "json-audit", Verbosity: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := map[string]logWriter.Level{
+		`"debug"`:      logWriter.DebugLevel,
+		`"json-audit"`: audit,
+		`3`:            logWriter.DebugLevel,
+		`6`:            logWriter.PanicLevel,
+	}
+	valid[strconv.Itoa(int(audit))] = audit
+	for data, want := range valid {
+		var level logWriter.Level
+		if err := json.Unmarshal([]byte(data), &level); err != nil || level != want {
+			t.Errorf("%s: got %v, %v, want %v", data, level, err, want)
+		}
+	}
+	for _, data := range []string{`"loud"`, `99`, `-3`, `7`, `1.5`, `true`} {
+		level := logWriter.InfoLevel
+		if err := json.Unmarshal([]byte(data), &level); err == nil {
+			t.Errorf("%s: got %v, want an error", data, level)
+		}
+	}
+}
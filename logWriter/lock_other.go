@@ -0,0 +1,18 @@
+//go:build !unix || aix || solaris
+
+package logWriter
+
+import (
+	"errors"
+	"os"
+)
+
+//Util method that reports that advisory locks are not supported on this system.
+func lockFile(file *os.File) error {
+	return errors.New("file locking is not supported on this system")
+}
+
+//Util method that does nothing, as no lock can be held.
+func unlockFile(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,23 @@
+//go:build unix && !aix && !solaris
+
+package logWriter
+
+import (
+	"os"
+	"syscall"
+)
+
+//Util method that takes an exclusive advisory lock on the file, waiting for other processes to release theirs.
+func lockFile(file *os.File) error {
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+//Util method that releases the advisory lock on the file.
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,220 @@
+package logWriter
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// PatternFormatter writes entries in a line layout given as a pattern, e.g. to keep the format a legacy parser
+// expects during a migration:
+//
+//	%t{2006-01-02 15:04:05} %-7L [%c] %m %f
+//
+// The pattern is compiled once by ParsePattern, so that formatting an entry only appends its parts. The verbs
+// are:
+//
+//	%t        time, in the layout given in braces, "2006/01/02 15:04:05.000000" by default
+//	%l, %L    level in lower and upper case, e.g. warning and WARNING
+//	%p        level as the padded prefix of the default output, e.g. "[WARN]  "
+//	%m        message
+//	%c        call site as file:line
+//	%f        fields as key=value pairs, or the value of the field named in braces, e.g. %f{user}
+//	%s        sequence number
+//	%S        recorded stack on indented lines, nothing for entries without one
+//	%n        newline
+//	%%        percent sign
+//
+// A width between the percent sign and the verb pads the value with spaces on the left, or on the right if it
+// is negative, e.g. %-7L. A newline is appended to lines that do not end with one.
+type PatternFormatter struct {
+	UTC   bool          //write the time in UTC instead of local time
+	parts []patternPart //literal text and verbs of the pattern, in order
+}
+
+//patternPart is a verb of a pattern, or literal text if verb is 0.
+type patternPart struct {
+	verb  byte   //verb character, 0 for literal text
+	arg   string //argument given in braces, or the literal text
+	width int    //padding width, negative to pad on the right
+}
+
+//verbs accepted by ParsePattern, and whether they take an argument in braces.
+var patternVerbs = map[byte]bool{'t': true, 'l': false, 'L': false, 'p': false, 'm': false, 'c': false, 'f': true,
+	's': false, 'S': false, 'n': false}
+
+// ParsePattern compiles a pattern of PatternFormatter, and fails for unknown verbs and unterminated braces.
+func ParsePattern(pattern string) (*PatternFormatter, error) {
+	f := &PatternFormatter{}
+	var literal strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' {
+			literal.WriteByte(pattern[i])
+			continue
+		}
+		i++
+		if i < len(pattern) && pattern[i] == '%' {
+			literal.WriteByte('%')
+			continue
+		}
+		start := i
+		if i < len(pattern) && pattern[i] == '-' {
+			i++
+		}
+		for i < len(pattern) && pattern[i] >= '0' && pattern[i] <= '9' {
+			i++
+		}
+		if i == len(pattern) {
+			return nil, fmt.Errorf("pattern %q ends in a verb", pattern)
+		}
+		part := patternPart{verb: pattern[i]}
+		if i > start {
+			width, err := strconv.Atoi(pattern[start:i])
+			if err != nil {
+				return nil, fmt.Errorf("pattern %q: invalid width %q", pattern, pattern[start:i])
+			}
+			part.width = width
+		}
+		takesArg, ok := patternVerbs[part.verb]
+		if !ok {
+			return nil, fmt.Errorf("pattern %q: unknown verb %%%c", pattern, part.verb)
+		}
+		if takesArg && i+1 < len(pattern) && pattern[i+1] == '{' {
+			end := strings.IndexByte(pattern[i+2:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("pattern %q: unterminated braces of %%%c", pattern, part.verb)
+			}
+			part.arg = pattern[i+2 : i+2+end]
+			i += 2 + end
+		}
+		if literal.Len() > 0 {
+			f.parts = append(f.parts, patternPart{arg: literal.String()})
+			literal.Reset()
+		}
+		f.parts = append(f.parts, part)
+	}
+	if literal.Len() > 0 {
+		f.parts = append(f.parts, patternPart{arg: literal.String()})
+	}
+	return f, nil
+}
+
+// Format implements Formatter.
+func (f *PatternFormatter) Format(entry Entry) ([]byte, error) {
+	var b bytes.Buffer
+	var scratch [64]byte
+	for _, part := range f.parts {
+		if part.verb == 0 {
+			b.WriteString(part.arg)
+			continue
+		}
+		start := b.Len()
+		switch part.verb {
+		case 't':
+			layout := part.arg
+			if len(layout) == 0 {
+				layout = "2006/01/02 15:04:05.000000"
+			}
+			b.Write(appendTime(scratch[:0], entry.Time(), layout, f.UTC))
+		case 'l':
+			b.WriteString(entry.level.String())
+		case 'L':
+			b.WriteString(strings.ToUpper(entry.level.String()))
+		case 'p':
+			b.WriteString(entry.level.Prefix())
+		case 'm':
+			entry.appendMessage(&b)
+		case 'c':
+			b.WriteString(entry.shortCaller())
+		case 'f':
+			if len(part.arg) == 0 {
+				entry.appendFields(&b)
+			} else if value, ok := entry.fields[part.arg]; ok {
+				b.WriteString(textValue(value))
+			}
+		case 's':
+			b.Write(strconv.AppendUint(scratch[:0], entry.sequence, 10))
+		case 'S':
+			writeStack(&b, entry)
+		case 'n':
+			b.WriteByte('\n')
+		}
+		padPart(&b, start, part.width)
+	}
+	if line := b.Bytes(); len(line) == 0 || line[len(line)-1] != '\n' {
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
+
+//This method pads the text written to b since start with spaces to width characters, on the left for a positive
+// width and on the right for a negative one.
+func padPart(b *bytes.Buffer, start int, width int) {
+	right := width < 0
+	if right {
+		width = -width
+	}
+	n := width - (b.Len() - start)
+	if n <= 0 {
+		return
+	}
+	if right {
+		b.WriteString(strings.Repeat(" ", n))
+		return
+	}
+	text := append([]byte(nil), b.Bytes()[start:]...)
+	b.Truncate(start)
+	b.WriteString(strings.Repeat(" ", n))
+	b.Write(text)
+}
+
+// TemplateFormatter writes entries with a text/template, for layouts a pattern of PatternFormatter cannot
+// express. The template is executed with a TemplateEntry, e.g.
+//
+//	{{.Time.Format "15:04:05"}} {{.Level}} {{.Message}}{{range $k, $v := .Fields}} {{$k}}={{$v}}{{end}}
+//
+// It is slower than PatternFormatter, since every value goes through reflection. A newline is appended to lines
+// that do not end with one.
+type TemplateFormatter struct {
+	template *template.Template
+}
+
+// TemplateEntry is the data a TemplateFormatter executes its template with.
+type TemplateEntry struct {
+	Time     time.Time              //time the entry was logged at
+	Level    Level                  //level, written as its name
+	Message  string                 //message
+	Caller   string                 //call site as file:line, "" if unknown
+	Fields   map[string]interface{} //fields, ranged over in key order
+	Sequence uint64                 //sequence number
+	Stack    string                 //recorded stack, "" if none
+}
+
+// NewTemplateFormatter parses a template of TemplateFormatter.
+func NewTemplateFormatter(text string) (*TemplateFormatter, error) {
+	parsed, err := template.New("entry").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateFormatter{template: parsed}, nil
+}
+
+// Format implements Formatter.
+func (f *TemplateFormatter) Format(entry Entry) ([]byte, error) {
+	data := TemplateEntry{Time: entry.Time(), Level: entry.level, Message: entry.Message(), Fields: entry.fields,
+		Sequence: entry.sequence, Stack: entry.Stack()}
+	if caller := entry.shortCaller(); caller != unknownCaller {
+		data.Caller = caller
+	}
+	var b bytes.Buffer
+	if err := f.template.Execute(&b, data); err != nil {
+		return nil, err
+	}
+	if line := b.Bytes(); len(line) == 0 || line[len(line)-1] != '\n' {
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
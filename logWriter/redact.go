@@ -0,0 +1,135 @@
+package logWriter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultMask replaces redacted data unless a Redactor says otherwise.
+const DefaultMask = "[REDACTED]"
+
+// CreditCardPattern matches card numbers of 13 to 19 digits, optionally grouped by spaces or dashes.
+var CreditCardPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+
+// EmailPattern matches email addresses.
+var EmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// Redactor masks sensitive data of entries in the worker, before they are formatted, written to the files or
+// handed to the sinks. The values of fields whose key is one of Keys are replaced by Mask, and every match of
+// Patterns in the message and in the values of the other fields is replaced by Mask. Besides strings, this
+// scrubs the text of errors, ErrorChains and fmt.Stringers, which are replaced by their redacted text if a
+// pattern matched.
+type Redactor struct {
+	Keys     []string         //keys of the fields whose values are masked, matched case-insensitively, e.g. "password"
+	Patterns []*regexp.Regexp //patterns scrubbed from the message and field values, e.g. CreditCardPattern
+	Mask     string           //replacement of redacted data, DefaultMask if empty
+}
+
+// NewRedactor returns a redactor masking the fields with the given keys and scrubbing the patterns, given as
+// regular expressions. It returns an error if a pattern does not compile.
+func NewRedactor(keys []string, patterns ...string) (*Redactor, error) {
+	redactor := &Redactor{Keys: keys}
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		redactor.Patterns = append(redactor.Patterns, compiled)
+	}
+	return redactor, nil
+}
+
+// Redact masks the sensitive data of the entry. The message is replaced by its redacted text if a pattern
+// matched; the fields are copied before they are masked, as they may be shared with other entries.
+func (r *Redactor) Redact(entry *Entry) {
+	if len(r.Patterns) > 0 {
+		message := entry.Message()
+		if scrubbed := r.scrub(message); scrubbed != message {
+			entry.message = scrubbed
+			entry.format = ""
+		}
+	}
+	if len(entry.fields) == 0 {
+		return
+	}
+	var fields Fields
+	for key, value := range entry.fields {
+		var redacted interface{} = r.mask()
+		if !r.masksKey(key) {
+			var changed bool
+			if redacted, changed = r.scrubValue(value); !changed {
+				continue
+			}
+		}
+		if fields == nil {
+			fields = make(Fields, len(entry.fields))
+			for k, v := range entry.fields {
+				fields[k] = v
+			}
+		}
+		fields[key] = redacted
+	}
+	if fields != nil {
+		entry.fields = fields
+	}
+}
+
+//This method reports whether the values of fields with the given key are masked.
+func (r *Redactor) masksKey(key string) bool {
+	for _, k := range r.Keys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+//This method scrubs the text of a field value, and reports whether a pattern matched. Errors and
+// fmt.Stringers are replaced by their redacted text, ErrorChains by a chain of their redacted parts; other
+// values are left alone, as their text depends on the formatter.
+func (r *Redactor) scrubValue(value interface{}) (interface{}, bool) {
+	switch value := value.(type) {
+	case string:
+		scrubbed := r.scrub(value)
+		return scrubbed, scrubbed != value
+	case ErrorChain:
+		var chain ErrorChain
+		for i, part := range value {
+			if scrubbed := r.scrub(part); scrubbed != part {
+				if chain == nil {
+					chain = append(ErrorChain(nil), value...)
+				}
+				chain[i] = scrubbed
+			}
+		}
+		return chain, chain != nil
+	case error:
+		return r.scrubText(value.Error())
+	case fmt.Stringer:
+		return r.scrubText(value.String())
+	}
+	return value, false
+}
+
+//This method scrubs the text of a value, see scrubValue.
+func (r *Redactor) scrubText(text string) (interface{}, bool) {
+	scrubbed := r.scrub(text)
+	return scrubbed, scrubbed != text
+}
+
+//This method replaces every match of the patterns in text by the mask.
+func (r *Redactor) scrub(text string) string {
+	for _, pattern := range r.Patterns {
+		text = pattern.ReplaceAllLiteralString(text, r.mask())
+	}
+	return text
+}
+
+//This method returns the replacement of redacted data.
+func (r *Redactor) mask() string {
+	if len(r.Mask) > 0 {
+		return r.Mask
+	}
+	return DefaultMask
+}
@@ -0,0 +1,110 @@
+package logWriter
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// SinkFactory creates a sink from the options given for it, e.g. the settings found under the sink's name in
+// a config file.
+type SinkFactory func(options map[string]interface{}) (Sink, error)
+
+//registry of sink factories keyed by name.
+var sinkRegistry = struct {
+	lock      sync.RWMutex
+	factories map[string]SinkFactory
+}{factories: make(map[string]SinkFactory)}
+
+// RegisterSink makes a sink factory available by name, so that third party sink packages can be wired through
+// configuration without this package importing them. It returns an error if the name is empty, the factory is
+// nil or the name is already registered.
+func RegisterSink(name string, factory SinkFactory) error {
+	if len(name) == 0 {
+		return fmt.Errorf("sink name must not be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("sink factory for %q is nil", name)
+	}
+	sinkRegistry.lock.Lock()
+	defer sinkRegistry.lock.Unlock()
+	if _, exists := sinkRegistry.factories[name]; exists {
+		return fmt.Errorf("sink %q is already registered", name)
+	}
+	sinkRegistry.factories[name] = factory
+	return nil
+}
+
+// NewSink creates a sink using the factory registered under name.
+func NewSink(name string, options map[string]interface{}) (Sink, error) {
+	sinkRegistry.lock.RLock()
+	factory, exists := sinkRegistry.factories[name]
+	sinkRegistry.lock.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("no sink registered as %q", name)
+	}
+	return factory(options)
+}
+
+// RegisteredSinks returns the sorted names of all registered sinks.
+func RegisteredSinks() []string {
+	sinkRegistry.lock.RLock()
+	defer sinkRegistry.lock.RUnlock()
+	names := make([]string, 0, len(sinkRegistry.factories))
+	for name := range sinkRegistry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FormatterFactory creates a formatter from the options given for it, e.g. the format_options of a config file.
+type FormatterFactory func(options map[string]interface{}) (Formatter, error)
+
+//registry of formatter factories keyed by name.
+var formatterRegistry = struct {
+	lock      sync.RWMutex
+	factories map[string]FormatterFactory
+}{factories: make(map[string]FormatterFactory)}
+
+// RegisterFormatter makes a formatter factory available by name, so that formats of other packages, e.g. the
+// schema of a log vendor, can be chosen in configuration like the built-in ones. It returns an error if the name
+// is empty, the factory is nil or the name is already registered.
+func RegisterFormatter(name string, factory FormatterFactory) error {
+	if len(name) == 0 {
+		return fmt.Errorf("formatter name must not be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("formatter factory for %q is nil", name)
+	}
+	formatterRegistry.lock.Lock()
+	defer formatterRegistry.lock.Unlock()
+	if _, exists := formatterRegistry.factories[name]; exists {
+		return fmt.Errorf("formatter %q is already registered", name)
+	}
+	formatterRegistry.factories[name] = factory
+	return nil
+}
+
+// NewFormatter creates a formatter using the factory registered under name.
+func NewFormatter(name string, options map[string]interface{}) (Formatter, error) {
+	formatterRegistry.lock.RLock()
+	factory, exists := formatterRegistry.factories[name]
+	formatterRegistry.lock.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("no formatter registered as %q", name)
+	}
+	return factory(options)
+}
+
+// RegisteredFormatters returns the sorted names of all registered formatters.
+func RegisteredFormatters() []string {
+	formatterRegistry.lock.RLock()
+	defer formatterRegistry.lock.RUnlock()
+	names := make([]string, 0, len(formatterRegistry.factories))
+	for name := range formatterRegistry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
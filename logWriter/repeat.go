@@ -0,0 +1,65 @@
+package logWriter
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+//repeats collapses runs of identical entries: the first entry of a run is written, the identical entries
+// following it within the window are counted instead, and the count is written when the run ends.
+type repeats struct {
+	lock    sync.Mutex    //guards the fields below, held while the entries of a run are written
+	window  time.Duration //time after the first entry of a run in which identical entries are suppressed
+	active  bool          //set while a run is open
+	key     string        //level, destination, message and fields of the run's entries
+	started time.Time     //time the first entry of the run was logged
+	last    Entry         //last suppressed entry of the run
+	count   int           //entries of the run suppressed so far
+}
+
+//This method returns what makes entries identical: their level, destination, message and fields.
+func repeatKey(event Entry) string {
+	return event.level.String() + "\x00" + event.destination + "\x00" + event.Message() + "\x00" + event.fieldText()
+}
+
+//This method writes the entry unless it repeats the entry before it within the window. An entry ending a run
+// is written after the entry reporting the run's repetitions.
+func (r *repeats) handle(w *Worker, event Entry) {
+	key := repeatKey(event)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.active && key == r.key && event.Time().Sub(r.started) < r.window {
+		r.last = event
+		r.count++
+		return
+	}
+	r.end(w)
+	r.active, r.key, r.started = true, key, event.Time()
+	w.emit(event)
+}
+
+//This method closes the open run, if any, writing the last suppressed entry with the number of repetitions
+// appended to its message, e.g. "connection refused (repeated 41 times)". It must be called with lock held.
+func (r *repeats) end(w *Worker) {
+	if r.count > 0 {
+		summary := r.last
+		summary.message = fmt.Sprintf("%s (repeated %d times)", r.last.Message(), r.count)
+		summary.format = ""
+		w.emit(summary)
+	}
+	r.active, r.count, r.last = false, 0, Entry{}
+}
+
+//This method closes the open run if its window has passed, or unconditionally if force is set, so that the
+// repetitions of a run that stopped are not held back until the next entry.
+func (w *Worker) endRepeats(force bool) {
+	if w.repeats == nil {
+		return
+	}
+	w.repeats.lock.Lock()
+	defer w.repeats.lock.Unlock()
+	if force || w.clock.Now().Sub(w.repeats.started) >= w.repeats.window {
+		w.repeats.end(w)
+	}
+}
@@ -0,0 +1,146 @@
+package logWriter
+
+import (
+	"github.com/shyamgrover/go-lite-logger/utils"
+	"os"
+	"sync/atomic"
+)
+
+// Ring is a bounded lock-free queue of entries with many producers and one consumer, the worker. It replaces the
+// channel between the logging calls and the worker for very hot services: putting an entry is a compare-and-swap
+// and a store instead of a channel send, and the worker takes entries without a select. Producers only touch
+// channels when the ring is full and the worker only when it is empty. Create a worker reading a ring with
+// NewRingWorker.
+type Ring struct {
+	mask     uint64        //capacity - 1, the capacity being a power of two
+	slots    []ringSlot    //entries, each guarded by its sequence number
+	_        [56]byte      //keeps tail on a cache line of its own
+	tail     uint64        //position the next producer claims
+	_        [56]byte      //keeps head on a cache line of its own
+	head     uint64        //position the consumer takes next, only written by the consumer
+	sleeping int32         //1 while the consumer waits for an entry
+	ready    chan struct{} //wakes the consumer, holds at most one token
+	waiters  int32         //producers waiting for room
+	space    chan struct{} //wakes a producer waiting for room, holds at most one token
+}
+
+//ringSlot is a cell of a Ring. Its sequence number is its position while it is free, the position plus one once
+// a producer has stored its entry, and the position of the next lap after the consumer took the entry.
+type ringSlot struct {
+	seq   uint64
+	entry Entry
+}
+
+// NewRing returns a ring holding size entries, rounded up to a power of two.
+func NewRing(size int) *Ring {
+	capacity := 1
+	for capacity < size {
+		capacity <<= 1
+	}
+	r := &Ring{mask: uint64(capacity - 1), slots: make([]ringSlot, capacity), ready: make(chan struct{}, 1),
+		space: make(chan struct{}, 1)}
+	for i := range r.slots {
+		r.slots[i].seq = uint64(i)
+	}
+	return r
+}
+
+// NewRingWorker returns a new worker like NewWorkerWithOptions that reads its entries from ring instead of a
+// channel. Only one worker may read a ring.
+func NewRingWorker(file *os.File, ring *Ring, errorCallback utils.ErrorFunction, options WorkerOptions) *Worker {
+	worker := NewWorkerWithOptions(file, nil, errorCallback, options)
+	worker.ring = ring
+	return worker
+}
+
+// Offer puts the entry on the ring without waiting and reports whether there was room.
+func (r *Ring) Offer(entry Entry) bool {
+	for {
+		pos := atomic.LoadUint64(&r.tail)
+		slot := &r.slots[pos&r.mask]
+		seq := atomic.LoadUint64(&slot.seq)
+		if seq == pos {
+			if atomic.CompareAndSwapUint64(&r.tail, pos, pos+1) {
+				slot.entry = entry
+				atomic.StoreUint64(&slot.seq, pos+1)
+				if atomic.LoadInt32(&r.sleeping) == 1 && atomic.CompareAndSwapInt32(&r.sleeping, 1, 0) {
+					wake(r.ready)
+				}
+				return true
+			}
+		} else if seq < pos {
+			return false //the slot still holds the entry of the previous lap
+		}
+	}
+}
+
+// Put puts the entry on the ring, waiting for the consumer to make room if the ring is full.
+func (r *Ring) Put(entry Entry) {
+	for !r.Offer(entry) {
+		atomic.AddInt32(&r.waiters, 1)
+		if r.Offer(entry) {
+			atomic.AddInt32(&r.waiters, -1)
+			return
+		}
+		<-r.space
+		atomic.AddInt32(&r.waiters, -1)
+	}
+}
+
+// Poll takes the oldest entry off the ring without waiting, and reports false if there is none. It must only
+// be called by the consumer.
+func (r *Ring) Poll() (Entry, bool) {
+	pos := r.head
+	slot := &r.slots[pos&r.mask]
+	if atomic.LoadUint64(&slot.seq) != pos+1 {
+		return Entry{}, false
+	}
+	entry := slot.entry
+	slot.entry = Entry{}
+	atomic.StoreUint64(&slot.seq, pos+r.mask+1)
+	atomic.StoreUint64(&r.head, pos+1)
+	if atomic.LoadInt32(&r.waiters) > 0 {
+		wake(r.space)
+	}
+	return entry, true
+}
+
+// Len returns the number of entries on the ring.
+func (r *Ring) Len() int {
+	head := atomic.LoadUint64(&r.head)
+	tail := atomic.LoadUint64(&r.tail)
+	if tail < head {
+		return 0
+	}
+	return int(tail - head)
+}
+
+// Cap returns the number of entries the ring holds.
+func (r *Ring) Cap() int {
+	return len(r.slots)
+}
+
+//This method waits until an entry may be on the ring or done is closed, and reports false in the latter case.
+// Wake-ups may be spurious. It must only be called by the consumer.
+func (r *Ring) wait(done <-chan struct{}) bool {
+	atomic.StoreInt32(&r.sleeping, 1)
+	if atomic.LoadUint64(&r.slots[r.head&r.mask].seq) == r.head+1 {
+		atomic.StoreInt32(&r.sleeping, 0)
+		return true
+	}
+	select {
+	case <-r.ready:
+		return true
+	case <-done:
+		atomic.StoreInt32(&r.sleeping, 0)
+		return false
+	}
+}
+
+//This method leaves a token on a channel of capacity one unless one is there already.
+func wake(tokens chan struct{}) {
+	select {
+	case tokens <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,225 @@
+package logWriter
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+//layout of the timestamp appended to the name of a file rotated because of its size.
+const rotatedTimeLayout = "2006-01-02T15-04-05"
+
+// RotationPeriod is a schedule on which the log file is rotated.
+type RotationPeriod int
+
+const (
+	// NoRotation never rotates the file on a schedule.
+	NoRotation RotationPeriod = iota
+	// Hourly rotates the file at every full hour; rotated files get a suffix like .2020-05-01T10.
+	Hourly
+	// Daily rotates the file at midnight; rotated files get a suffix like .2020-05-01.
+	Daily
+)
+
+//This method returns the start of the period containing t, in t's location.
+func (period RotationPeriod) start(t time.Time) time.Time {
+	switch period {
+	case Hourly:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case Daily:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+	return time.Time{}
+}
+
+//This method returns the suffix of a file holding the entries of the period starting at start.
+func (period RotationPeriod) suffix(start time.Time) string {
+	if period == Hourly {
+		return start.Format("2006-01-02T15")
+	}
+	return start.Format("2006-01-02")
+}
+
+//This method returns the period the file was last written in: the period of its modification time if it has
+// content, the period of now otherwise.
+func (period RotationPeriod) ofFile(file *os.File, now time.Time) time.Time {
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		return period.start(info.ModTime())
+	}
+	return period.start(now)
+}
+
+// File returns the file the worker currently writes to. After a rotation this is the fresh file, so the owner
+// of the worker should close the file returned here rather than the one passed to NewWorker.
+func (w *Worker) File() *os.File {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.fileRoot
+}
+
+//This method returns the size of the file the worker writes to, 0 if it cannot be determined.
+func fileSize(file *os.File) int64 {
+	if file == nil {
+		return 0
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+//This method rotates the file if a new period has begun or if writing the buffer would make the file exceed the
+// worker's maximum size. A file that is still empty is never rotated for its size, so that a buffer larger than
+// the maximum size is still written. It must be called with lock held.
+func (w *Worker) rotateIfNeeded() error {
+	if w.period != NoRotation {
+		if now := w.period.start(w.clock.Now()); now.After(w.periodStart) {
+			suffix := w.period.suffix(w.periodStart)
+			w.periodStart = now
+			if w.size > 0 {
+				return w.rotate(suffix)
+			}
+		}
+	}
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(w.readyBytes+w.position) > w.maxSize {
+		return w.rotate(w.clock.Now().Format(rotatedTimeLayout))
+	}
+	return nil
+}
+
+//This method closes the current file, renames it with the given suffix and opens a fresh file under the
+// original name. The buffer is not touched, so buffered entries go to the fresh file. If the file cannot be
+// renamed, or the fresh file cannot be opened, the worker keeps writing to the current file under its original
+// name. It must be called with lock held.
+func (w *Worker) rotate(suffix string) error {
+	path := w.fileRoot.Name()
+	rotated := rotatedName(path, suffix)
+	if err := os.Rename(path, rotated); err != nil {
+		return fmt.Errorf("rotating %s: %v", path, err)
+	}
+	file, err := OpenFile(path, w.fileMode)
+	if err != nil {
+		os.Rename(rotated, path)
+		return fmt.Errorf("rotating %s: %v", path, err)
+	}
+	w.fileRoot.Close()
+	w.fileRoot = file
+	w.size = 0
+	if w.compress {
+		w.compressing.Add(1)
+		go w.compressRotated(rotated)
+	}
+	return nil
+}
+
+//This method compresses a rotated file to a .gz file next to it and removes the original. The compressed file is
+// written under a temporary name first, so a .gz file is always complete. Failures are reported through the
+// error callback and LastError; the rotated file is kept in that case.
+func (w *Worker) compressRotated(path string) {
+	defer w.compressing.Done()
+	if err := compressFile(path, w.fileMode); err != nil {
+		w.lastError.Store(&FlushError{Err: err, Time: w.clock.Now()})
+		w.fail(err, OpCompress, nil)
+	}
+}
+
+//This method gzips the file at path to path.gz, created with the given mode, and removes path.
+func compressFile(path string, mode os.FileMode) error {
+	source, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("compressing %s: %v", path, err)
+	}
+	defer source.Close()
+	temp := path + ".gz.tmp"
+	target, err := os.OpenFile(temp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return fmt.Errorf("compressing %s: %v", path, err)
+	}
+	if err = target.Chmod(mode); err != nil {
+		target.Close()
+		os.Remove(temp)
+		return fmt.Errorf("compressing %s: %v", path, err)
+	}
+	zipper := gzip.NewWriter(target)
+	_, err = io.Copy(zipper, source)
+	if closeErr := zipper.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp, path+".gz")
+	}
+	if err != nil {
+		os.Remove(temp)
+		return fmt.Errorf("compressing %s: %v", path, err)
+	}
+	source.Close()
+	return os.Remove(path)
+}
+
+//This method returns path with the suffix appended, followed by the lowest free index if a file of that name
+// already exists, e.g. app.log.2020-05-01 or app.log.2020-05-01.1.
+func rotatedName(path string, suffix string) string {
+	name := path + "." + suffix
+	candidate := name
+	for i := 1; ; i++ {
+		if !exists(candidate) && !exists(candidate+".gz") {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s.%d", name, i)
+	}
+}
+
+//This method reports whether a file exists at path.
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return !os.IsNotExist(err)
+}
+
+// Reopen closes the worker's file and opens it again by name, and does the same for its routes. It is meant for
+// external rotation tools such as logrotate, which rename the file and expect the writer to continue in a fresh
+// file under the original name. Buffered entries are written to the reopened file, so none are lost. It returns
+// the first error; a worker whose file cannot be reopened keeps its current file.
+func (w *Worker) Reopen() error {
+	w.lock.Lock()
+	err := w.reopen()
+	w.lock.Unlock()
+
+	w.routeLock.RLock()
+	defer w.routeLock.RUnlock()
+	for _, shard := range w.shards {
+		if shard.into == nil {
+			if shardErr := shard.Reopen(); err == nil {
+				err = shardErr
+			}
+		}
+	}
+	for _, route := range w.routes {
+		if routeErr := route.Reopen(); err == nil {
+			err = routeErr
+		}
+	}
+	return err
+}
+
+//This method swaps the worker's file for a freshly opened file of the same name. It must be called with lock
+// held.
+func (w *Worker) reopen() error {
+	path := w.fileRoot.Name()
+	file, err := OpenFile(path, w.fileMode)
+	if err != nil {
+		return fmt.Errorf("reopening %s: %v", path, err)
+	}
+	w.fileRoot.Close()
+	w.fileRoot = file
+	w.size = fileSize(file)
+	if w.period != NoRotation {
+		w.periodStart = w.period.ofFile(file, w.clock.Now())
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package logWriter
+
+import "os"
+
+// NewShard returns a worker that handles entries like w on a goroutine of its own, reading them from channel, or
+// from ring if it is not nil, so that several cores share the work of one logger. A shard runs the hooks of w,
+// hands entries to the sinks and routes of w and reports its deliveries to the delivery hooks of w; it is
+// flushed and closed by its own flush entries and by w.CloseWorker, which closes the shards before w itself.
+//
+// With a nil file the shard shares the file of w: it formats its entries in parallel and appends them to the
+// buffer of w, which writes, rotates and seals them. With a file of its own the shard also has its own buffer
+// and writes, rotates and seals its file like a worker created with the options of w. Start the shard with Work.
+func (w *Worker) NewShard(file *os.File, channel <-chan Entry, ring *Ring) *Worker {
+	options := w.options
+	shared := file == nil
+	if shared {
+		file = w.File()
+		options.MaxSize, options.Rotation, options.Compress, options.FileLock = 0, NoRotation, false, false
+		options.Encryption, options.AuditKey = nil, nil
+	}
+	shard := NewWorkerWithOptions(file, channel, w.errorCallback, options)
+	shard.ring = ring
+	shard.owner = w
+	shard.shardOf = w
+	if shared {
+		shard.into = w
+	}
+	w.lock.Lock()
+	shard.maxRetained = w.maxRetained
+	w.lock.Unlock()
+	w.routeLock.Lock()
+	w.shards = append(w.shards, shard)
+	w.routeLock.Unlock()
+	return shard
+}
+
+//This method returns the worker whose hooks, sinks and routes the worker uses: the worker it is a shard of, or
+// itself.
+func (w *Worker) base() *Worker {
+	if w.shardOf != nil {
+		return w.shardOf
+	}
+	return w
+}
+
+//This method closes the shards of the worker, writing the entries still queued for them, and returns the errors
+// of the final flushes of the shards with files of their own, keyed by file name.
+func (w *Worker) closeShards() map[string]error {
+	w.routeLock.RLock()
+	shards := w.shards
+	w.routeLock.RUnlock()
+	errs := make(map[string]error)
+	for _, shard := range shards {
+		if err := shard.CloseWorker(); err != nil && shard.into == nil {
+			errs[shard.File().Name()] = err
+		}
+	}
+	return errs
+}
@@ -0,0 +1,11 @@
+package logWriter
+
+import (
+	"io"
+)
+
+// Sink is a destination for formatted log output. *os.File satisfies it.
+type Sink interface {
+	io.Writer
+	Close() error
+}
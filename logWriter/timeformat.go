@@ -0,0 +1,49 @@
+package logWriter
+
+import (
+	"strconv"
+	"time"
+)
+
+// Layouts writing a timestamp as a Unix time, accepted wherever a timestamp layout is, e.g.
+// WorkerOptions.TimeLayout or JSONFormatter.TimestampFormat.
+const (
+	UnixSeconds = "unix"    //seconds since the epoch, e.g. 1588327200
+	UnixMillis  = "unix_ms" //milliseconds since the epoch, e.g. 1588327200123
+	UnixMicros  = "unix_us" //microseconds since the epoch
+	UnixNanos   = "unix_ns" //nanoseconds since the epoch
+)
+
+// FormatTime formats t with layout, a time.Format layout such as time.RFC3339Nano or one of the Unix layouts,
+// converting it to UTC first if utc is set.
+func FormatTime(t time.Time, layout string, utc bool) string {
+	var scratch [64]byte
+	return string(appendTime(scratch[:0], t, layout, utc))
+}
+
+//This method appends the timestamp FormatTime returns to dst.
+func appendTime(dst []byte, t time.Time, layout string, utc bool) []byte {
+	if utc {
+		t = t.UTC()
+	}
+	switch layout {
+	case UnixSeconds:
+		return strconv.AppendInt(dst, t.Unix(), 10)
+	case UnixMillis:
+		return strconv.AppendInt(dst, t.UnixNano()/int64(time.Millisecond), 10)
+	case UnixMicros:
+		return strconv.AppendInt(dst, t.UnixNano()/int64(time.Microsecond), 10)
+	case UnixNanos:
+		return strconv.AppendInt(dst, t.UnixNano(), 10)
+	}
+	return t.AppendFormat(dst, layout)
+}
+
+//This method reports whether layout is one of the Unix layouts, whose timestamps are numbers.
+func isUnixLayout(layout string) bool {
+	switch layout {
+	case UnixSeconds, UnixMillis, UnixMicros, UnixNanos:
+		return true
+	}
+	return false
+}
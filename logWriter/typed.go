@@ -0,0 +1,209 @@
+package logWriter
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"time"
+)
+
+// TypedField is a field built by one of the typed constructors, String, Int, Err and so on, for the logging
+// methods of a logger that take fields, e.g.
+//
+//	myLogger.Infow("request handled", logWriter.String("path", path), logWriter.Int("status", 200))
+//
+// The value is kept in a typed slot rather than an interface, so that the logging goroutine neither boxes the
+// values nor builds a Fields map; the worker turns the fields into Fields when it handles the entry, so that
+// hooks, formatters and sinks see them like any other field.
+type TypedField struct {
+	Key     string      //the key
+	kind    fieldKind   //which of the slots holds the value
+	integer int64       //value of integer, boolean, float and duration fields, and the Unix nanos of time fields
+	text    string      //value of string fields
+	value   interface{} //value of error and Any fields, and the location of time fields
+}
+
+//fieldKind is the type of the value of a TypedField.
+type fieldKind uint8
+
+const (
+	anyKind fieldKind = iota
+	stringKind
+	intKind
+	int64Kind
+	uint64Kind
+	float64Kind
+	boolKind
+	durationKind
+	timeKind
+	errorKind
+)
+
+// String returns a field with a string value.
+func String(key string, value string) TypedField {
+	return TypedField{Key: key, kind: stringKind, text: value}
+}
+
+// Int returns a field with an int value.
+func Int(key string, value int) TypedField {
+	return TypedField{Key: key, kind: intKind, integer: int64(value)}
+}
+
+// Int64 returns a field with an int64 value.
+func Int64(key string, value int64) TypedField {
+	return TypedField{Key: key, kind: int64Kind, integer: value}
+}
+
+// Uint64 returns a field with a uint64 value.
+func Uint64(key string, value uint64) TypedField {
+	return TypedField{Key: key, kind: uint64Kind, integer: int64(value)}
+}
+
+// Float64 returns a field with a float64 value.
+func Float64(key string, value float64) TypedField {
+	return TypedField{Key: key, kind: float64Kind, integer: int64(math.Float64bits(value))}
+}
+
+// Bool returns a field with a bool value.
+func Bool(key string, value bool) TypedField {
+	field := TypedField{Key: key, kind: boolKind}
+	if value {
+		field.integer = 1
+	}
+	return field
+}
+
+// Duration returns a field with a time.Duration value, written like time.Duration.String, e.g. 1.5s.
+func Duration(key string, value time.Duration) TypedField {
+	return TypedField{Key: key, kind: durationKind, integer: int64(value)}
+}
+
+// Time returns a field with a time.Time value.
+func Time(key string, value time.Time) TypedField {
+	return TypedField{Key: key, kind: timeKind, integer: value.UnixNano(), value: value.Location()}
+}
+
+// Err returns a field named "error" holding err, written as its message. A nil err gives a field that is left
+// out, so that Err can be passed unconditionally.
+func Err(err error) TypedField {
+	if err == nil {
+		return TypedField{kind: errorKind}
+	}
+	return TypedField{Key: "error", kind: errorKind, value: err}
+}
+
+// Any returns a field with a value of any type, rendered like the values of Fields.
+func Any(key string, value interface{}) TypedField {
+	return TypedField{Key: key, kind: anyKind, value: value}
+}
+
+// Value returns the value of the field as it appears in the entry's Fields.
+func (field TypedField) Value() interface{} {
+	switch field.kind {
+	case stringKind:
+		return field.text
+	case intKind:
+		return int(field.integer)
+	case int64Kind:
+		return field.integer
+	case uint64Kind:
+		return uint64(field.integer)
+	case float64Kind:
+		return math.Float64frombits(uint64(field.integer))
+	case boolKind:
+		return field.integer == 1
+	case durationKind:
+		return time.Duration(field.integer)
+	case timeKind:
+		location, _ := field.value.(*time.Location)
+		if location == nil {
+			location = time.UTC
+		}
+		return time.Unix(0, field.integer).In(location)
+	}
+	return field.value
+}
+
+// SetTypedFields attaches typed fields to the entry, in addition to the fields set with SetFields; a typed field
+// replaces a field of the same key. The entry keeps the slice, so it must not be modified afterwards.
+func (entry *Entry) SetTypedFields(fields []TypedField) {
+	entry.typed = fields
+}
+
+//This method reports whether the entry reaches nothing but the classic encoding of the worker, which appends its
+// typed fields as they are, so that the worker need not build Fields for it either.
+func (w *Worker) encodesTyped(entry Entry) bool {
+	if w.formatter != nil || w.redactor != nil || w.repeats != nil || len(entry.destination) > 0 ||
+		len(entry.fields) > 0 || len(entry.typed) > maxInlineFields {
+		return false
+	}
+	if current, _ := w.base().hooks.hooks.Load().([]Hook); len(current) > 0 {
+		return false
+	}
+	base := w.base()
+	base.routeLock.RLock()
+	defer base.routeLock.RUnlock()
+	return len(base.sinks) == 0
+}
+
+//This method appends the entry's typed fields like appendFields appends Fields, each after a space, in key order, a later field
+// replacing an earlier one of the same key. The entry must have no other fields and at most maxInlineFields
+// typed ones.
+func (entry Entry) appendTyped(b *bytes.Buffer) {
+	var ordered [maxInlineFields]int
+	n := 0
+	for i, field := range entry.typed {
+		if field.kind == errorKind && field.value == nil {
+			continue
+		}
+		j := 0
+		for j < n && entry.typed[ordered[j]].Key < field.Key {
+			j++
+		}
+		if j < n && entry.typed[ordered[j]].Key == field.Key {
+			ordered[j] = i
+			continue
+		}
+		copy(ordered[j+1:n+1], ordered[j:n])
+		ordered[j] = i
+		n++
+	}
+	var scratch [32]byte
+	for _, index := range ordered[:n] {
+		field := entry.typed[index]
+		b.WriteByte(' ')
+		b.WriteString(field.Key)
+		b.WriteByte('=')
+		switch field.kind {
+		case stringKind:
+			writeTextValue(b, field.text)
+		case intKind, int64Kind:
+			b.Write(strconv.AppendInt(scratch[:0], field.integer, 10))
+		case uint64Kind:
+			b.Write(strconv.AppendUint(scratch[:0], uint64(field.integer), 10))
+		case boolKind:
+			b.Write(strconv.AppendBool(scratch[:0], field.integer == 1))
+		case errorKind:
+			writeTextValue(b, field.value.(error).Error())
+		default:
+			b.WriteString(textValue(field.Value()))
+		}
+	}
+}
+
+//This method merges the entry's typed fields into a copy of its fields, which the worker does before anything
+// reads them.
+func (entry *Entry) resolveTyped() {
+	fields := make(Fields, len(entry.fields)+len(entry.typed))
+	for key, value := range entry.fields {
+		fields[key] = value
+	}
+	for _, field := range entry.typed {
+		if field.kind == errorKind && field.value == nil {
+			continue
+		}
+		fields[field.Key] = field.Value()
+	}
+	entry.fields = fields
+	entry.typed = nil
+}
@@ -0,0 +1,106 @@
+package logWriter
+
+import (
+	"os"
+	"time"
+)
+
+//This method moves the full buffer to the buffers waiting for the next vectored write, see
+// WorkerOptions.VectoredWrites, and continues with a spare buffer. It must be called with lock held.
+func (w *Worker) stage() {
+	w.ready = append(w.ready, w.buffer[:w.position])
+	w.readyBytes += w.position
+	if n := len(w.spare); n > 0 {
+		w.buffer = w.spare[n-1]
+		w.spare = w.spare[:n-1]
+	} else {
+		w.buffer = make([]byte, 0, w.capacity)
+	}
+	w.position = 0
+}
+
+//This method reports whether the full buffer is staged for a vectored write rather than written at once: the
+// worker gathers buffers, has gathered fewer than it writes together and does not encrypt them, as every
+// encrypted block is written on its own. It must be called with lock held.
+func (w *Worker) staging() bool {
+	return w.vectored > 1 && w.encrypter == nil && w.position > 0 && len(w.ready)+1 < w.vectored
+}
+
+//This method writes the staged buffers and the current one to the file with vectored writes, retrying like
+// writeWithRetry and purging rotated files like writeBuffer. It returns the bytes written and the bytes of the
+// buffers they hold. The buffers are recycled if the write succeeds and joined into the current buffer if it
+// fails, so that save handles the rest like a single buffer. It must be called with lock held.
+func (w *Worker) writeStaged() (written int, consumed int, err error) {
+	buffers := append(w.ready, w.buffer[:w.position])
+	backoff := w.retryBackoff
+	for attempt := 0; ; attempt++ {
+		var n int
+		n, err = writeVector(w.fileRoot, skipBytes(buffers, written))
+		written += n
+		if err == nil || attempt >= w.retries {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	if err == nil {
+		w.recycle()
+		return written, written, nil
+	}
+	w.join()
+	if w.diskFull == DiskFullPurge {
+		written, err = w.purgeRotated(w.buffer[:w.position], written, err)
+	}
+	return written, written, err
+}
+
+//This method puts the staged buffers back as spare buffers. It must be called with lock held.
+func (w *Worker) recycle() {
+	for i, b := range w.ready {
+		if cap(b) <= w.capacity && len(w.spare) < w.vectored {
+			w.spare = append(w.spare, b[:0])
+		}
+		w.ready[i] = nil
+	}
+	w.ready = w.ready[:0]
+	w.readyBytes = 0
+}
+
+//This method joins the staged buffers and the current one into the current buffer. It must be called with lock
+// held.
+func (w *Worker) join() {
+	joined := make([]byte, 0, w.readyBytes+w.position)
+	for _, b := range w.ready {
+		joined = append(joined, b...)
+	}
+	joined = append(joined, w.buffer[:w.position]...)
+	w.recycle()
+	w.buffer = joined
+	w.position = len(joined)
+}
+
+//Util method that returns the buffers without their first n bytes. The given slice is not modified.
+func skipBytes(buffers [][]byte, n int) [][]byte {
+	for len(buffers) > 0 && n >= len(buffers[0]) {
+		n -= len(buffers[0])
+		buffers = buffers[1:]
+	}
+	if n == 0 {
+		return buffers
+	}
+	rest := append([][]byte(nil), buffers...)
+	rest[0] = rest[0][n:]
+	return rest
+}
+
+//Util method that writes the buffers to the file one after the other and returns the bytes written.
+func writeSequential(file *os.File, buffers [][]byte) (written int, err error) {
+	for _, b := range buffers {
+		n, err := file.Write(b)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
@@ -1,30 +1,137 @@
 package logWriter
 
 import (
+	"fmt"
 	"github.com/shyamgrover/go-lite-logger/utils"
+	"io"
 	"log"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type Worker struct {
+	flushed       uint64              //entries written to the file, first for 64-bit atomic alignment
+	dropped       uint64              //entries discarded after failed flushes
+	written       uint64              //bytes written to the file
+	lastFlush     int64               //time of the last successful write to the file in Unix nanoseconds, 0 if none
 	once          sync.Once           //for singleton operations
 	fileRoot      *os.File            //file to which log entries would be written.
 	buffer        []byte              //temporarily keeps log entries before writing to file.
+	capacity      int                 //buffer size at which the buffer is flushed to file.
+	formatter     Formatter           //renders entries, nil for the classic text lines written by the log handles.
+	maxSize       int64               //file size above which the file is rotated, 0 to never rotate.
+	size          int64               //size of the file, maintained while maxSize or period is set.
+	period        RotationPeriod      //schedule on which the file is rotated.
+	periodStart   time.Time           //start of the period the file holds entries of.
+	compress      bool                //gzip rotated files in the background.
+	compressing   sync.WaitGroup      //compressions still running, waited for by CloseWorker.
 	position      int                 //position to maintain upto which index in buffer data is written to disk.
+	vectored      int                 //full buffers gathered into one vectored write, see WorkerOptions.VectoredWrites
+	ready         [][]byte            //full buffers waiting for the next vectored write, older than buffer
+	readyBytes    int                 //bytes in ready
+	spare         [][]byte            //emptied buffers reused by stage
 	Info          *log.Logger         //Info log handle.
 	Warning       *log.Logger         //Warning log handle.
 	Error         *log.Logger         //Error log handle.
 	Debug         *log.Logger         //Debug log handle.
+	Trace         *log.Logger         //Trace log handle.
+	Fatal         *log.Logger         //Fatal log handle.
+	Panic         *log.Logger         //Panic log handle.
 	channel       <-chan Entry        //Channel that will receive log entries.
+	ring          *Ring               //ring the entries are read from instead of channel, nil if not set
 	lock          sync.Mutex          //lock to synchronize between capacity and timer based flush to file.
-	ticker        *time.Ticker        //timer
+	clock         Clock               //source of time of the flushes and the flush timer
+	ticker        Ticker              //timer
 	quitTimer     chan struct{}       //stop timer channel
 	done          chan struct{}       //stop worker channel
+	stateLock     sync.Mutex          //guards working against a concurrent close
+	working       chan struct{}       //closed when Work returns, nil if Work was never started
 	errorCallback utils.ErrorFunction //user defined error callback function..to be invoked in case of error
+	errorHandler  ErrorHandler        //told what failed next to the error callback, nil if not set
+	retries       int                 //attempts to write the rest of the buffer after a failed write
+	retryBackoff  time.Duration       //wait before the first retry, doubled for every further one
+	fallback      io.Writer           //receives buffers the file failed to take, nil if not set
+	fallbackAfter int                 //failed flushes in a row after which the fallback is used
+	failures      int                 //failed flushes in a row
+	diskFull      DiskFullPolicy      //what to do when the disk is full
+	paused        bool                //set while DiskFullPause drops new entries
+	syncPolicy    SyncPolicy          //when the file is synced to stable storage
+	syncInterval  time.Duration       //least time between two syncs of SyncInterval
+	lastSync      time.Time           //time of the last sync of the file
+	unsynced      bool                //the file was written since the last sync
+	timeLayout    string              //layout of the timestamp of the classic text lines
+	utc           bool                //write the timestamps of the classic text lines in UTC
+	sequence      bool                //append the sequence number to the classic text lines
+	redactor      *Redactor           //masks sensitive data of entries, nil if not set
+	encrypter     *encrypter          //encrypts the buffers written to the file, nil to write them as they are
+	auditor       *auditor            //seals every record written in audit mode, nil if not set
+	fileMode      os.FileMode         //mode of the files created by rotation and compression
+	fileLock      bool                //lock the file while writing the buffer
+	repeats       *repeats            //collapses identical consecutive entries, nil if not set
+	routeLock     sync.RWMutex        //guards routes
+	routes        map[string]*Worker  //workers that receive the entries sent to a named destination
+	sinks         []*sinkRunner       //sinks that receive every entry, guarded by routeLock
+	sinkErrs      map[string]error    //errors of closing the sinks, keyed by sink name, and the shards, keyed by file name
+	hooks         hooks               //hooks added with AddHook and OnDelivery
+	owner         *Worker             //worker this worker is a route or a shard of, nil if none
+	shardOf       *Worker             //worker this worker is a shard of, whose hooks, sinks and routes it uses, nil if none
+	into          *Worker             //worker whose buffer the entries of a shard sharing its file go to, nil otherwise
+	shards        []*Worker           //shards added with NewShard, guarded by routeLock
+	options       WorkerOptions       //options the worker was created with, defaults filled in, copied by NewShard
+	queued        uint64              //sequence number of the entry being written to the buffer
+	firstSeq      uint64              //sequence number of the first entry in the buffer
+	lastSeq       uint64              //sequence number of the last entry in the buffer
+	lastError     atomic.Value        //*FlushError of the most recent flush failure
+	maxRetained   int                 //cap on the bytes kept after a failed flush, 0 to discard them
+	pending       uint64              //entries in the buffer
+	closeErr      error               //error of the final flush done by CloseWorker
 }
 
+// Counters are running totals of a worker and its routes.
+type Counters struct {
+	EntriesFlushed uint64    //entries written to the files
+	EntriesDropped uint64    //entries discarded after failed flushes
+	BytesWritten   uint64    //bytes written to the files
+	LastFlush      time.Time //time of the latest successful write to a file, zero if there was none
+}
+
+// WorkerOptions tune the buffering of a worker. Zero values select the defaults.
+type WorkerOptions struct {
+	BufferSize     int            //buffer size at which entries are flushed, 32 KiB by default
+	FlushInterval  time.Duration  //interval of the timer based flush, 10 seconds by default
+	Formatter      Formatter      //renders entries, the classic text lines by default
+	MaxSize        int64          //file size in bytes above which the file is rotated, 0 to never rotate
+	Rotation       RotationPeriod //schedule on which the file is rotated, NoRotation by default
+	Compress       bool           //gzip rotated files in the background
+	ErrorHandler   ErrorHandler   //told about every failure next to the error callback
+	RetryAttempts  int            //retries of a failed write before the failure is reported, 0 to report it at once
+	RetryBackoff   time.Duration  //wait before the first retry, doubled for every further one, 100ms by default
+	Fallback       io.Writer      //receives the buffer when the file keeps failing, shared with routes, nil to lose it
+	FallbackAfter  int            //failed flushes in a row after which Fallback is used, 1 by default
+	DiskFull       DiskFullPolicy //what to do when the disk is full, DiskFullDiscard by default
+	Sync           SyncPolicy     //when the file is synced to stable storage with fsync, SyncNever by default
+	SyncInterval   time.Duration  //least time between two syncs of SyncInterval, 1 second by default
+	TimeLayout     string         //layout of the timestamp of the classic text lines, "2006/01/02 15:04:05.000000" by default
+	UTC            bool           //write the timestamps of the classic text lines in UTC instead of local time
+	Sequence       bool           //append the entry's sequence number to the classic text lines as seq=N
+	Redactor       *Redactor      //masks sensitive data of every entry before it is written, nil to write entries as they are
+	Encryption     KeyProvider    //encrypts every buffer written to the file with AES-GCM, nil to write plain text
+	AuditKey       []byte         //HMAC key chaining every record to the previous one, nil to write records unsealed
+	FileMode       os.FileMode    //mode of the files created by rotation and compression, DefaultFileMode by default
+	FileLock       bool           //hold an advisory lock on the file while writing, for files shared by several processes
+	DedupWindow    time.Duration  //collapse identical consecutive entries logged within this window, 0 to write them all
+	Clock          Clock          //source of time of the flushes and the flush timer, SystemClock by default
+	VectoredWrites int            //full buffers gathered and written with one vectored write, 0 or 1 to write each at once
+}
+
+//least time between two syncs of SyncInterval unless WorkerOptions say otherwise.
+const defaultSyncInterval = time.Second
+
+//wait before the first retry of a failed write unless WorkerOptions say otherwise.
+const defaultRetryBackoff = 100 * time.Millisecond
+
 //default flush timer repeat interval in seconds.
 const defaultFlushLogsTimerInterval = 10
 
@@ -34,6 +141,9 @@ const capacity = 32768
 //default flag for log entries
 const defaultLogFlag = log.LstdFlags | log.Lmicroseconds | log.Lshortfile
 
+//layout of the timestamp of the classic text lines, the one written by defaultLogFlag.
+const classicTimeLayout = "2006/01/02 15:04:05.000000"
+
 //This returns a new instance of a worker. It takes file, channel(in read only mode) and callback as
 // arguments and returns a new worker. The returned worker reads continuously from channel and fills its buffer.
 // This buffer is flushed on to the disk to the given file. Flushing is of 2 types:
@@ -44,21 +154,86 @@ const defaultLogFlag = log.LstdFlags | log.Lmicroseconds | log.Lshortfile
 // not too frequent. In this case buffer will be lesser than its default capacity and will never flush
 // to the disk. So timer job will run and will flush the log entries to the file.
 func NewWorker(file *os.File, channel <-chan Entry, errorCallback utils.ErrorFunction) (worker *Worker) {
+	return NewWorkerWithOptions(file, channel, errorCallback, WorkerOptions{})
+}
+
+// NewWorkerWithOptions returns a new worker like NewWorker, with the buffer size and flush interval taken
+// from options.
+func NewWorkerWithOptions(file *os.File, channel <-chan Entry, errorCallback utils.ErrorFunction, options WorkerOptions) (worker *Worker) {
+	if options.BufferSize <= 0 {
+		options.BufferSize = capacity
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = defaultFlushLogsTimerInterval * time.Second
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = defaultRetryBackoff
+	}
+	if options.FileMode == 0 {
+		options.FileMode = DefaultFileMode
+	}
+	if options.FallbackAfter <= 0 {
+		options.FallbackAfter = 1
+	}
+	if len(options.TimeLayout) == 0 {
+		options.TimeLayout = classicTimeLayout
+	}
+	if options.Clock == nil {
+		options.Clock = SystemClock
+	}
+	if options.SyncInterval <= 0 {
+		options.SyncInterval = defaultSyncInterval
+	}
 	newWorker := Worker{
 		fileRoot:      file,
-		buffer:        make([]byte, capacity),
+		buffer:        make([]byte, options.BufferSize),
+		capacity:      options.BufferSize,
+		formatter:     options.Formatter,
+		maxSize:       options.MaxSize,
+		period:        options.Rotation,
+		compress:      options.Compress,
 		channel:       channel,
-		ticker:        time.NewTicker(defaultFlushLogsTimerInterval * time.Second),
+		clock:         options.Clock,
+		ticker:        options.Clock.NewTicker(options.FlushInterval),
 		quitTimer:     make(chan struct{}),
 		done:          make(chan struct{}),
 		errorCallback: errorCallback,
+		errorHandler:  options.ErrorHandler,
+		retries:       options.RetryAttempts,
+		retryBackoff:  options.RetryBackoff,
+		fallback:      options.Fallback,
+		fallbackAfter: options.FallbackAfter,
+		diskFull:      options.DiskFull,
+		syncPolicy:    options.Sync,
+		syncInterval:  options.SyncInterval,
+		timeLayout:    options.TimeLayout,
+		utc:           options.UTC,
+		sequence:      options.Sequence,
+		redactor:      options.Redactor,
+		fileMode:      options.FileMode,
+		fileLock:      options.FileLock,
+		vectored:      options.VectoredWrites,
+		options:       options,
+	}
+	if options.Encryption != nil {
+		newWorker.encrypter = &encrypter{keys: options.Encryption}
+	}
+	if options.DedupWindow > 0 {
+		newWorker.repeats = &repeats{window: options.DedupWindow}
+	}
+	if options.AuditKey != nil {
+		newWorker.auditor = &auditor{key: options.AuditKey, digest: resumeDigest(file.Name(), options.Encryption)}
+	}
+	if newWorker.maxSize > 0 || newWorker.period != NoRotation {
+		newWorker.size = fileSize(file)
+		newWorker.periodStart = newWorker.period.ofFile(file, options.Clock.Now())
 	}
 	newWorker.init()
 	return &newWorker
 }
 
-//This method will initialize the worker by creating different log handles say; Info, Error, Warning and
-// Debug. Also it will start a timer job(new go-routine) that would run periodically to flush the
+//This method will initialize the worker by creating different log handles say; Info, Error, Warning,
+// Debug, Trace, Fatal and Panic. Also it will start a timer job(new go-routine) that would run periodically to flush the
 // buffer(containing log entries) to the disk.
 func (w *Worker) init() {
 	w.createLogHandles()
@@ -77,86 +252,508 @@ func (w *Worker) fileExists() bool {
 
 //This is the overridden implementation of io.Writer interface. This method writes log entry on worker's
 // buffer. The method first checks if (previous buffer capacity + new log entry length) > buffer's capacity,
-// then it calls the save method on writer to save buffered entries and if save is successful, it will
-// copy new event data(received as argument to Write method) to the buffer. And will update the position
-// accordingly. If there is some error while writing buffer to file, then, provided callback method will be executed.
+// then it calls the save method on writer to save buffered entries, or with VectoredWrites stages the full buffer
+// to be written together with the next ones. Then it appends new event data(received as
+// argument to Write method) to the buffer and updates the position accordingly. If there is some error while
+// writing buffer to file, then, provided callback method will be executed; the failed contents are either
+// discarded or, if RetainOnFailure is set, kept in the buffer for the next flush. While DiskFullPause has paused
+// logging the data is dropped. Data larger than the buffer, e.g. a dumped payload, is written to the file at once
+// and the buffer returns to its configured size afterwards. In audit mode the data is sealed as one record of the
+// HMAC chain first.
 func (w *Worker) Write(data []byte) (n int, err error) {
-	length := len(data)
 	w.lock.Lock()
-	if (length + w.position) > capacity {
-		n, err = w.save()
-		if err != nil {
-			w.errorCallback()
-			return n, err
+	defer w.lock.Unlock()
+	return w.write(data)
+}
+
+//This method writes an entry with the given sequence number to the buffer, or to the buffer of the worker whose
+// file a shard shares.
+func (w *Worker) writeEntry(data []byte, sequence uint64) {
+	target := w
+	if w.into != nil {
+		target = w.into
+	}
+	target.lock.Lock()
+	target.queued = sequence
+	target.write(data)
+	target.lock.Unlock()
+}
+
+//This method is Write with lock held.
+func (w *Worker) write(data []byte) (n int, err error) {
+	written := len(data)
+	if w.paused {
+		w.dropPaused()
+		return written, nil
+	}
+	if w.auditor != nil {
+		data = w.auditor.seal(data)
+	}
+	length := len(data)
+	if (length+w.position) > w.capacity && w.staging() {
+		w.stage()
+	} else if (length + w.position) > w.capacity {
+		if _, err = w.save(); err != nil {
+			w.fail(w.flushError(), OpWrite, nil)
 		}
+		if w.paused {
+			w.dropPaused()
+			return written, nil
+		}
+		if w.position > 0 && w.position+length > w.maxRetained {
+			w.discard()
+		}
+	}
+	if w.position == 0 && len(w.ready) == 0 {
+		w.firstSeq = w.queued
 	}
-	copy(w.buffer[w.position:], data)
+	w.lastSeq = w.queued
+	w.buffer = append(w.buffer[:w.position], data...)
 	w.position += length
-	w.lock.Unlock()
-	return n, err
+	w.pending++
+	if w.position > w.capacity {
+		if _, err = w.save(); err != nil {
+			w.fail(w.flushError(), OpWrite, nil)
+		}
+		if w.position == 0 && cap(w.buffer) > w.capacity {
+			w.buffer = make([]byte, w.capacity)
+		}
+	}
+	return written, nil
 }
 
 //This method writes the buffered log entries to the file. This copies data from position 0 to buffer's
 // current length and after writing to file, if save is successful, it sets the buffer position to 0 and
-// if there is some error while writing to file, it will return error to its caller.
+// if there is some error while writing to file, it will return error to its caller. Failures are recorded
+// together with the sequence range of the buffered entries, see LastError. Bytes written before a failure
+// are removed from the buffer; the rest goes to the fallback writer once the file has failed often enough in a
+// row, and is otherwise kept for the next attempt if RetainOnFailure is set and discarded if not, so the position
+// always points at data that has not reached the file yet; encrypted buffers reach the file completely or not at
+// all. The error is returned even if the fallback took it. If a new rotation period has begun or the buffer would
+// make the file exceed its maximum size, the file is rotated first. Successful writes are reported to the
+// delivery hooks.
 func (w *Worker) save() (n int, err error) {
 	if w.position == 0 {
 		return 0, nil
 	}
+	if rotateErr := w.rotateIfNeeded(); rotateErr != nil {
+		w.recordError(rotateErr)
+		w.fail(rotateErr, OpRotate, nil)
+	}
 	if w.fileExists() {
-		n, err = w.fileRoot.Write(w.buffer[0:w.position])
+		var written int
+		start := w.clock.Now()
+		written, n, err = w.writeBuffer()
+		atomic.AddUint64(&w.written, uint64(written))
+		w.size += int64(written)
 		if err == nil {
+			w.failures = 0
+			w.paused = false
+			atomic.AddUint64(&w.flushed, w.pending)
+			now := w.clock.Now()
+			atomic.StoreInt64(&w.lastFlush, now.UnixNano())
+			w.deliver(written, now, now.Sub(start))
+			w.pending = 0
 			w.position = 0
+			w.wrote()
+			return written, nil
 		}
 	} else {
-		w.errorCallback()
+		err = fmt.Errorf("%w: %s", ErrFileMissing, w.fileRoot.Name())
+	}
+	w.recordError(err)
+	if n > 0 {
+		copy(w.buffer, w.buffer[n:w.position])
+		w.position -= n
+	}
+	w.failures++
+	diskFull := IsDiskFull(err)
+	if diskFull && w.diskFull == DiskFullPause {
+		w.paused = true
+		return n, err
+	}
+	if w.fallback != nil && (w.failures >= w.fallbackAfter || diskFull && w.diskFull == DiskFullFallback) {
+		if data, blockErr := w.block(w.buffer[0:w.position]); blockErr != nil {
+			w.recordError(blockErr)
+		} else if _, fallbackErr := w.fallback.Write(data); fallbackErr == nil {
+			atomic.AddUint64(&w.flushed, w.pending)
+			w.pending = 0
+			w.position = 0
+			return n, err
+		}
+	}
+	if w.maxRetained == 0 {
+		w.discard()
 	}
 	return n, err
 }
 
+//This method writes the buffer to the file, as an encrypted block if the worker encrypts its files, retrying and
+// purging rotated files as configured. It returns the bytes written to the file and the bytes of the buffer they
+// hold. A partly written encrypted block is truncated from the file, as it would make the rest of the file
+// unreadable, so encrypted buffers are either written completely or not at all. With FileLock the file is locked
+// for the write, so that buffers of processes sharing the file never interleave; if the lock cannot be taken the
+// failure is reported and the buffer is written anyway. Buffers staged for a vectored write are written together
+// with the buffer, see writeStaged.
+func (w *Worker) writeBuffer() (written int, consumed int, err error) {
+	data, err := w.block(w.buffer[0:w.position])
+	if err != nil {
+		return 0, 0, err
+	}
+	if w.fileLock {
+		if lockErr := lockFile(w.fileRoot); lockErr != nil {
+			w.fail(fmt.Errorf("locking %s: %w", w.fileRoot.Name(), lockErr), OpLock, nil)
+		} else {
+			defer unlockFile(w.fileRoot)
+		}
+	}
+	if len(w.ready) > 0 {
+		return w.writeStaged()
+	}
+	var offset int64
+	if w.encrypter != nil {
+		if info, statErr := w.fileRoot.Stat(); statErr == nil {
+			offset = info.Size()
+		}
+	}
+	written, err = w.writeWithRetry(data)
+	if err != nil && w.diskFull == DiskFullPurge {
+		written, err = w.purgeRotated(data, written, err)
+	}
+	if w.encrypter == nil {
+		return written, written, err
+	}
+	if err == nil {
+		return written, w.position, nil
+	}
+	if written > 0 && w.fileRoot.Truncate(offset) == nil {
+		written = 0
+	}
+	return written, 0, err
+}
+
+//This method writes data to the file. After a failed write, e.g. a transient NFS error, it waits and writes the
+// rest of data again, up to the worker's retry attempts, doubling the wait every time. It returns the bytes
+// written in total and the error of the last attempt. It must be called with lock held, so the worker waits too.
+func (w *Worker) writeWithRetry(data []byte) (written int, err error) {
+	backoff := w.retryBackoff
+	for attempt := 0; ; attempt++ {
+		n, writeErr := w.fileRoot.Write(data[written:])
+		written += n
+		if writeErr == nil || attempt >= w.retries {
+			return written, writeErr
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+//This method empties the buffer and counts its entries as dropped. It must be called with lock held.
+func (w *Worker) discard() {
+	w.recycle()
+	atomic.AddUint64(&w.dropped, w.pending)
+	w.pending = 0
+	w.position = 0
+}
+
+// Counters returns the running totals of the worker, its shards, its routes and its sinks. Entries written to several sinks
+// are counted once for each.
+func (w *Worker) Counters() Counters {
+	counters := Counters{
+		EntriesFlushed: atomic.LoadUint64(&w.flushed),
+		EntriesDropped: atomic.LoadUint64(&w.dropped),
+		BytesWritten:   atomic.LoadUint64(&w.written),
+	}
+	if lastFlush := atomic.LoadInt64(&w.lastFlush); lastFlush > 0 {
+		counters.LastFlush = time.Unix(0, lastFlush)
+	}
+	w.routeLock.RLock()
+	defer w.routeLock.RUnlock()
+	workers := append([]*Worker(nil), w.shards...)
+	for _, route := range w.routes {
+		workers = append(workers, route)
+	}
+	for _, route := range workers {
+		routeCounters := route.Counters()
+		counters.EntriesFlushed += routeCounters.EntriesFlushed
+		counters.EntriesDropped += routeCounters.EntriesDropped
+		counters.BytesWritten += routeCounters.BytesWritten
+		if routeCounters.LastFlush.After(counters.LastFlush) {
+			counters.LastFlush = routeCounters.LastFlush
+		}
+	}
+	for _, runner := range w.sinks {
+		counters.EntriesFlushed += atomic.LoadUint64(&runner.delivered)
+		counters.EntriesDropped += atomic.LoadUint64(&runner.dropped)
+	}
+	return counters
+}
+
+// RetainOnFailure makes the worker, and its shards and routes, keep the contents of a buffer that failed to flush and
+// write them again on the next flush, instead of discarding them. The retained contents grow the buffer up to
+// maxBytes; once new entries would exceed that cap the retained contents are discarded. Zero, the default,
+// disables retention.
+func (w *Worker) RetainOnFailure(maxBytes int) {
+	w.lock.Lock()
+	w.maxRetained = maxBytes
+	w.lock.Unlock()
+
+	w.routeLock.RLock()
+	defer w.routeLock.RUnlock()
+	for _, shard := range w.shards {
+		shard.RetainOnFailure(maxBytes)
+	}
+	for _, route := range w.routes {
+		route.RetainOnFailure(maxBytes)
+	}
+}
+
+//This method records a flush failure of the current buffer. It must be called with lock held.
+func (w *Worker) recordError(err error) {
+	w.lastError.Store(&FlushError{Err: err, FirstSequence: w.firstSeq, LastSequence: w.lastSeq, Time: w.clock.Now()})
+}
+
+//This method returns the most recent flush failure of the worker itself, as recorded by recordError.
+func (w *Worker) flushError() error {
+	if err, ok := w.lastError.Load().(*FlushError); ok {
+		return err
+	}
+	return nil
+}
+
+//This method reports a failure to the error callback and the error handler, if any.
+func (w *Worker) fail(err error, op string, entry *Entry) {
+	w.errorCallback()
+	if w.errorHandler != nil {
+		w.errorHandler(err, op, entry)
+	}
+}
+
+// LastError returns the most recent flush failure of the worker or of one of its routes as a *FlushError,
+// or nil if no flush has failed. It is meant to be called from the error callback to find out which entries
+// were affected. It does not take the buffer lock, so it is safe to call while the callback runs.
+func (w *Worker) LastError() error {
+	latest, _ := w.lastError.Load().(*FlushError)
+
+	w.routeLock.RLock()
+	defer w.routeLock.RUnlock()
+	for _, route := range w.routes {
+		if err, ok := route.LastError().(*FlushError); ok && (latest == nil || err.Time.After(latest.Time)) {
+			latest = err
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+	return latest
+}
+
 //Worker spends most of the time in this method. This method is called as a separate goroutine after
 // instantiating the worker. The method checks in an infinite loop if worker is closed or not. If closed, it returns
 // from the method and if not, reads continuously from channel and fills its buffer.
 func (w *Worker) Work() {
+	w.stateLock.Lock()
+	select {
+	case <-w.done:
+		w.stateLock.Unlock()
+		return
+	default:
+	}
+	stopped := make(chan struct{})
+	w.working = stopped
+	w.stateLock.Unlock()
+	defer close(stopped)
+
+	if w.ring != nil {
+		w.workRing()
+		return
+	}
+	for {
+		select {
+		case <-w.done:
+			return
+		case event := <-w.channel:
+			w.handle(event)
+		}
+	}
+}
+
+//This method is the loop of Work for a worker reading a ring: it handles entries as long as there are any and
+// waits for the producers when the ring is empty, until the worker is closed.
+func (w *Worker) workRing() {
 	for {
 		select {
 		case <-w.done:
 			return
 		default:
-			event := <-w.channel
-			w.writeToBuffer(event)
+		}
+		if event, ok := w.ring.Poll(); ok {
+			w.handle(event)
+		} else if !w.ring.wait(w.done) {
+			return
 		}
 	}
 }
 
-//This method checks entry's log level and format and calls appropriate handle to write it to the buffer.
-func (w *Worker) writeToBuffer(event Entry) {
-	switch event.level {
-	case WarnLevel:
-		if len(event.format) > 0 {
-			w.Warning.Printf(event.format, event.message)
-		} else {
-			w.Warning.Println(event.message)
+// HandleNow handles the entry on the calling goroutine, as Work does with the entries it reads, and writes the
+// buffers of the worker and of its routes to their files before it returns, for loggers that write
+// synchronously. It returns the error of the first failed write, which is also reported like that of any flush.
+// Calls must not overlap, and a worker handling entries this way must not be started with Work.
+func (w *Worker) HandleNow(entry Entry) error {
+	w.handle(entry)
+	if entry.IsFlush() {
+		return nil
+	}
+	err := w.saveNow()
+	w.routeLock.RLock()
+	defer w.routeLock.RUnlock()
+	for _, route := range w.routes {
+		if routeErr := route.saveNow(); err == nil {
+			err = routeErr
 		}
-	case InfoLevel:
-		if len(event.format) > 0 {
-			w.Info.Printf(event.format, event.message)
-		} else {
-			w.Info.Println(event.message)
+	}
+	return err
+}
+
+//This method writes the buffer to the file, if it holds anything, and reports a failure like that of any flush.
+func (w *Worker) saveNow() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	_, err := w.save()
+	if err != nil {
+		w.fail(w.flushError(), OpWrite, nil)
+	}
+	return err
+}
+
+//This method processes an entry received from the channel: flush requests are answered with the result of
+// flushing the buffer and log entries are run through the hooks and, unless a hook vetoes them, redacted, handed
+// to the sinks and written to the buffer, unless they repeat the entry before them, see DedupWindow.
+func (w *Worker) handle(event Entry) {
+	if event.flushed != nil {
+		event.flushed <- w.Flush()
+		return
+	}
+	if event.typed != nil && !w.encodesTyped(event) {
+		event.resolveTyped()
+	}
+	if current, _ := w.base().hooks.hooks.Load().([]Hook); len(current) > 0 || w.redactor != nil {
+		var kept bool
+		if event, kept = w.intercept(event); !kept {
+			return
 		}
-	case DebugLevel:
-		if len(event.format) > 0 {
-			w.Debug.Printf(event.format, event.message)
-		} else {
-			w.Debug.Println(event.message)
+	}
+	if w.repeats != nil {
+		w.repeats.handle(w, event)
+		return
+	}
+	w.emit(event)
+}
+
+//This method runs the entry through the hooks and redacts it, and reports whether a hook vetoed it. It is kept
+// apart from handle because taking the entry's address moves it to the heap, which entries of workers without
+// hooks or redaction are spared.
+func (w *Worker) intercept(event Entry) (Entry, bool) {
+	if !w.fireHooks(&event) {
+		return event, false
+	}
+	if w.redactor != nil {
+		w.redactor.Redact(&event)
+	}
+	return event, true
+}
+
+//This method hands the entry to the sinks, unless it is for the file only, and writes it to the buffer.
+func (w *Worker) emit(event Entry) {
+	if !event.fileOnly {
+		w.fanOut(event)
+	}
+	w.writeToBuffer(event)
+	if w.syncPolicy == SyncOnError && ErrorLevel.Enables(event.level) {
+		w.flushAndSync()
+	}
+}
+
+// Flush writes the buffered log entries of the worker and of its routes to their files, waits for the sinks to
+// write the entries queued for them and returns the first write error, if any. The repetitions of suppressed
+// duplicates are written first.
+func (w *Worker) Flush() error {
+	w.endRepeats(true)
+	w.lock.Lock()
+	_, err := w.save()
+	w.lock.Unlock()
+
+	w.routeLock.RLock()
+	defer w.routeLock.RUnlock()
+	for _, route := range w.routes {
+		if routeErr := route.Flush(); err == nil {
+			err = routeErr
 		}
-	case ErrorLevel:
-		if len(event.format) > 0 {
-			w.Error.Printf(event.format, event.message)
-		} else {
-			w.Error.Println(event.message)
+	}
+	for _, runner := range w.sinks {
+		if sinkErr := runner.flush(); err == nil {
+			err = sinkErr
+		}
+	}
+	return err
+}
+
+// AddRoute makes the worker hand entries whose destination is name to route. The route worker is not started
+// with Work; it only buffers and flushes the entries it receives, and it is closed together with this worker.
+func (w *Worker) AddRoute(name string, route *Worker) error {
+	w.routeLock.Lock()
+	defer w.routeLock.Unlock()
+	if _, exists := w.routes[name]; exists {
+		return fmt.Errorf("destination %q already exists", name)
+	}
+	for _, runner := range w.sinks {
+		if runner.name == name {
+			return fmt.Errorf("sink %q already exists", name)
 		}
 	}
+	if w.routes == nil {
+		w.routes = make(map[string]*Worker)
+	}
+	w.lock.Lock()
+	maxRetained := w.maxRetained
+	w.lock.Unlock()
+	route.RetainOnFailure(maxRetained)
+	route.lock.Lock()
+	route.owner = w
+	route.lock.Unlock()
+	w.routes[name] = route
+	return nil
+}
+
+//This method returns the route for the given destination, or nil if there is none.
+func (w *Worker) route(destination string) *Worker {
+	base := w.base()
+	base.routeLock.RLock()
+	defer base.routeLock.RUnlock()
+	return base.routes[destination]
+}
+
+//This method checks entry's log level and calls appropriate handle to write its message to the buffer, or
+// writes the output of the worker's formatter if it has one. Entries for a destination that has a route are
+// handed to the route instead.
+func (w *Worker) writeToBuffer(event Entry) {
+	if len(event.destination) > 0 {
+		if route := w.route(event.destination); route != nil {
+			route.writeToBuffer(event)
+			return
+		}
+	}
+	if w.formatter != nil {
+		data, err := w.formatter.Format(event)
+		if err != nil {
+			failed := event
+			w.fail(err, OpFormat, &failed)
+			return
+		}
+		w.writeEntry(data, event.sequence)
+		return
+	}
+	w.encodeClassic(event)
 }
 
 //This method is used to close the worker resources. First it will stop the timer by closing quitTimer channel,
@@ -164,25 +761,88 @@ func (w *Worker) writeToBuffer(event Entry) {
 // over the channel length(if there were some entries remaining on channel) and writes to buffer. Now, if the capacity
 // is full in between, capacity based flushing will run automatically and finally if the buffer content is less than
 // its capacity, the after loop exit, save method will be called to flush off the buffer to file. This way all
-// buffer data and channel entries are flushed on to disk on worker close.
-func (w *Worker) CloseWorker() {
+// buffer data and channel entries are flushed on to disk on worker close. Closing waits for the Work goroutine
+// to return first, so that no entry is written concurrently with the final drain, and for compressions of rotated
+// files to finish. Sinks are closed after writing the entries queued for them. Entries still in the buffer when
+// the final flush fails are counted as dropped. It returns the error of the final flush, if any; later calls
+// return the same error.
+func (w *Worker) CloseWorker() error {
 	w.once.Do(func() {
+		w.stateLock.Lock()
 		close(w.done)
+		stopped := w.working
+		w.stateLock.Unlock()
+		if stopped != nil {
+			<-stopped
+		}
 		close(w.quitTimer)
+		shardErrs := w.closeShards()
 
 		w.lock.Lock()
-		w.save()
+		if _, err := w.save(); err != nil {
+			w.closeErr = err
+			w.fail(w.flushError(), OpWrite, nil)
+		}
 		w.lock.Unlock()
 
 		length := len(w.channel)
 		for i := 0; i < length; i++ {
 			event := <-w.channel
-			w.writeToBuffer(event)
+			w.handle(event)
 		}
+		for w.ring != nil {
+			event, ok := w.ring.Poll()
+			if !ok {
+				break
+			}
+			w.handle(event)
+		}
+		w.endRepeats(true)
 		w.lock.Lock()
-		w.save()
+		if _, err := w.save(); err != nil {
+			w.closeErr = err
+			w.discard()
+			w.fail(w.flushError(), OpWrite, nil)
+		} else if w.syncPolicy == SyncInterval {
+			w.syncFile()
+		}
 		w.lock.Unlock()
+
+		w.routeLock.RLock()
+		for _, route := range w.routes {
+			route.CloseWorker()
+		}
+		w.sinkErrs = shardErrs
+		for _, runner := range w.sinks {
+			if err := runner.close(); err != nil {
+				w.sinkErrs[runner.name] = err
+			}
+		}
+		w.routeLock.RUnlock()
+		w.compressing.Wait()
 	})
+	return w.closeErr
+}
+
+// CloseErrors closes the worker if it is still open and returns the errors of the final flushes, keyed by
+// destination name, with the worker's own error under the empty name, and the errors of closing the sinks,
+// keyed by sink name. Destinations and sinks that closed cleanly are not included.
+func (w *Worker) CloseErrors() map[string]error {
+	errs := make(map[string]error)
+	if err := w.CloseWorker(); err != nil {
+		errs[""] = err
+	}
+	w.routeLock.RLock()
+	defer w.routeLock.RUnlock()
+	for name, route := range w.routes {
+		if err := route.CloseWorker(); err != nil {
+			errs[name] = err
+		}
+	}
+	for name, err := range w.sinkErrs {
+		errs[name] = err
+	}
+	return errs
 }
 
 //This method starts a timer job that is initiated when new worker is instantiated and it runs periodically
@@ -193,11 +853,14 @@ func (w *Worker) doTimerJob() {
 	go func() {
 		for {
 			select {
-			case <-w.ticker.C:
+			case <-w.ticker.C():
+				w.endRepeats(false)
 				w.lock.Lock()
 				_, err := w.save()
 				if err != nil {
-					w.errorCallback()
+					w.fail(w.flushError(), OpWrite, nil)
+				} else if w.syncPolicy == SyncInterval {
+					w.syncIfDue()
 				}
 				w.lock.Unlock()
 			case <-w.quitTimer:
@@ -226,4 +889,16 @@ func (w *Worker) createLogHandles() {
 	w.Debug = log.New(w,
 		"[DEBUG] ",
 		defaultLogFlag)
+
+	w.Trace = log.New(w,
+		"[TRACE] ",
+		defaultLogFlag)
+
+	w.Fatal = log.New(w,
+		"[FATAL] ",
+		defaultLogFlag)
+
+	w.Panic = log.New(w,
+		"[PANIC] ",
+		defaultLogFlag)
 }
@@ -0,0 +1,58 @@
+//go:build linux
+
+package logWriter
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+//most buffers passed to one writev call, IOV_MAX on Linux.
+const maxIovecs = 1024
+
+//Util method that writes the buffers to the file with as few writev calls as possible and returns the bytes
+// written. Short writes are continued; errors are returned as *os.PathError like those of os.File.Write.
+func writeVector(file *os.File, buffers [][]byte) (written int, err error) {
+	conn, err := file.SyscallConn()
+	if err != nil {
+		return writeSequential(file, buffers)
+	}
+	iovecs := make([]syscall.Iovec, 0, len(buffers))
+	for {
+		iovecs = iovecs[:0]
+		for _, b := range buffers {
+			if len(b) == 0 {
+				continue
+			}
+			iovec := syscall.Iovec{Base: &b[0]}
+			iovec.SetLen(len(b))
+			if iovecs = append(iovecs, iovec); len(iovecs) == maxIovecs {
+				break
+			}
+		}
+		if len(iovecs) == 0 {
+			return written, nil
+		}
+		var n uintptr
+		var errno syscall.Errno
+		if err = conn.Write(func(fd uintptr) bool {
+			n, _, errno = syscall.Syscall(syscall.SYS_WRITEV, fd, uintptr(unsafe.Pointer(&iovecs[0])),
+				uintptr(len(iovecs)))
+			return errno != syscall.EAGAIN
+		}); err != nil {
+			return written, err
+		}
+		switch {
+		case errno == syscall.EINTR:
+			continue
+		case errno != 0:
+			return written, &os.PathError{Op: "writev", Path: file.Name(), Err: errno}
+		case n == 0:
+			return written, io.ErrShortWrite
+		}
+		written += int(n)
+		buffers = skipBytes(buffers, int(n))
+	}
+}
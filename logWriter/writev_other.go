@@ -0,0 +1,11 @@
+//go:build !linux
+
+package logWriter
+
+import "os"
+
+//Util method that writes the buffers to the file one after the other, as there is no vectored write on this
+// platform, and returns the bytes written.
+func writeVector(file *os.File, buffers [][]byte) (int, error) {
+	return writeSequential(file, buffers)
+}
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"net/http"
+)
+
+//adminState is the body of the requests and responses of the level handler. The fields of a PUT body are
+// optional; the ones left out are not changed.
+type adminState struct {
+	Level  *string `json:"level,omitempty"`  //name of the level, as accepted by logWriter.ParseLevel
+	Status *bool   `json:"status,omitempty"` //whether logging is on
+}
+
+// LevelHandler returns an http.Handler that reads and changes the level and status of the logger at runtime,
+// to be mounted on an existing mux, e.g.
+//
+//	mux.Handle("/admin/log", myLogger.LevelHandler())
+//
+// GET returns {"level":"info","status":true}. PUT takes the same object, with either field left out to keep
+// it, applies it and returns the new state, e.g. curl -X PUT -d '{"level":"debug"}'. An unknown level or a
+// malformed body is answered with 400 and nothing is changed. On a logger returned by Named the handler reads
+// and sets the level of the module. The handler does no authentication; mount it where only operators reach it.
+func (logger *Logger) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPut:
+			var state adminState
+			if err := json.NewDecoder(r.Body).Decode(&state); err != nil {
+				http.Error(w, fmt.Sprintf("invalid body: %v", err), http.StatusBadRequest)
+				return
+			}
+			if state.Level != nil {
+				level, err := logWriter.ParseLevel(*state.Level)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				logger.SetLevel(level)
+			}
+			if state.Status != nil {
+				logger.SetStatus(*state.Status)
+			}
+		default:
+			w.Header().Set("Allow", "GET, HEAD, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		level, status := logger.GetLevel().String(), logger.status.Get()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(adminState{Level: &level, Status: &status})
+	})
+}
@@ -0,0 +1,85 @@
+package logger_test
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"path/filepath"
+	"testing"
+)
+
+//This method returns an Info level logger writing to a file in a temporary directory, which is closed with the
+// test.
+func newTestLogger(tb testing.TB, options ...logger.Option) *logger.Logger {
+	options = append([]logger.Option{logger.WithFile(filepath.Join(tb.TempDir(), "app.log")),
+		logger.WithLevel(logWriter.InfoLevel)}, options...)
+	myLogger, err := logger.New(options...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { myLogger.CloseLogger() })
+	return myLogger
+}
+
+//allocCase is a logging call and the heap allocations it may make per entry, including those of the worker.
+type allocCase struct {
+	name      string
+	maxAllocs float64
+	log       func(l *logger.Logger)
+}
+
+//allocCases are the common logging calls, from the cheapest to the most expensive.
+var allocCases = []allocCase{
+	{"string", 0, func(l *logger.Logger) { l.Info("request handled") }},
+	{"string/filtered", 0, func(l *logger.Logger) { l.Debug("request handled") }},
+	{"fields", 0, nil},
+	{"fields/per-call", 2, func(l *logger.Logger) {
+		l.WithFields(logWriter.Fields{"method": "GET", "status": 200}).Info("request handled")
+	}},
+	{"fields/typed", 2, func(l *logger.Logger) {
+		l.Infow("request handled", logWriter.String("method", "GET"), logWriter.Int("status", 200))
+	}},
+	{"args", 2, func(l *logger.Logger) { l.Info("request handled in", 42, "ms") }},
+	{"formatted", 2, func(l *logger.Logger) { l.Infof("request %s handled in %d ms", "GET /orders", 42) }},
+}
+
+//This method returns the logging call of the case; the fields case logs through a logger carrying the fields.
+func (c allocCase) call(l *logger.Logger) func() {
+	if c.log == nil {
+		fielded := l.With("method", "GET", "status", 200)
+		return func() { fielded.Info("request handled") }
+	}
+	return func() { c.log(l) }
+}
+
+// TestAllocations guards the allocation free path of plain messages and of loggers with fields, and keeps the
+// other common calls within their allocations.
+func TestAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	for _, c := range allocCases {
+		myLogger := newTestLogger(t)
+		if allocs := testing.AllocsPerRun(1000, c.call(myLogger)); allocs > c.maxAllocs {
+			t.Errorf("%s: %v allocations per entry, want at most %v", c.name, allocs, c.maxAllocs)
+		}
+	}
+}
+
+// BenchmarkLogging measures the time and allocations of the common logging calls, from the call to the worker
+// writing the entry to its buffer.
+func BenchmarkLogging(b *testing.B) {
+	for _, c := range allocCases {
+		b.Run(c.name, func(b *testing.B) {
+			myLogger := newTestLogger(b)
+			log := c.call(myLogger)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				log()
+			}
+			if err := myLogger.Flush(); err != nil {
+				b.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package logger
+
+import "github.com/shyamgrover/go-lite-logger/logWriter"
+
+// These constants report which levels are compiled into the binary (see MaxLevel). Logging methods for a
+// stripped level return immediately, but Go still evaluates their arguments at the call site. Guard expensive
+// arguments with one of these constants, or use the *func variants, to guarantee zero overhead:
+//
+//	if logger.DebugEnabled {
+//		myLogger.Debug(dumpState())
+//	}
+const (
+	TraceEnabled = MaxLevel >= logWriter.TraceLevel
+	DebugEnabled = MaxLevel >= logWriter.DebugLevel
+	InfoEnabled  = MaxLevel >= logWriter.InfoLevel
+	WarnEnabled  = MaxLevel >= logWriter.WarnLevel
+)
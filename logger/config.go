@@ -0,0 +1,655 @@
+package logger
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/utils"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config describes a logger in a config file. Every key of the file must map to a field of Config; unknown
+// keys and values of the wrong type are reported with their line and column by LoadConfig.
+type Config struct {
+	Level    string     `json:"level"`    //logger level: panic, fatal, error, warn, info, debug, trace or a custom level
+	File     string     `json:"file"`     //log file name
+	Dir      string     `json:"dir"`      //directory of the log file, created if it does not exist
+	Format   string     `json:"format"`   //output format: text, the default, json, ecs, csv, pattern, binary or a registered one
+	Pattern  string     `json:"pattern"`  //line layout of the pattern format, e.g. "%t %-7L %m %f", see logWriter.PatternFormatter
+	MaxSize  utils.Size `json:"max_size"` //size at which the log file is rotated, e.g. "100MB", 0 to never rotate
+	Rotate   string     `json:"rotate"`   //schedule on which the log file is rotated: hourly, daily or none
+	Compress bool       `json:"compress"` //gzip rotated files
+
+	FormatOptions map[string]interface{} `json:"format_options"` //settings of a registered format, see logWriter.RegisterFormatter
+
+	LevelFiles  map[string]string `json:"level_files"`  //files of the entries at some levels, keyed by level, e.g. {"error": "error.log"}
+	StderrLevel string            `json:"stderr_level"` //least severe level also written to stderr, e.g. warn, none by default
+
+	Sinks map[string]map[string]interface{} `json:"sinks"` //settings of registered sinks keyed by name, see logWriter.RegisterSink
+
+	FileMode     string `json:"file_mode"`     //octal mode of created log files, e.g. "0640", 0644 by default
+	DirMode      string `json:"dir_mode"`      //octal mode of created directories, e.g. "0750", 0755 by default
+	PrivateFiles bool   `json:"private_files"` //refuse to write to log files others can read
+	FileLock     bool   `json:"file_lock"`     //lock the log file while writing, for files shared by several processes
+
+	TimeLayout string `json:"time_layout"` //layout of the timestamps, e.g. "2006-01-02T15:04:05.000Z07:00" or "unix_ms"
+	UTC        bool   `json:"utc"`         //write timestamps in UTC
+	Sequence   bool   `json:"sequence"`    //write the sequence number of every entry, as seq=N or a "seq" key
+	StackLevel string `json:"stack_level"` //least severe level whose entries get a stack trace, e.g. error, none by default
+	StackDepth int    `json:"stack_depth"` //frames of the stack traces, 32 by default
+	App        string `json:"app"`         //application name; when set, every entry gets app, host and pid fields
+
+	TraceOnError int `json:"trace_on_error"` //filtered out Debug and Trace entries kept and written before an Error entry
+
+	RedactKeys     []string `json:"redact_keys"`     //keys of the fields whose values are masked, e.g. ["password", "token"]
+	RedactPatterns []string `json:"redact_patterns"` //regular expressions scrubbed from messages, or credit_card and email
+	EncryptionKey  string   `json:"encryption_key"`  //file holding the hex encoded AES key the log files are encrypted with
+	AuditKey       string   `json:"audit_key"`       //file holding the hex encoded HMAC key of audit mode, which it enables
+
+	BufferSize     utils.Size     `json:"buffer_size"`     //size at which buffered entries are flushed, e.g. "64KB", 32 KiB by default
+	FlushInterval  utils.Duration `json:"flush_interval"`  //how often buffered entries are flushed, e.g. "5s", 10 seconds by default
+	ChannelSize    int            `json:"channel_size"`    //entries the channel to the worker holds, 2048 by default
+	VectoredWrites int            `json:"vectored_writes"` //full buffers written with one vectored write, 0 to write each at once
+	Overflow       string         `json:"overflow"`        //what logging does when the channel is full: block, drop_newest or drop_oldest
+	Queue          string         `json:"queue"`           //how entries reach the worker: "channel", the default, or "ring"
+	Synchronous    bool           `json:"synchronous"`     //write every entry on the logging goroutine, bypassing the channel
+	Shards         int            `json:"shards"`          //workers the entries are spread over, 1 by default
+	ShardBy        string         `json:"shard_by"`        //which worker handles an entry: caller, the default, logger or round_robin
+	ShardFiles     bool           `json:"shard_files"`     //give every worker a file of its own
+
+	RetryAttempts int            `json:"retry_attempts"` //retries of a failed write, 0 to report it at once
+	RetryBackoff  utils.Duration `json:"retry_backoff"`  //wait before the first retry, e.g. "250ms", doubled for every further one
+	Fallback      string         `json:"fallback"`       //where entries go when the log file keeps failing: stderr or a file path
+	FallbackAfter int            `json:"fallback_after"` //failed flushes in a row before the fallback is used, 1 by default
+	DiskFull      string         `json:"disk_full"`      //what to do when the disk is full: discard, pause, fallback or purge
+	Fsync         string         `json:"fsync"`          //when the file is synced to stable storage: never, interval, flush or error
+	FsyncInterval utils.Duration `json:"fsync_interval"` //least time between two syncs of the interval policy, e.g. "5s", 1 second by default
+
+	Sampling    map[string]SamplingConfig `json:"sampling"`     //sampling policies keyed by level, e.g. {"debug": {"first": 100}}
+	DedupWindow utils.Duration            `json:"dedup_window"` //collapse identical consecutive entries within this window, e.g. "10s"
+}
+
+// SamplingConfig describes the sampling of a level in a config file, see Sampling.
+type SamplingConfig struct {
+	Interval   utils.Duration `json:"interval"`   //window the counts are kept for, e.g. "1s", one second by default
+	First      int            `json:"first"`      //entries of a message logged per interval before sampling starts
+	Thereafter int            `json:"thereafter"` //after first, every thereafter-th entry is logged
+	Rate       float64        `json:"rate"`       //probability with which the other entries are logged
+}
+
+// ConfigError describes a problem found at a position in a config file.
+type ConfigError struct {
+	File   string //config file path
+	Line   int    //1-based line of the offending key or value
+	Column int    //1-based column of the offending key or value
+	Key    string //dotted path of the offending key, empty for syntax errors
+	Msg    string //description of the problem
+}
+
+func (e *ConfigError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s: %s", e.File, e.Key, e.Msg)
+	}
+	if len(e.Key) > 0 {
+		return fmt.Sprintf("%s:%d:%d: %s: %s", e.File, e.Line, e.Column, e.Key, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+}
+
+// ConfigErrors is the list of all problems found in a config file. LoadConfig returns it, rather than the
+// first problem only, so that a misconfigured file can be fixed in one go.
+type ConfigErrors []*ConfigError
+
+func (errs ConfigErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// LoadConfig reads and validates the config file at path and then applies the environment overrides described
+// by EnvironmentPrefix. Files ending in .yaml or .yml are read as YAML, files ending in .toml as TOML and all
+// others as JSON; the keys are the same in every format. Validation checks every key against the fields of Config and every value
+// against the field's type; if anything is wrong the returned error is a ConfigErrors value listing each
+// problem with its position in the file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	root, err := parseConfig(path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs ConfigErrors
+	validateConfigNode(path, root, reflect.TypeOf(Config{}), "", &errs)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	config := &Config{}
+	if err = root.decode(config); err != nil {
+		return nil, err
+	}
+	overridden, err := applyEnvironment(reflect.ValueOf(config).Elem(), []string{EnvironmentPrefix}, "", os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	config.check(path, root, overridden, &errs)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return config, nil
+}
+
+// NewFromConfig creates a logger from the config file at path, see LoadConfig. Options given in addition are
+// applied after the ones of the file, e.g. a callback or a sink that cannot be described in a file.
+func NewFromConfig(path string, opts ...Option) (*Logger, error) {
+	config, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	configOpts, err := config.Options()
+	if err != nil {
+		return nil, err
+	}
+	return New(append(configOpts, opts...)...)
+}
+
+//This method checks the values that are well typed but still invalid, e.g. an unknown level name. Problems
+// with values taken from the environment are reported against the environment rather than a file position.
+func (config *Config) check(path string, root *configNode, overridden []string, errs *ConfigErrors) {
+	report := func(key string, msg string) {
+		for _, o := range overridden {
+			if o == key {
+				*errs = append(*errs, &ConfigError{File: "environment", Key: key, Msg: msg})
+				return
+			}
+		}
+		node := root.lookup(key)
+		*errs = append(*errs, &ConfigError{File: path, Line: node.line, Column: node.column, Key: key, Msg: msg})
+	}
+	if len(config.Level) > 0 {
+		if _, err := logWriter.ParseLevel(config.Level); err != nil {
+			report("level", fmt.Sprintf("unknown level %q", config.Level))
+		}
+	}
+	if len(config.File) == 0 {
+		report("file", "missing required key")
+	}
+	if _, err := formatterFor(config); err != nil {
+		report("format", err.Error())
+	}
+	if _, err := rotationFor(config.Rotate); err != nil {
+		report("rotate", err.Error())
+	}
+	if _, err := modeFor(config.FileMode); err != nil {
+		report("file_mode", err.Error())
+	}
+	if _, err := modeFor(config.DirMode); err != nil {
+		report("dir_mode", err.Error())
+	}
+	if len(config.StackLevel) > 0 {
+		if _, err := logWriter.ParseLevel(config.StackLevel); err != nil {
+			report("stack_level", err.Error())
+		}
+	}
+	if config.StackDepth < 0 {
+		report("stack_depth", "must not be negative")
+	}
+	if config.BufferSize < 0 {
+		report("buffer_size", "must not be negative")
+	}
+	if config.ChannelSize < 0 {
+		report("channel_size", "must not be negative")
+	}
+	if config.VectoredWrites < 0 {
+		report("vectored_writes", "must not be negative")
+	}
+	if policy, err := ParseOverflowPolicy(config.Overflow); err != nil {
+		report("overflow", err.Error())
+	} else if strings.ToLower(config.Queue) == "ring" && policy == DropOldest {
+		report("overflow", "drop_oldest is not supported with the ring queue")
+	}
+	switch strings.ToLower(config.Queue) {
+	case "", "channel", "ring":
+	default:
+		report("queue", fmt.Sprintf("unknown queue %q, want channel or ring", config.Queue))
+	}
+	if config.Synchronous && (strings.ToLower(config.Queue) == "ring" || config.Shards > 1) {
+		report("synchronous", "cannot be combined with the ring queue or shards")
+	}
+	if config.Shards < 0 {
+		report("shards", "must not be negative")
+	}
+	if _, err := ParseShardStrategy(config.ShardBy); err != nil {
+		report("shard_by", err.Error())
+	}
+	if config.RetryAttempts < 0 {
+		report("retry_attempts", "must not be negative")
+	}
+	if config.RetryBackoff < 0 {
+		report("retry_backoff", "must not be negative")
+	}
+	if config.FallbackAfter < 0 {
+		report("fallback_after", "must not be negative")
+	}
+	if _, err := diskFullFor(config.DiskFull); err != nil {
+		report("disk_full", err.Error())
+	}
+	if _, err := syncPolicyFor(config.Fsync); err != nil {
+		report("fsync", err.Error())
+	}
+	if config.FsyncInterval < 0 {
+		report("fsync_interval", "must not be negative")
+	}
+	if _, err := config.redactor(); err != nil {
+		report("redact_patterns", err.Error())
+	}
+	if len(config.StderrLevel) > 0 {
+		if _, err := logWriter.ParseLevel(config.StderrLevel); err != nil {
+			report("stderr_level", err.Error())
+		}
+	}
+	for name := range config.LevelFiles {
+		if _, err := logWriter.ParseLevel(name); err != nil {
+			report("level_files."+name, err.Error())
+		}
+	}
+	registered := logWriter.RegisteredSinks()
+	for name := range config.Sinks {
+		known := false
+		for _, r := range registered {
+			known = known || r == name
+		}
+		if !known {
+			report("sinks."+name, fmt.Sprintf("no sink registered as %q; import the sink's package", name))
+		}
+	}
+	if config.FlushInterval < 0 {
+		report("flush_interval", "must not be negative")
+	}
+	if config.TraceOnError < 0 {
+		report("trace_on_error", "must not be negative")
+	}
+	if config.DedupWindow < 0 {
+		report("dedup_window", "must not be negative")
+	}
+	for name, sampling := range config.Sampling {
+		key := "sampling." + name
+		if _, err := logWriter.ParseLevel(name); err != nil {
+			report(key, err.Error())
+		}
+		if sampling.Interval < 0 || sampling.First < 0 || sampling.Thereafter < 0 {
+			report(key, "interval, first and thereafter must not be negative")
+		}
+		if sampling.Rate < 0 || sampling.Rate > 1 {
+			report(key+".rate", "must be between 0 and 1")
+		}
+	}
+	if _, err := config.encryptionKey(); err != nil {
+		report("encryption_key", err.Error())
+	}
+	if len(config.AuditKey) > 0 {
+		if _, err := readHexKey(config.AuditKey); err != nil {
+			report("audit_key", err.Error())
+		}
+	}
+}
+
+// Options returns the options for New described by the config, so that a logger can be created with
+//
+//	myLogger, err := logger.New(options...)
+func (config *Config) Options() ([]Option, error) {
+	opts := []Option{WithFile(config.File), WithDir(config.Dir)}
+	if len(config.Level) > 0 {
+		level, err := logWriter.ParseLevel(config.Level)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithLevel(level))
+	}
+	formatter, err := formatterFor(config)
+	if err != nil {
+		return nil, err
+	}
+	period, err := rotationFor(config.Rotate)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, WithFormatter(formatter), WithMaxSize(int64(config.MaxSize)), WithRotation(period))
+	if config.Compress {
+		opts = append(opts, WithCompression())
+	}
+	fileMode, err := modeFor(config.FileMode)
+	if err != nil {
+		return nil, err
+	}
+	if fileMode != 0 {
+		opts = append(opts, WithFileMode(fileMode))
+	}
+	dirMode, err := modeFor(config.DirMode)
+	if err != nil {
+		return nil, err
+	}
+	if dirMode != 0 {
+		opts = append(opts, WithDirMode(dirMode))
+	}
+	if config.PrivateFiles {
+		opts = append(opts, WithPrivateFiles())
+	}
+	if config.FileLock {
+		opts = append(opts, WithFileLock())
+	}
+	if len(config.TimeLayout) > 0 {
+		opts = append(opts, WithTimeLayout(config.TimeLayout))
+	}
+	if config.UTC {
+		opts = append(opts, WithUTC())
+	}
+	if config.Sequence {
+		opts = append(opts, WithSequence())
+	}
+	if len(config.App) > 0 {
+		opts = append(opts, WithProcessFields(config.App))
+	}
+	redactor, err := config.redactor()
+	if err != nil {
+		return nil, err
+	}
+	if redactor != nil {
+		opts = append(opts, WithRedaction(redactor))
+	}
+	key, err := config.encryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	if key != nil {
+		opts = append(opts, WithEncryption(key))
+	}
+	if len(config.AuditKey) > 0 {
+		auditKey, err := readHexKey(config.AuditKey)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithAudit(auditKey))
+	}
+	if len(config.StderrLevel) > 0 {
+		level, err := logWriter.ParseLevel(config.StderrLevel)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithStderr(level))
+	}
+	for _, name := range sortedKeys(config.Sinks) {
+		sink, err := config.sink(name, formatter)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithSink(name, sink))
+	}
+	for name, path := range config.LevelFiles {
+		level, err := logWriter.ParseLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithLevelFile(path, level))
+	}
+	if config.TraceOnError > 0 {
+		opts = append(opts, WithTraceOnError(config.TraceOnError))
+	}
+	if config.DedupWindow > 0 {
+		opts = append(opts, WithDeduplication(time.Duration(config.DedupWindow)))
+	}
+	policies, err := config.samplingPolicies()
+	if err != nil {
+		return nil, err
+	}
+	for level, policy := range policies {
+		opts = append(opts, WithSampling(level, policy))
+	}
+	if len(config.StackLevel) > 0 {
+		level, err := logWriter.ParseLevel(config.StackLevel)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithStackTrace(level, config.StackDepth))
+	}
+	if config.BufferSize > 0 {
+		opts = append(opts, WithBufferSize(int(config.BufferSize)))
+	}
+	if config.VectoredWrites > 1 {
+		opts = append(opts, WithVectoredWrites(config.VectoredWrites))
+	}
+	if config.FlushInterval > 0 {
+		opts = append(opts, WithFlushInterval(time.Duration(config.FlushInterval)))
+	}
+	if config.ChannelSize > 0 {
+		opts = append(opts, WithChannelSize(config.ChannelSize))
+	}
+	if config.RetryAttempts > 0 {
+		opts = append(opts, WithRetry(config.RetryAttempts, time.Duration(config.RetryBackoff)))
+	}
+	diskFull, err := diskFullFor(config.DiskFull)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, WithDiskFullPolicy(diskFull))
+	syncPolicy, err := syncPolicyFor(config.Fsync)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, WithSyncPolicy(syncPolicy))
+	if syncPolicy == logWriter.SyncInterval && config.FsyncInterval > 0 {
+		opts = append(opts, WithSyncInterval(time.Duration(config.FsyncInterval)))
+	}
+	switch config.Fallback {
+	case "":
+	case "stderr":
+		opts = append(opts, WithFallback(os.Stderr, config.FallbackAfter))
+	default:
+		opts = append(opts, WithFallbackFile(config.Fallback, config.FallbackAfter))
+	}
+	if strings.ToLower(config.Queue) == "ring" {
+		opts = append(opts, WithRingQueue())
+	}
+	if config.Synchronous {
+		opts = append(opts, WithSynchronous())
+	}
+	if config.Shards > 1 {
+		strategy, err := ParseShardStrategy(config.ShardBy)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithShards(config.Shards, strategy))
+		if config.ShardFiles {
+			opts = append(opts, WithShardFiles())
+		}
+	}
+	policy, err := ParseOverflowPolicy(config.Overflow)
+	if err != nil {
+		return nil, err
+	}
+	return append(opts, WithOverflowPolicy(policy)), nil
+}
+
+//This method returns the formatter for the format of a config file, writing timestamps and sequence numbers as
+// the config says, nil for the text format. Other names are looked up among the formatters registered with
+// logWriter.RegisterFormatter, which get the format_options of the config.
+func formatterFor(config *Config) (logWriter.Formatter, error) {
+	switch strings.ToLower(config.Format) {
+	case "", "text":
+		return nil, nil
+	case "json":
+		return logWriter.JSONFormatter{TimestampFormat: config.TimeLayout, UTC: config.UTC, Sequence: config.Sequence}, nil
+	case "ecs":
+		return logWriter.ECSFormatter{Sequence: config.Sequence}, nil
+	case "csv":
+		return logWriter.CSVFormatter{TimestampFormat: config.TimeLayout, UTC: config.UTC}, nil
+	case "pattern":
+		if len(config.Pattern) == 0 {
+			return nil, fmt.Errorf(`the pattern format needs a "pattern"`)
+		}
+		formatter, err := logWriter.ParsePattern(config.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		formatter.UTC = config.UTC
+		return formatter, nil
+	case "binary":
+		return logWriter.BinaryFormatter{}, nil
+	}
+	for _, name := range logWriter.RegisteredFormatters() {
+		if name == config.Format {
+			return logWriter.NewFormatter(name, config.FormatOptions)
+		}
+	}
+	return nil, fmt.Errorf("unknown format %q", config.Format)
+}
+
+//This method returns the rotation period for a schedule name of a config file.
+func rotationFor(schedule string) (logWriter.RotationPeriod, error) {
+	switch strings.ToLower(schedule) {
+	case "", "none":
+		return logWriter.NoRotation, nil
+	case "hourly":
+		return logWriter.Hourly, nil
+	case "daily":
+		return logWriter.Daily, nil
+	}
+	return logWriter.NoRotation, fmt.Errorf("unknown rotation schedule %q", schedule)
+}
+
+//This method returns the redactor described by the config, nil if it redacts nothing. Patterns are regular
+// expressions except for the names of the built-in ones, credit_card and email.
+func (config *Config) redactor() (*logWriter.Redactor, error) {
+	if len(config.RedactKeys) == 0 && len(config.RedactPatterns) == 0 {
+		return nil, nil
+	}
+	patterns := make([]string, len(config.RedactPatterns))
+	for i, pattern := range config.RedactPatterns {
+		switch pattern {
+		case "credit_card":
+			pattern = logWriter.CreditCardPattern.String()
+		case "email":
+			pattern = logWriter.EmailPattern.String()
+		}
+		patterns[i] = pattern
+	}
+	return logWriter.NewRedactor(config.RedactKeys, patterns...)
+}
+
+//This method reads the key of the encryption_key file, nil if the config does not encrypt the log files.
+func (config *Config) encryptionKey() (logWriter.StaticKey, error) {
+	if len(config.EncryptionKey) == 0 {
+		return nil, nil
+	}
+	key, err := readHexKey(config.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 16 && len(key) != 24 && len(key) != 32 {
+		return nil, fmt.Errorf("key must be 16, 24 or 32 bytes, not %d", len(key))
+	}
+	return key, nil
+}
+
+//This method reads the hex encoded key held by the file at path.
+func readHexKey(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("key is not hex encoded: %v", err)
+	}
+	return key, nil
+}
+
+//This method parses an octal file mode of a config file, 0 if it is empty.
+func modeFor(mode string) (os.FileMode, error) {
+	if len(mode) == 0 {
+		return 0, nil
+	}
+	bits, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || bits == 0 || bits > 0777 {
+		return 0, fmt.Errorf("invalid mode %q, want an octal mode such as \"0640\"", mode)
+	}
+	return os.FileMode(bits), nil
+}
+
+//This method returns the disk full policy for a policy name of a config file.
+func diskFullFor(policy string) (logWriter.DiskFullPolicy, error) {
+	switch strings.ToLower(policy) {
+	case "", "discard":
+		return logWriter.DiskFullDiscard, nil
+	case "pause":
+		return logWriter.DiskFullPause, nil
+	case "fallback":
+		return logWriter.DiskFullFallback, nil
+	case "purge":
+		return logWriter.DiskFullPurge, nil
+	}
+	return logWriter.DiskFullDiscard, fmt.Errorf("unknown disk full policy %q", policy)
+}
+
+//This method returns the sync policy for a policy name of a config file.
+func syncPolicyFor(policy string) (logWriter.SyncPolicy, error) {
+	switch strings.ToLower(policy) {
+	case "", "never":
+		return logWriter.SyncNever, nil
+	case "interval":
+		return logWriter.SyncInterval, nil
+	case "flush":
+		return logWriter.SyncEveryFlush, nil
+	case "error":
+		return logWriter.SyncOnError, nil
+	}
+	return logWriter.SyncNever, fmt.Errorf("unknown fsync policy %q", policy)
+}
+
+//This method returns the sampling policies keyed by level.
+func (config *Config) samplingPolicies() (map[logWriter.Level]Sampling, error) {
+	policies := make(map[logWriter.Level]Sampling, len(config.Sampling))
+	for name, sampling := range config.Sampling {
+		level, err := logWriter.ParseLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		policy := Sampling{Interval: time.Duration(sampling.Interval), First: sampling.First,
+			Thereafter: sampling.Thereafter, Rate: sampling.Rate}
+		if policy.Interval <= 0 {
+			policy.Interval = time.Second
+		}
+		policies[level] = policy
+	}
+	return policies, nil
+}
+
+//This method creates the sink configured under name, rendering entries with formatter.
+func (config *Config) sink(name string, formatter logWriter.Formatter) (logWriter.EntrySink, error) {
+	sink, err := logWriter.NewSink(name, config.Sinks[name])
+	if err != nil {
+		return nil, err
+	}
+	return logWriter.NewWriterSink(sink, formatter), nil
+}
+
+//Util method that returns the keys of the sink settings in order, so that sinks are added in the same order
+// every time.
+func sortedKeys(sinks map[string]map[string]interface{}) []string {
+	names := make([]string, 0, len(sinks))
+	for name := range sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,340 @@
+package logger
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+//kinds of values found in a config file.
+type nodeKind int
+
+const (
+	objectNode nodeKind = iota
+	arrayNode
+	stringNode
+	numberNode
+	boolNode
+	nullNode
+)
+
+//names used in type errors.
+var nodeKindNames = map[nodeKind]string{
+	objectNode: "object",
+	arrayNode:  "array",
+	stringNode: "string",
+	numberNode: "number",
+	boolNode:   "bool",
+	nullNode:   "null",
+}
+
+//configNode is a value of a config file together with its position, so that validation errors can point
+// at the offending line and column.
+type configNode struct {
+	kind   nodeKind
+	scalar interface{}   //string, json.Number, bool or nil for scalar nodes
+	fields []configField //members of an object node, in file order
+	items  []*configNode //elements of an array node
+	line   int           //1-based line of the value
+	column int           //1-based column of the value
+}
+
+//configField is a member of an object node.
+type configField struct {
+	key    string
+	line   int //1-based line of the key
+	column int //1-based column of the key
+	value  *configNode
+}
+
+//This method returns the node found at the given dotted path, or the node itself if the path does not exist.
+func (node *configNode) lookup(path string) *configNode {
+	current := node
+	for _, key := range strings.Split(path, ".") {
+		found := false
+		for _, field := range current.fields {
+			if strings.EqualFold(field.key, key) {
+				current, found = field.value, true
+				break
+			}
+		}
+		if !found {
+			return node
+		}
+	}
+	return current
+}
+
+//This method converts the node to plain maps, slices and scalars.
+func (node *configNode) value() interface{} {
+	switch node.kind {
+	case objectNode:
+		object := make(map[string]interface{}, len(node.fields))
+		for _, field := range node.fields {
+			object[field.key] = field.value.value()
+		}
+		return object
+	case arrayNode:
+		array := make([]interface{}, len(node.items))
+		for i, item := range node.items {
+			array[i] = item.value()
+		}
+		return array
+	}
+	return node.scalar
+}
+
+//This method decodes the node into target using the json tags of target's fields.
+func (node *configNode) decode(target interface{}) error {
+	data, err := json.Marshal(node.value())
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+//jsonConfigParser builds a configNode tree from JSON, tracking the position of every token.
+type jsonConfigParser struct {
+	path    string
+	data    []byte
+	decoder *json.Decoder
+}
+
+//This method parses a config file into a node tree, choosing the format by the file's extension.
+func parseConfig(path string, data []byte) (*configNode, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return parseYAMLConfig(path, data)
+	case ".toml":
+		return parseTOMLConfig(path, data)
+	}
+	return parseJSONConfig(path, data)
+}
+
+//This method parses a JSON config file into a node tree. Syntax errors are returned as *ConfigError.
+func parseJSONConfig(path string, data []byte) (*configNode, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	parser := &jsonConfigParser{path: path, data: data, decoder: decoder}
+	root, err := parser.parseValue()
+	if err != nil {
+		return nil, parser.wrap(err)
+	}
+	if _, err = decoder.Token(); err != io.EOF {
+		line, column := parser.next()
+		return nil, &ConfigError{File: path, Line: line, Column: column, Msg: "unexpected data after top-level value"}
+	}
+	return root, nil
+}
+
+//This method parses the next value, recursing into objects and arrays.
+func (p *jsonConfigParser) parseValue() (*configNode, error) {
+	line, column := p.next()
+	token, err := p.decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	node := &configNode{line: line, column: column}
+	switch value := token.(type) {
+	case json.Delim:
+		if value == '{' {
+			node.kind = objectNode
+			for p.decoder.More() {
+				keyLine, keyColumn := p.next()
+				key, err := p.decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				child, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				node.fields = append(node.fields, configField{key: key.(string), line: keyLine, column: keyColumn, value: child})
+			}
+		} else {
+			node.kind = arrayNode
+			for p.decoder.More() {
+				child, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				node.items = append(node.items, child)
+			}
+		}
+		if _, err = p.decoder.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		node.kind, node.scalar = stringNode, value
+	case json.Number:
+		node.kind, node.scalar = numberNode, value
+	case bool:
+		node.kind, node.scalar = boolNode, value
+	default:
+		node.kind = nullNode
+	}
+	return node, nil
+}
+
+//This method returns the position at which the next token starts. The decoder offset points right after the
+// previous token, so separators and white space are skipped first.
+func (p *jsonConfigParser) next() (line int, column int) {
+	offset := int(p.decoder.InputOffset())
+	for offset < len(p.data) && strings.IndexByte(" \t\r\n,:", p.data[offset]) >= 0 {
+		offset++
+	}
+	return position(p.data, offset)
+}
+
+//This method converts decoder errors into *ConfigError values carrying the position of the error.
+func (p *jsonConfigParser) wrap(err error) error {
+	offset := -1
+	if syntaxError, ok := err.(*json.SyntaxError); ok {
+		offset = int(syntaxError.Offset)
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		offset = len(p.data)
+		err = fmt.Errorf("unexpected end of file")
+	}
+	if offset < 0 {
+		return err
+	}
+	line, column := position(p.data, offset)
+	return &ConfigError{File: p.path, Line: line, Column: column, Msg: err.Error()}
+}
+
+//This method converts a byte offset into a 1-based line and column.
+func position(data []byte, offset int) (line int, column int) {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	line = 1 + bytes.Count(data[:offset], []byte{'\n'})
+	column = offset - bytes.LastIndexByte(data[:offset], '\n')
+	return line, column
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+//This method validates node against the Go type it will be decoded into and appends a *ConfigError for
+// every unknown key and every value of the wrong type.
+func validateConfigNode(path string, node *configNode, t reflect.Type, key string, errs *ConfigErrors) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	mismatch := func(expected string) {
+		*errs = append(*errs, &ConfigError{File: path, Line: node.line, Column: node.column, Key: key,
+			Msg: fmt.Sprintf("expected %s, found %s", expected, nodeKindNames[node.kind])})
+	}
+	if node.kind == nullNode {
+		return
+	}
+
+	pointer := reflect.PtrTo(t)
+	switch {
+	case pointer.Implements(jsonUnmarshalerType):
+		if node.kind == objectNode || node.kind == arrayNode {
+			mismatch("string or number")
+		}
+		return
+	case pointer.Implements(textUnmarshalerType):
+		if node.kind != stringNode {
+			mismatch("string")
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.kind != objectNode {
+			mismatch("object")
+			return
+		}
+		for _, field := range node.fields {
+			fieldKey := joinKey(key, field.key)
+			structField, ok := fieldByKey(t, field.key)
+			if !ok {
+				*errs = append(*errs, &ConfigError{File: path, Line: field.line, Column: field.column,
+					Key: fieldKey, Msg: "unknown key"})
+				continue
+			}
+			validateConfigNode(path, field.value, structField.Type, fieldKey, errs)
+		}
+	case reflect.Map:
+		if node.kind != objectNode {
+			mismatch("object")
+			return
+		}
+		for _, field := range node.fields {
+			validateConfigNode(path, field.value, t.Elem(), joinKey(key, field.key), errs)
+		}
+	case reflect.Slice, reflect.Array:
+		if node.kind != arrayNode {
+			mismatch("array")
+			return
+		}
+		for i, item := range node.items {
+			validateConfigNode(path, item, t.Elem(), fmt.Sprintf("%s[%d]", key, i), errs)
+		}
+	case reflect.String:
+		if node.kind != stringNode {
+			mismatch("string")
+		}
+	case reflect.Bool:
+		if node.kind != boolNode {
+			mismatch("bool")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if node.kind != numberNode {
+			mismatch("integer")
+		} else if strings.ContainsAny(node.scalar.(json.Number).String(), ".eE") {
+			mismatch("integer")
+		}
+	case reflect.Float32, reflect.Float64:
+		if node.kind != numberNode {
+			mismatch("number")
+		}
+	}
+}
+
+//This method finds the struct field decoded from the given key, matching json tags the way encoding/json does.
+func fieldByKey(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("json"); len(tag) > 0 {
+			if tag == "-" {
+				continue
+			}
+			if comma := strings.IndexByte(tag, ','); comma >= 0 {
+				tag = tag[:comma]
+			}
+			if len(tag) > 0 {
+				name = tag
+			}
+		}
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+//This method joins a parent key path and a child key with a dot.
+func joinKey(parent string, child string) string {
+	if len(parent) == 0 {
+		return child
+	}
+	return parent + "." + child
+}
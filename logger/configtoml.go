@@ -0,0 +1,339 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//tomlConfigParser builds a configNode tree from TOML: key/value pairs with bare, quoted and dotted keys,
+// tables, arrays of tables, basic and literal strings, numbers, booleans, arrays and inline tables. Multi-line
+// strings are rejected and date-times are kept as strings.
+type tomlConfigParser struct {
+	path   string
+	data   []byte
+	offset int //offset of the next byte to parse
+}
+
+//tomlKey is a part of a dotted key together with its offset.
+type tomlKey struct {
+	name   string
+	offset int
+}
+
+//This method parses a TOML config file into a node tree. Syntax errors are returned as *ConfigError.
+func parseTOMLConfig(path string, data []byte) (*configNode, error) {
+	p := &tomlConfigParser{path: path, data: data}
+	root := &configNode{kind: objectNode, line: 1, column: 1}
+	table := root
+	for p.skipSpace(true) < len(data) {
+		start := p.offset
+		if data[p.offset] == '[' {
+			array := strings.HasPrefix(string(data[p.offset:]), "[[")
+			closing := "]"
+			if array {
+				closing = "]]"
+			}
+			p.offset += len(closing)
+			keys, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			if !strings.HasPrefix(string(data[p.skipSpace(false):]), closing) {
+				return nil, p.errorAt(p.offset, "expected "+closing)
+			}
+			p.offset += len(closing)
+			if table, err = p.table(root, keys, array, start); err != nil {
+				return nil, err
+			}
+		} else {
+			keys, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			if p.skipSpace(false) >= len(data) || data[p.offset] != '=' {
+				return nil, p.errorAt(p.offset, "expected =")
+			}
+			p.offset++
+			p.skipSpace(false)
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.insert(table, keys, value); err != nil {
+				return nil, err
+			}
+		}
+		if p.skipSpace(false) < len(data) && data[p.offset] != '\n' && data[p.offset] != '#' {
+			return nil, p.errorAt(p.offset, "expected end of line")
+		}
+	}
+	return root, nil
+}
+
+//This method parses a bare, quoted or dotted key.
+func (p *tomlConfigParser) parseKey() ([]tomlKey, error) {
+	var keys []tomlKey
+	for {
+		start := p.skipSpace(false)
+		var name string
+		var err error
+		if start < len(p.data) && (p.data[start] == '"' || p.data[start] == '\'') {
+			if name, err = p.parseString(); err != nil {
+				return nil, err
+			}
+		} else {
+			for p.offset < len(p.data) && isTOMLBareKeyByte(p.data[p.offset]) {
+				p.offset++
+			}
+			if p.offset == start {
+				return nil, p.errorAt(start, "expected a key")
+			}
+			name = string(p.data[start:p.offset])
+		}
+		keys = append(keys, tomlKey{name: name, offset: start})
+		if p.skipSpace(false) >= len(p.data) || p.data[p.offset] != '.' {
+			return keys, nil
+		}
+		p.offset++
+	}
+}
+
+//This method parses the value at the current offset.
+func (p *tomlConfigParser) parseValue() (*configNode, error) {
+	if p.offset >= len(p.data) {
+		return nil, p.errorAt(p.offset, "expected a value")
+	}
+	line, column := position(p.data, p.offset)
+	node := &configNode{line: line, column: column}
+	switch p.data[p.offset] {
+	case '"', '\'':
+		value, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		node.kind, node.scalar = stringNode, value
+	case '[':
+		node.kind = arrayNode
+		p.offset++
+		for p.skipSpace(true) < len(p.data) && p.data[p.offset] != ']' {
+			item, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			node.items = append(node.items, item)
+			if p.skipSpace(true) < len(p.data) && p.data[p.offset] == ',' {
+				p.offset++
+			} else if p.offset >= len(p.data) || p.data[p.offset] != ']' {
+				return nil, p.errorAt(p.offset, "expected , or ]")
+			}
+		}
+		if p.offset >= len(p.data) {
+			return nil, p.errorAt(p.offset, "unexpected end of file")
+		}
+		p.offset++
+	case '{':
+		node.kind = objectNode
+		p.offset++
+		for p.skipSpace(false) < len(p.data) && p.data[p.offset] != '}' {
+			keys, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			if p.skipSpace(false) >= len(p.data) || p.data[p.offset] != '=' {
+				return nil, p.errorAt(p.offset, "expected =")
+			}
+			p.offset++
+			p.skipSpace(false)
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.insert(node, keys, value); err != nil {
+				return nil, err
+			}
+			if p.skipSpace(false) < len(p.data) && p.data[p.offset] == ',' {
+				p.offset++
+			} else if p.offset >= len(p.data) || p.data[p.offset] != '}' {
+				return nil, p.errorAt(p.offset, "expected , or }")
+			}
+		}
+		if p.offset >= len(p.data) {
+			return nil, p.errorAt(p.offset, "unexpected end of file")
+		}
+		p.offset++
+	default:
+		start := p.offset
+		for p.offset < len(p.data) && strings.IndexByte(" \t\r\n,]}#", p.data[p.offset]) < 0 {
+			p.offset++
+		}
+		token := string(p.data[start:p.offset])
+		switch {
+		case token == "true" || token == "false":
+			node.kind, node.scalar = boolNode, token == "true"
+		case isTOMLDateTime(token):
+			node.kind, node.scalar = stringNode, token
+		default:
+			number, ok := tomlNumber(token)
+			if !ok {
+				return nil, p.errorAt(start, fmt.Sprintf("invalid value %q", token))
+			}
+			node.kind, node.scalar = numberNode, number
+		}
+	}
+	return node, nil
+}
+
+//This method parses a basic or literal string on a single line.
+func (p *tomlConfigParser) parseString() (string, error) {
+	start := p.offset
+	quote := p.data[start]
+	if strings.HasPrefix(string(p.data[start:]), strings.Repeat(string(quote), 3)) {
+		return "", p.errorAt(start, "multi-line strings are not supported")
+	}
+	for p.offset = start + 1; p.offset < len(p.data) && p.data[p.offset] != '\n'; p.offset++ {
+		if quote == '"' && p.data[p.offset] == '\\' {
+			p.offset++
+			continue
+		}
+		if p.data[p.offset] != quote {
+			continue
+		}
+		p.offset++
+		if quote == '\'' {
+			return string(p.data[start+1 : p.offset-1]), nil
+		}
+		value, err := strconv.Unquote(string(p.data[start:p.offset]))
+		if err != nil {
+			return "", p.errorAt(start, "invalid escape in string")
+		}
+		return value, nil
+	}
+	return "", p.errorAt(start, "string is not terminated")
+}
+
+//This method inserts value into table under the dotted key, creating the intermediate tables.
+func (p *tomlConfigParser) insert(table *configNode, keys []tomlKey, value *configNode) error {
+	for _, key := range keys[:len(keys)-1] {
+		child := tomlField(table, key.name)
+		if child == nil {
+			child = p.newTable(table, key)
+		} else if child.kind != objectNode {
+			return p.errorAt(key.offset, fmt.Sprintf("key %q is not a table", key.name))
+		}
+		table = child
+	}
+	last := keys[len(keys)-1]
+	if tomlField(table, last.name) != nil {
+		return p.errorAt(last.offset, fmt.Sprintf("duplicate key %q", last.name))
+	}
+	line, column := position(p.data, last.offset)
+	table.fields = append(table.fields, configField{key: last.name, line: line, column: column, value: value})
+	return nil
+}
+
+//This method returns the table a [table] or [[array of tables]] header at start selects, creating it.
+func (p *tomlConfigParser) table(root *configNode, keys []tomlKey, array bool, start int) (*configNode, error) {
+	table := root
+	for i, key := range keys {
+		child := tomlField(table, key.name)
+		last := i == len(keys)-1
+		switch {
+		case child == nil && last && array:
+			line, column := position(p.data, key.offset)
+			child = &configNode{kind: arrayNode, line: line, column: column}
+			table.fields = append(table.fields, configField{key: key.name, line: line, column: column, value: child})
+		case child == nil:
+			child = p.newTable(table, key)
+		}
+		if child.kind == arrayNode && (!last || array) {
+			if last {
+				line, column := position(p.data, start)
+				child.items = append(child.items, &configNode{kind: objectNode, line: line, column: column})
+			}
+			if len(child.items) == 0 || child.items[len(child.items)-1].kind != objectNode {
+				return nil, p.errorAt(key.offset, fmt.Sprintf("key %q is not an array of tables", key.name))
+			}
+			child = child.items[len(child.items)-1]
+		} else if child.kind != objectNode || last && array {
+			return nil, p.errorAt(key.offset, fmt.Sprintf("key %q is already defined", key.name))
+		}
+		table = child
+	}
+	return table, nil
+}
+
+//This method adds an empty table under key to table.
+func (p *tomlConfigParser) newTable(table *configNode, key tomlKey) *configNode {
+	line, column := position(p.data, key.offset)
+	child := &configNode{kind: objectNode, line: line, column: column}
+	table.fields = append(table.fields, configField{key: key.name, line: line, column: column, value: child})
+	return child
+}
+
+//This method skips spaces and comments, and line breaks too if newlines is set, and returns the new offset.
+func (p *tomlConfigParser) skipSpace(newlines bool) int {
+	for p.offset < len(p.data) {
+		switch p.data[p.offset] {
+		case ' ', '\t', '\r':
+		case '\n':
+			if !newlines {
+				return p.offset
+			}
+		case '#':
+			if !newlines {
+				return p.offset
+			}
+			for p.offset < len(p.data) && p.data[p.offset] != '\n' {
+				p.offset++
+			}
+			continue
+		default:
+			return p.offset
+		}
+		p.offset++
+	}
+	return p.offset
+}
+
+//This method returns a *ConfigError at the given offset.
+func (p *tomlConfigParser) errorAt(offset int, msg string) error {
+	line, column := position(p.data, offset)
+	return &ConfigError{File: p.path, Line: line, Column: column, Msg: msg}
+}
+
+//Util method that returns the value of the field with the given key of an object node, or nil.
+func tomlField(table *configNode, key string) *configNode {
+	for _, field := range table.fields {
+		if field.key == key {
+			return field.value
+		}
+	}
+	return nil
+}
+
+//Util method that reports whether c may appear in a bare key.
+func isTOMLBareKeyByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+//Util method that reports whether a token is a date, time or date-time, e.g. 2024-05-27T07:32:00Z.
+func isTOMLDateTime(token string) bool {
+	return len(token) >= 8 && (token[4] == '-' || token[2] == ':') && strings.Trim(token, "0123456789-:.TtZz+") == ""
+}
+
+//Util method that converts a TOML integer or float, e.g. 1_000, +5 or 0x1F, into a JSON number.
+func tomlNumber(token string) (json.Number, bool) {
+	digits := strings.Replace(strings.TrimPrefix(token, "+"), "_", "", -1)
+	for prefix, base := range map[string]int{"0x": 16, "0o": 8, "0b": 2} {
+		if strings.HasPrefix(digits, prefix) {
+			value, err := strconv.ParseInt(digits[2:], base, 64)
+			return json.Number(strconv.FormatInt(value, 10)), err == nil
+		}
+	}
+	if jsonNumberPattern.MatchString(digits) {
+		return json.Number(digits), true
+	}
+	return "", false
+}
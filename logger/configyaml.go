@@ -0,0 +1,413 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//numbers decoded as numberNode; other numeric looking values, e.g. the file mode 0640, are kept as strings.
+var jsonNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+//yamlLine is a line of a YAML config file holding content.
+type yamlLine struct {
+	number int    //1-based line number
+	indent int    //columns before the content
+	text   string //content without indentation, comment and trailing white space
+}
+
+//yamlConfigParser builds a configNode tree from the block-style subset of YAML used by config files: nested
+// mappings and sequences, plain and quoted scalars and single-line flow collections. Anchors, tags, block
+// scalars and multiple documents are rejected.
+type yamlConfigParser struct {
+	path  string
+	lines []yamlLine
+	next  int //index of the next line to parse
+}
+
+//This method parses a YAML config file into a node tree. Syntax errors are returned as *ConfigError.
+func parseYAMLConfig(path string, data []byte) (*configNode, error) {
+	p := &yamlConfigParser{path: path}
+	for i, raw := range strings.Split(string(data), "\n") {
+		raw = strings.TrimRight(raw, "\r")
+		text := strings.TrimLeft(raw, " ")
+		indent := len(raw) - len(text)
+		if strings.HasPrefix(text, "\t") {
+			return nil, p.errorAt(i+1, indent+1, "tabs are not allowed in indentation")
+		}
+		text = strings.TrimRight(stripYAMLComment(text), " \t")
+		if len(text) == 0 {
+			continue
+		}
+		if indent == 0 && text == "---" {
+			if len(p.lines) > 0 {
+				return nil, p.errorAt(i+1, 1, "multiple documents are not supported")
+			}
+			continue
+		}
+		if indent == 0 && text == "..." {
+			break
+		}
+		p.lines = append(p.lines, yamlLine{number: i + 1, indent: indent, text: text})
+	}
+	if len(p.lines) == 0 {
+		return &configNode{kind: objectNode, line: 1, column: 1}, nil
+	}
+	root, err := p.parseBlock(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.next < len(p.lines) {
+		line := p.lines[p.next]
+		return nil, p.errorAt(line.number, line.indent+1, "unexpected indentation")
+	}
+	return root, nil
+}
+
+//This method parses the block starting at the next line, which is indented by indent columns.
+func (p *yamlConfigParser) parseBlock(indent int) (*configNode, error) {
+	line := p.lines[p.next]
+	if isYAMLSequenceItem(line.text) {
+		return p.parseSequence(indent)
+	}
+	if _, _, _, ok := splitYAMLKey(line.text); ok {
+		return p.parseMapping(indent)
+	}
+	p.next++
+	return p.parseScalar(line.text, line.number, line.indent+1)
+}
+
+//This method parses the items of a block sequence indented by indent columns.
+func (p *yamlConfigParser) parseSequence(indent int) (*configNode, error) {
+	node := &configNode{kind: arrayNode, line: p.lines[p.next].number, column: indent + 1}
+	for p.next < len(p.lines) && p.lines[p.next].indent >= indent {
+		line := &p.lines[p.next]
+		if line.indent > indent {
+			return nil, p.errorAt(line.number, line.indent+1, "unexpected indentation")
+		}
+		if !isYAMLSequenceItem(line.text) {
+			//the next key of the mapping holding a sequence at the key's indentation.
+			break
+		}
+		rest := strings.TrimLeft(line.text[1:], " ")
+		var item *configNode
+		var err error
+		if len(rest) == 0 {
+			p.next++
+			item, err = p.parseNested(indent, line.number, indent+2, false)
+		} else {
+			//the item's content is parsed as a block of its own, indented to where it starts.
+			line.indent += len(line.text) - len(rest)
+			line.text = rest
+			item, err = p.parseBlock(line.indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		node.items = append(node.items, item)
+	}
+	return node, nil
+}
+
+//This method parses the key: value pairs of a block mapping indented by indent columns.
+func (p *yamlConfigParser) parseMapping(indent int) (*configNode, error) {
+	node := &configNode{kind: objectNode, line: p.lines[p.next].number, column: indent + 1}
+	for p.next < len(p.lines) && p.lines[p.next].indent >= indent {
+		line := p.lines[p.next]
+		if line.indent > indent {
+			return nil, p.errorAt(line.number, line.indent+1, "unexpected indentation")
+		}
+		key, value, offset, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, p.errorAt(line.number, line.indent+1, "expected key: value")
+		}
+		key, err := p.parseKey(key, line)
+		if err != nil {
+			return nil, err
+		}
+		p.next++
+		var child *configNode
+		if len(value) == 0 {
+			child, err = p.parseNested(indent, line.number, line.indent+len(line.text)+1, true)
+		} else {
+			child, err = p.parseScalar(value, line.number, line.indent+offset+1)
+		}
+		if err != nil {
+			return nil, err
+		}
+		node.fields = append(node.fields, configField{key: key, line: line.number, column: line.indent + 1, value: child})
+	}
+	return node, nil
+}
+
+//This method parses the value of a key or sequence item that was left empty on its own line: the block
+// indented further on the following lines or, for keys, a sequence at the key's indentation. Without one the
+// value is null.
+func (p *yamlConfigParser) parseNested(indent int, lineNumber int, column int, sequence bool) (*configNode, error) {
+	if p.next < len(p.lines) {
+		next := p.lines[p.next]
+		if next.indent > indent {
+			return p.parseBlock(next.indent)
+		}
+		if sequence && next.indent == indent && isYAMLSequenceItem(next.text) {
+			return p.parseSequence(indent)
+		}
+	}
+	return &configNode{kind: nullNode, line: lineNumber, column: column}, nil
+}
+
+//This method returns the key of a mapping entry, unquoting quoted keys.
+func (p *yamlConfigParser) parseKey(key string, line yamlLine) (string, error) {
+	if !strings.HasPrefix(key, `"`) && !strings.HasPrefix(key, "'") {
+		return key, nil
+	}
+	unquoted, n, err := unquoteYAML(key)
+	if err == nil && n != len(key) {
+		err = fmt.Errorf("unexpected data after quoted key")
+	}
+	if err != nil {
+		return "", p.errorAt(line.number, line.indent+1, err.Error())
+	}
+	return unquoted, nil
+}
+
+//This method parses a value written on the line of its key or sequence item, starting at column.
+func (p *yamlConfigParser) parseScalar(text string, lineNumber int, column int) (*configNode, error) {
+	switch text[0] {
+	case '[', '{':
+		flow := &yamlFlowParser{text: text}
+		node, err := flow.parseValue(lineNumber, column)
+		if err == nil && flow.skipSpace() < len(text) {
+			err = fmt.Errorf("unexpected data after flow collection")
+		}
+		if err != nil {
+			return nil, p.errorAt(lineNumber, column+flow.offset, err.Error())
+		}
+		return node, nil
+	case '"', '\'':
+		value, n, err := unquoteYAML(text)
+		if err == nil && n != len(text) {
+			err = fmt.Errorf("unexpected data after quoted string")
+		}
+		if err != nil {
+			return nil, p.errorAt(lineNumber, column, err.Error())
+		}
+		return &configNode{kind: stringNode, scalar: value, line: lineNumber, column: column}, nil
+	case '|', '>':
+		return nil, p.errorAt(lineNumber, column, "block scalars are not supported")
+	case '&', '*':
+		return nil, p.errorAt(lineNumber, column, "anchors and aliases are not supported")
+	case '!':
+		return nil, p.errorAt(lineNumber, column, "tags are not supported")
+	}
+	return plainYAMLScalar(text, lineNumber, column), nil
+}
+
+//This method returns a *ConfigError at the given position.
+func (p *yamlConfigParser) errorAt(line int, column int, msg string) error {
+	return &ConfigError{File: p.path, Line: line, Column: column, Msg: msg}
+}
+
+//yamlFlowParser parses a flow collection, e.g. [debug, info] or {first: 100}, written on a single line.
+type yamlFlowParser struct {
+	text   string
+	offset int //offset of the next character to parse
+}
+
+//This method parses the flow value at the current offset; column is the column of the start of the text.
+func (f *yamlFlowParser) parseValue(line int, column int) (*configNode, error) {
+	if f.skipSpace() >= len(f.text) {
+		return nil, fmt.Errorf("flow collections must be written on a single line")
+	}
+	node := &configNode{line: line, column: column + f.offset}
+	switch f.text[f.offset] {
+	case '[':
+		node.kind = arrayNode
+		f.offset++
+		for f.skipSpace() < len(f.text) && f.text[f.offset] != ']' {
+			item, err := f.parseValue(line, column)
+			if err != nil {
+				return nil, err
+			}
+			node.items = append(node.items, item)
+			if err = f.separator(']'); err != nil {
+				return nil, err
+			}
+		}
+		return node, f.close(']')
+	case '{':
+		node.kind = objectNode
+		f.offset++
+		for f.skipSpace() < len(f.text) && f.text[f.offset] != '}' {
+			keyColumn := column + f.offset
+			key, err := f.parseString(":,}")
+			if err != nil {
+				return nil, err
+			}
+			if f.skipSpace() >= len(f.text) || f.text[f.offset] != ':' {
+				return nil, fmt.Errorf("expected : after key %q", key)
+			}
+			f.offset++
+			value, err := f.parseValue(line, column)
+			if err != nil {
+				return nil, err
+			}
+			node.fields = append(node.fields, configField{key: key, line: line, column: keyColumn, value: value})
+			if err = f.separator('}'); err != nil {
+				return nil, err
+			}
+		}
+		return node, f.close('}')
+	case '"', '\'':
+		value, err := f.parseString(",]}")
+		if err != nil {
+			return nil, err
+		}
+		node.kind, node.scalar = stringNode, value
+		return node, nil
+	}
+	start := f.offset
+	for f.offset < len(f.text) && strings.IndexByte(",]}", f.text[f.offset]) < 0 {
+		f.offset++
+	}
+	return plainYAMLScalar(strings.TrimSpace(f.text[start:f.offset]), line, node.column), nil
+}
+
+//This method parses a quoted string or a plain one ending before one of the terminators.
+func (f *yamlFlowParser) parseString(terminators string) (string, error) {
+	if f.text[f.offset] == '"' || f.text[f.offset] == '\'' {
+		value, n, err := unquoteYAML(f.text[f.offset:])
+		f.offset += n
+		return value, err
+	}
+	start := f.offset
+	for f.offset < len(f.text) && strings.IndexByte(terminators, f.text[f.offset]) < 0 {
+		f.offset++
+	}
+	return strings.TrimSpace(f.text[start:f.offset]), nil
+}
+
+//This method skips the comma after an item of a flow collection, unless the collection ends with closing.
+func (f *yamlFlowParser) separator(closing byte) error {
+	if f.skipSpace() < len(f.text) {
+		switch f.text[f.offset] {
+		case ',':
+			f.offset++
+			return nil
+		case closing:
+			return nil
+		}
+	}
+	return fmt.Errorf("expected , or %c", closing)
+}
+
+//This method consumes the closing bracket of a flow collection.
+func (f *yamlFlowParser) close(closing byte) error {
+	if f.offset >= len(f.text) {
+		return fmt.Errorf("flow collections must be written on a single line")
+	}
+	f.offset++
+	return nil
+}
+
+//This method skips spaces and returns the new offset.
+func (f *yamlFlowParser) skipSpace() int {
+	for f.offset < len(f.text) && (f.text[f.offset] == ' ' || f.text[f.offset] == '\t') {
+		f.offset++
+	}
+	return f.offset
+}
+
+//Util method that reports whether a line is an item of a block sequence.
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+//Util method that splits a line into the key of a mapping entry and its value, if any. offset is the offset
+// of the value in the line. It returns false if the line is not a mapping entry.
+func splitYAMLKey(text string) (key string, value string, offset int, ok bool) {
+	if isYAMLSequenceItem(text) || text[0] == '[' || text[0] == '{' {
+		return "", "", 0, false
+	}
+	colon := -1
+	if text[0] == '"' || text[0] == '\'' {
+		if _, n, err := unquoteYAML(text); err == nil && strings.HasPrefix(text[n:], ":") {
+			colon = n
+		}
+	} else if colon = strings.Index(text, ": "); colon < 0 && strings.HasSuffix(text, ":") {
+		colon = len(text) - 1
+	}
+	if colon <= 0 || colon+1 < len(text) && text[colon+1] != ' ' {
+		return "", "", 0, false
+	}
+	value = strings.TrimLeft(text[colon+1:], " ")
+	return strings.TrimSpace(text[:colon]), value, len(text) - len(value), true
+}
+
+//Util method that unquotes the double or single quoted string text starts with and returns it together with
+// the length of its quoted form.
+func unquoteYAML(text string) (string, int, error) {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case quote == '\'' && text[i] == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case text[i] == quote:
+			if quote == '\'' {
+				return strings.Replace(text[1:i], "''", "'", -1), i + 1, nil
+			}
+			value, err := strconv.Unquote(text[:i+1])
+			if err != nil {
+				return "", 0, fmt.Errorf("invalid escape in %s", text[:i+1])
+			}
+			return value, i + 1, nil
+		}
+	}
+	return "", 0, fmt.Errorf("quoted string is not terminated")
+}
+
+//Util method that removes a comment from a line, ignoring # inside quoted strings and within words.
+func stripYAMLComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.IndexByte(" \t:-[{,", text[i-1]) >= 0 {
+				quote = c
+			}
+		case c == '#':
+			if i == 0 || text[i-1] == ' ' || text[i-1] == '\t' {
+				return text[:i]
+			}
+		}
+	}
+	return text
+}
+
+//Util method that converts a plain scalar into a bool, null, number or string node.
+func plainYAMLScalar(text string, line int, column int) *configNode {
+	node := &configNode{kind: stringNode, scalar: text, line: line, column: column}
+	switch text {
+	case "true", "True", "TRUE":
+		node.kind, node.scalar = boolNode, true
+	case "false", "False", "FALSE":
+		node.kind, node.scalar = boolNode, false
+	case "null", "Null", "NULL", "~", "":
+		node.kind, node.scalar = nullNode, nil
+	default:
+		if jsonNumberPattern.MatchString(text) {
+			node.kind, node.scalar = numberNode, json.Number(text)
+		}
+	}
+	return node
+}
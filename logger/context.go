@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"context"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+)
+
+// ContextExtractor returns fields taken from a context, e.g. a user ID stored by an authentication middleware.
+// It returns nil if the context carries none.
+type ContextExtractor func(ctx context.Context) logWriter.Fields
+
+//contextKey is the type of the keys under which this package stores values in a context.
+type contextKey int
+
+const (
+	loggerKey       contextKey = iota //key of the logger stored by NewContext
+	contextFieldKey                   //key of the fields stored by ContextWithFields
+)
+
+// NewContext returns a copy of ctx carrying l, so that code further down the call chain can log through it
+// with FromContext.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+// FromContext returns the logger stored in ctx by NewContext, or the default logger if ctx carries none, with
+// the context's fields attached as by WithContext. It returns nil if there is neither.
+func FromContext(ctx context.Context) *Logger {
+	l, _ := ctx.Value(loggerKey).(*Logger)
+	if l == nil {
+		l = Default()
+	}
+	if l == nil {
+		return nil
+	}
+	return l.WithContext(ctx)
+}
+
+// ContextWithFields returns a copy of ctx carrying the given request-scoped fields in addition to those ctx
+// already carries, e.g. a request ID set by a middleware:
+//
+//	ctx = logger.ContextWithFields(ctx, logWriter.Fields{"request_id": id})
+//	...
+//	myLogger.WithContext(ctx).Info("order created")
+//
+// Fields given here replace fields of ctx with the same key. The map is copied, so the caller may reuse it.
+func ContextWithFields(ctx context.Context, fields logWriter.Fields) context.Context {
+	existing, _ := ctx.Value(contextFieldKey).(logWriter.Fields)
+	merged := make(logWriter.Fields, len(existing)+len(fields))
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return context.WithValue(ctx, contextFieldKey, merged)
+}
+
+// WithContext returns a logger attaching the request-scoped fields of ctx to every entry it logs: the fields
+// stored with ContextWithFields and those returned by the extractors added with WithContextExtractor.
+// Context fields replace fields of this logger with the same key. A ctx without fields returns the logger
+// itself.
+func (logger *Logger) WithContext(ctx context.Context) *Logger {
+	if ctx == nil {
+		return logger
+	}
+	fields, _ := ctx.Value(contextFieldKey).(logWriter.Fields)
+	for _, extract := range logger.extractors {
+		extracted := extract(ctx)
+		if len(extracted) == 0 {
+			continue
+		}
+		if len(fields) == 0 {
+			fields = extracted
+			continue
+		}
+		merged := make(logWriter.Fields, len(fields)+len(extracted))
+		for key, value := range fields {
+			merged[key] = value
+		}
+		for key, value := range extracted {
+			merged[key] = value
+		}
+		fields = merged
+	}
+	if len(fields) == 0 {
+		return logger
+	}
+	return logger.WithFields(fields)
+}
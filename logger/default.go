@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"log"
+	"strings"
+	"sync/atomic"
+)
+
+//defaultLogger holds the defaults set with SetDefault.
+var defaultLogger atomic.Value
+
+//defaults is the logger set with SetDefault together with the logger the package-level functions log through.
+type defaults struct {
+	logger *Logger //logger set with SetDefault
+	caller *Logger //logger skipping the package-level function to find the caller, nil if logger is nil
+}
+
+// SetDefault makes l the logger used by the package-level logging functions such as Info and Errorf, so that
+// small programs do not need to pass a *Logger to every function:
+//
+//	myLogger, err := logger.New(logger.WithFile("app.log"))
+//	...
+//	logger.SetDefault(myLogger)
+//	logger.Infof("listening on %s", addr)
+//
+// SetDefault(nil) goes back to the standard library fallback described at Default.
+func SetDefault(l *Logger) {
+	d := defaults{logger: l}
+	if l != nil {
+		d.caller = l.AddCallerSkip(1)
+	}
+	defaultLogger.Store(d)
+}
+
+// Default returns the logger set with SetDefault, or nil if there is none. Until one is set, the
+// package-level functions write Info and more severe messages with the standard library's log package, to
+// stderr by default, so that nothing logged before the logger is set up is lost.
+func Default() *Logger {
+	d, _ := defaultLogger.Load().(defaults)
+	return d.logger
+}
+
+//This method returns the logger the package-level functions log through, nil if no default is set.
+func defaultCaller() *Logger {
+	d, _ := defaultLogger.Load().(defaults)
+	return d.caller
+}
+
+//This method writes a message with the standard library's log package when no default logger is set.
+// Messages more verbose than Info are discarded.
+func stdLog(level logWriter.Level, message string) {
+	if logWriter.InfoLevel.Enables(level) {
+		log.Print(level.Prefix() + strings.TrimSuffix(message, "\n"))
+	}
+}
+
+// Trace logs a message at level Trace on the default logger, see SetDefault.
+func Trace(args ...interface{}) {
+	if l := defaultCaller(); l != nil {
+		l.Trace(args...)
+		return
+	}
+	stdLog(logWriter.TraceLevel, fmt.Sprintln(args...))
+}
+
+// Tracef logs a formatted message at level Trace on the default logger, see SetDefault.
+func Tracef(format string, args ...interface{}) {
+	if l := defaultCaller(); l != nil {
+		l.Tracef(format, args...)
+		return
+	}
+	stdLog(logWriter.TraceLevel, fmt.Sprintf(format, args...))
+}
+
+// Debug logs a message at level Debug on the default logger, see SetDefault.
+func Debug(args ...interface{}) {
+	if l := defaultCaller(); l != nil {
+		l.Debug(args...)
+		return
+	}
+	stdLog(logWriter.DebugLevel, fmt.Sprintln(args...))
+}
+
+// Debugf logs a formatted message at level Debug on the default logger, see SetDefault.
+func Debugf(format string, args ...interface{}) {
+	if l := defaultCaller(); l != nil {
+		l.Debugf(format, args...)
+		return
+	}
+	stdLog(logWriter.DebugLevel, fmt.Sprintf(format, args...))
+}
+
+// Info logs a message at level Info on the default logger, see SetDefault.
+func Info(args ...interface{}) {
+	if l := defaultCaller(); l != nil {
+		l.Info(args...)
+		return
+	}
+	stdLog(logWriter.InfoLevel, fmt.Sprintln(args...))
+}
+
+// Infof logs a formatted message at level Info on the default logger, see SetDefault.
+func Infof(format string, args ...interface{}) {
+	if l := defaultCaller(); l != nil {
+		l.Infof(format, args...)
+		return
+	}
+	stdLog(logWriter.InfoLevel, fmt.Sprintf(format, args...))
+}
+
+// Warn logs a message at level Warn on the default logger, see SetDefault.
+func Warn(args ...interface{}) {
+	if l := defaultCaller(); l != nil {
+		l.Warn(args...)
+		return
+	}
+	stdLog(logWriter.WarnLevel, fmt.Sprintln(args...))
+}
+
+// Warnf logs a formatted message at level Warn on the default logger, see SetDefault.
+func Warnf(format string, args ...interface{}) {
+	if l := defaultCaller(); l != nil {
+		l.Warnf(format, args...)
+		return
+	}
+	stdLog(logWriter.WarnLevel, fmt.Sprintf(format, args...))
+}
+
+// Error logs a message at level Error on the default logger, see SetDefault.
+func Error(args ...interface{}) {
+	if l := defaultCaller(); l != nil {
+		l.Error(args...)
+		return
+	}
+	stdLog(logWriter.ErrorLevel, fmt.Sprintln(args...))
+}
+
+// Errorf logs a formatted message at level Error on the default logger, see SetDefault.
+func Errorf(format string, args ...interface{}) {
+	if l := defaultCaller(); l != nil {
+		l.Errorf(format, args...)
+		return
+	}
+	stdLog(logWriter.ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+// Fatal logs a message at level Fatal on the default logger, closes it and exits the process with status 1.
+func Fatal(args ...interface{}) {
+	if l := defaultCaller(); l != nil {
+		l.Fatal(args...)
+	}
+	log.Fatal(logWriter.FatalLevel.Prefix() + strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+// Fatalf logs a formatted message at level Fatal on the default logger, closes it and exits the process with
+// status 1.
+func Fatalf(format string, args ...interface{}) {
+	if l := defaultCaller(); l != nil {
+		l.Fatalf(format, args...)
+	}
+	log.Fatal(logWriter.FatalLevel.Prefix() + fmt.Sprintf(format, args...))
+}
+
+// Panic logs a message at level Panic on the default logger, flushes it and panics with the message.
+func Panic(args ...interface{}) {
+	if l := defaultCaller(); l != nil {
+		l.Panic(args...)
+	}
+	log.Panic(logWriter.PanicLevel.Prefix() + strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+// Panicf logs a formatted message at level Panic on the default logger, flushes it and panics with the
+// message.
+func Panicf(format string, args ...interface{}) {
+	if l := defaultCaller(); l != nil {
+		l.Panicf(format, args...)
+	}
+	log.Panic(logWriter.PanicLevel.Prefix() + fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+)
+
+// destination is a named destination added with AddDestination.
+type destination struct {
+	name  string            //name used with To
+	path  string            //path of the destination's file
+	route *logWriter.Worker //worker writing the destination's file
+}
+
+// AddDestination adds a named destination writing to its own file, created in logDir like the main log file.
+// Entries logged through To(name) go to that file instead of the main one. A destination has its own buffer
+// and flush timer but shares the logger's channel and worker.
+func (logger *Logger) AddDestination(name string, fileName string, logDir string) error {
+	if len(name) == 0 {
+		return fmt.Errorf("destination name must not be empty")
+	}
+	logger.destLock.Lock()
+	defer logger.destLock.Unlock()
+	select {
+	case <-logger.stopCh:
+		return fmt.Errorf("logger is closed")
+	default:
+	}
+	if err := createDir(logDir, logger.permissions.dirMode); err != nil {
+		return err
+	}
+	filePath := logDir + fileName
+	file, err := openLogFile(filePath, logger.permissions)
+	if err != nil {
+		return err
+	}
+	route := logWriter.NewWorkerWithOptions(file, nil, logger.errorCallback, logger.workerOptions)
+	if err = logger.worker.AddRoute(name, route); err != nil {
+		file.Close()
+		return err
+	}
+	logger.destinations = append(logger.destinations, destination{name: name, path: filePath, route: route})
+	return nil
+}
+
+// To returns a logger sharing this logger's level, status, fields, module and worker whose entries carry the given
+// destination hint. Entries are written to the destination added with AddDestination under that name, or to the main log
+// file if there is no such destination. To is cheap, so it can be used inline:
+//
+//	myLogger.To("audit").Info("user", id, "deleted", item)
+func (logger *Logger) To(destination string) *Logger {
+	derived := *logger
+	derived.destination = destination
+	return &derived
+}
+
+// RouteLevels sends the entries logged at the given levels to the destination added with AddDestination under
+// name, unless they were logged through To, e.g. to keep errors in a file of their own:
+//
+//	myLogger.AddDestination("errors", "error.log", "logs/")
+//	myLogger.RouteLevels("errors", logWriter.ErrorLevel, logWriter.FatalLevel, logWriter.PanicLevel)
+//
+// Entries at the other levels still go to the main log file. A later call for a level replaces the earlier one.
+func (logger *Logger) RouteLevels(name string, levels ...logWriter.Level) error {
+	logger.destLock.Lock()
+	defer logger.destLock.Unlock()
+	found := false
+	for _, dest := range logger.destinations {
+		found = found || dest.name == name
+	}
+	if !found {
+		return fmt.Errorf("no destination %q", name)
+	}
+	current, _ := logger.levelRoutes.Load().(map[logWriter.Level]string)
+	routes := make(map[logWriter.Level]string, len(current)+len(levels))
+	for level, destination := range current {
+		routes[level] = destination
+	}
+	for _, level := range levels {
+		routes[level] = name
+	}
+	logger.levelRoutes.Store(routes)
+	return nil
+}
+
+//This method returns the destination of entries logged at level: the logger's own, set with To, or else the one
+// set for the level with RouteLevels, "" for the main log file.
+func (logger *Logger) destinationFor(level logWriter.Level) string {
+	if len(logger.destination) > 0 {
+		return logger.destination
+	}
+	routes, _ := logger.levelRoutes.Load().(map[logWriter.Level]string)
+	return routes[level]
+}
+
+//levelFile is a file given to WithLevelFile with the levels written to it.
+type levelFile struct {
+	path   string            //path of the file
+	levels []logWriter.Level //levels of the entries written to it
+}
+
+// WithLevelFile writes the entries logged at the given levels to the file at path instead of the main log file,
+// e.g. WithLevelFile("error.log", logWriter.ErrorLevel, logWriter.FatalLevel, logWriter.PanicLevel). The file is
+// a destination named after its path, with a buffer of its own, see AddDestination and RouteLevels; relative
+// paths are relative to the directory given to WithDir.
+func WithLevelFile(path string, levels ...logWriter.Level) Option {
+	return func(o *options) {
+		for i := range o.levelFiles {
+			if o.levelFiles[i].path == path {
+				o.levelFiles[i].levels = append(o.levelFiles[i].levels, levels...)
+				return
+			}
+		}
+		o.levelFiles = append(o.levelFiles, levelFile{path: path, levels: levels})
+	}
+}
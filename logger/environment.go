@@ -0,0 +1,215 @@
+package logger
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/utils"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EnvironmentPrefix is the prefix of the environment variables that override config keys. The variable for a
+// key is the prefix and the upper cased key joined by an underscore, e.g. LOGGER_MAX_SIZE=200MB overrides
+// max_size and LOGGER_STDERR_LEVEL=warn overrides stderr_level. Lists and maps are given as JSON, e.g.
+// LOGGER_REDACT_KEYS='["password", "token"]'.
+const EnvironmentPrefix = "LOGGER"
+
+// ApplyEnvironment overrides the fields of config with the values of the matching environment variables (see
+// EnvironmentPrefix). LoadConfig calls it after reading the file, so the precedence is: built-in defaults,
+// then the config file, then the environment.
+func (config *Config) ApplyEnvironment() error {
+	_, err := applyEnvironment(reflect.ValueOf(config).Elem(), []string{EnvironmentPrefix}, "", os.LookupEnv)
+	return err
+}
+
+// WithEnvironment makes the LOGGER_LEVEL, LOGGER_FILE, LOGGER_FORMAT and LOGGER_FLUSH_INTERVAL environment
+// variables override the options given to New, so that a container can be switched to debug logging without a
+// rebuild. Loggers ignore them otherwise; those created by NewFromConfig, LoadConfig and Register honour every
+// LOGGER_* variable through ApplyEnvironment instead.
+func WithEnvironment() Option {
+	return func(o *options) {
+		o.environment = true
+	}
+}
+
+// WithoutEnvironment undoes an earlier WithEnvironment, e.g. one in a shared list of options, for loggers whose
+// settings must not change with the deployment.
+func WithoutEnvironment() Option {
+	return func(o *options) {
+		o.environment = false
+	}
+}
+
+//This method overrides the settings given to New with the environment variables LOGGER_LEVEL, LOGGER_FILE,
+// LOGGER_FORMAT (text, json, ecs, csv or binary) and LOGGER_FLUSH_INTERVAL (e.g. 5s), so that containers can
+// be reconfigured without a rebuild. A relative LOGGER_FILE is relative to the directory given to WithDir.
+func (o *options) applyEnvironment(lookup func(string) (string, bool)) error {
+	if !o.environment {
+		return nil
+	}
+	variable := func(key string) (string, string, bool) {
+		name := EnvironmentPrefix + "_" + key
+		value, ok := lookup(name)
+		return name, value, ok
+	}
+	if name, value, ok := variable("LEVEL"); ok {
+		level, err := logWriter.ParseLevel(value)
+		if err != nil {
+			return fmt.Errorf("environment variable %s: %v", name, err)
+		}
+		o.level = level
+	}
+	if name, value, ok := variable("FILE"); ok {
+		if len(value) == 0 {
+			return fmt.Errorf("environment variable %s: empty file name", name)
+		}
+		o.file = value
+	}
+	if name, value, ok := variable("FORMAT"); ok {
+		formatter, err := formatterFor(&Config{Format: value, TimeLayout: o.worker.TimeLayout, UTC: o.worker.UTC,
+			Sequence: o.worker.Sequence})
+		if err != nil {
+			return fmt.Errorf("environment variable %s: %v", name, err)
+		}
+		o.worker.Formatter = formatter
+	}
+	if name, value, ok := variable("FLUSH_INTERVAL"); ok {
+		interval, err := utils.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("environment variable %s: invalid interval %q", name, value)
+		}
+		o.worker.FlushInterval = interval
+	}
+	return nil
+}
+
+//This method walks the fields of the struct value and sets every field for which one of the candidate
+// environment variable names is set. It returns the dotted keys of the fields that were overridden.
+func applyEnvironment(value reflect.Value, prefixes []string, parent string,
+	lookup func(string) (string, bool)) ([]string, error) {
+	var overridden []string
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+		key := field.Name
+		if tag := field.Tag.Get("json"); len(tag) > 0 {
+			if tag == "-" {
+				continue
+			}
+			if name := strings.Split(tag, ",")[0]; len(name) > 0 {
+				key = name
+			}
+		}
+		names := environmentNames(prefixes, key)
+		fieldValue := value.Field(i)
+		dottedKey := joinKey(parent, key)
+
+		if fieldValue.Kind() == reflect.Struct && !isUnmarshaler(fieldValue) {
+			keys, err := applyEnvironment(fieldValue, names, dottedKey, lookup)
+			if err != nil {
+				return nil, err
+			}
+			overridden = append(overridden, keys...)
+			continue
+		}
+		for _, name := range names {
+			if raw, ok := lookup(name); ok {
+				if err := setFromString(fieldValue, raw); err != nil {
+					return nil, fmt.Errorf("environment variable %s: %v", name, err)
+				}
+				overridden = append(overridden, dottedKey)
+				break
+			}
+		}
+	}
+	return overridden, nil
+}
+
+//This method returns the environment variable names for key under each of the prefixes: the key upper cased
+// as is and, for camel case keys, upper cased with its words separated by underscores.
+func environmentNames(prefixes []string, key string) []string {
+	forms := []string{strings.ToUpper(key)}
+	if snake := snakeUpper(key); snake != forms[0] {
+		forms = append(forms, snake)
+	}
+	names := make([]string, 0, len(prefixes)*len(forms))
+	for _, prefix := range prefixes {
+		for _, form := range forms {
+			names = append(names, prefix+"_"+form)
+		}
+	}
+	return names
+}
+
+//This method converts a camel case key such as "maxSize" to "MAX_SIZE".
+func snakeUpper(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+//This method reports whether the value decodes itself from text or JSON.
+func isUnmarshaler(value reflect.Value) bool {
+	pointer := reflect.PtrTo(value.Type())
+	return pointer.Implements(textUnmarshalerType) || pointer.Implements(jsonUnmarshalerType)
+}
+
+//This method parses raw into value according to value's type. Composite values are given as JSON.
+func setFromString(value reflect.Value, raw string) error {
+	target := value.Addr().Interface()
+	if unmarshaler, ok := target.(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(raw))
+	}
+	if _, ok := target.(json.Unmarshaler); ok {
+		if err := json.Unmarshal([]byte(raw), target); err == nil {
+			return nil
+		}
+		quoted, _ := json.Marshal(raw)
+		return json.Unmarshal(quoted, target)
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(f)
+	default:
+		return json.Unmarshal([]byte(raw), target)
+	}
+	return nil
+}
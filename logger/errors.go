@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+)
+
+//number of failures Errors holds for a slow reader before further failures are discarded.
+const errorChannelSize = 64
+
+// OpError is a failure of the logger as delivered by Errors: the error, the operation that failed, e.g.
+// logWriter.OpWrite, and the lost entry when a single entry was lost.
+type OpError struct {
+	Op    string           //operation that failed, one of the logWriter.Op constants
+	Entry *logWriter.Entry //entry that was lost, nil if none or several were
+	Err   error            //underlying error, a *logWriter.FlushError for failed writes of the buffer
+}
+
+func (e *OpError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Op, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// Errors returns a channel delivering the logger's failures as *OpError values, an alternative to the error
+// callback for applications that want to handle them in their own goroutine:
+//
+//	go func() {
+//		for err := range myLogger.Errors() {
+//			metrics.Count("log_failures", 1)
+//		}
+//	}()
+//
+// The worker never waits for the reader: the channel holds up to 64 unread failures and further ones are
+// discarded until there is room. The channel is closed by CloseLogger, after the final flush.
+func (logger *Logger) Errors() <-chan error {
+	return logger.errs
+}
+
+//This method delivers a failure on the error channel unless it is full or closed.
+func (logger *Logger) publishError(err error, op string, entry *logWriter.Entry) {
+	logger.errLock.Lock()
+	defer logger.errLock.Unlock()
+	if logger.errsClosed {
+		return
+	}
+	select {
+	case logger.errs <- &OpError{Op: op, Entry: entry, Err: err}:
+	default:
+	}
+}
+
+//This method closes the error channel. Failures reported afterwards are discarded.
+func (logger *Logger) closeErrorChannel() {
+	logger.errLock.Lock()
+	defer logger.errLock.Unlock()
+	if !logger.errsClosed {
+		logger.errsClosed = true
+		close(logger.errs)
+	}
+}
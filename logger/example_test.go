@@ -0,0 +1,105 @@
+package logger_test
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+//This method prints the file at path, or why it could not be read.
+func printFile(path string) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Print(string(data))
+}
+
+//This method returns a formatter for the pattern that panics on errors. The examples leave out the time so that
+// their output is stable.
+func exampleFormatter(pattern string) logWriter.Formatter {
+	formatter, err := logWriter.ParsePattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return formatter
+}
+
+func ExampleNew() {
+	dir, err := ioutil.TempDir("", "example")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	myLogger, err := logger.New(logger.WithFile("app.log"), logger.WithDir(dir),
+		logger.WithLevel(logWriter.InfoLevel), logger.WithFormatter(exampleFormatter("%-7L %m")))
+	if err != nil {
+		panic(err)
+	}
+	myLogger.Debug("cache miss")
+	myLogger.Info("server started")
+	myLogger.Warnf("disk %d%% full", 91)
+	report := myLogger.CloseLogger()
+
+	printFile(filepath.Join(dir, "app.log"))
+	fmt.Println("flushed:", report.EntriesFlushed)
+	// Output:
+	// INFO    server started
+	// WARNING disk 91% full
+	// flushed: 2
+}
+
+func ExampleLogger_With() {
+	dir, err := ioutil.TempDir("", "example")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	myLogger, err := logger.New(logger.WithFile("app.log"), logger.WithDir(dir),
+		logger.WithFormatter(exampleFormatter("%-7L %m %f")))
+	if err != nil {
+		panic(err)
+	}
+	billing := myLogger.With("service", "billing", "version", "1.4.2")
+	billing.Info("started")
+	billing.With("invoice", 1042).Error("charge declined")
+	myLogger.CloseLogger()
+
+	printFile(filepath.Join(dir, "app.log"))
+	// Output:
+	// INFO    started service=billing version=1.4.2
+	// ERROR   charge declined service=billing version=1.4.2 invoice=1042
+}
+
+func ExampleWithLevelFile() {
+	dir, err := ioutil.TempDir("", "example")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	myLogger, err := logger.New(logger.WithFile("app.log"), logger.WithDir(dir),
+		logger.WithFormatter(exampleFormatter("%-7L %m")), logger.WithLevelFile("error.log", logWriter.ErrorLevel))
+	if err != nil {
+		panic(err)
+	}
+	myLogger.Info("request handled")
+	myLogger.Error("request failed")
+	myLogger.CloseLogger()
+
+	fmt.Println("app.log:")
+	printFile(filepath.Join(dir, "app.log"))
+	fmt.Println("error.log:")
+	printFile(filepath.Join(dir, "error.log"))
+	// Output:
+	// app.log:
+	// INFO    request handled
+	// error.log:
+	// ERROR   request failed
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"sync/atomic"
+)
+
+// WithFields returns a logger sharing this logger's level, status and worker that attaches the given fields,
+// in addition to this logger's own, to every entry it logs:
+//
+//	myLogger.WithFields(logWriter.Fields{"user": id}).Info("login ok")
+//
+// Fields given here replace fields of this logger with the same key. The map is copied, so the caller may
+// reuse it.
+func (logger *Logger) WithFields(fields logWriter.Fields) *Logger {
+	merged := make(logWriter.Fields, len(logger.fields)+len(fields))
+	for key, value := range logger.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	derived := *logger
+	derived.fields = merged
+	derived.shardKey = atomic.AddUint64(&logger.derived, 1)
+	return &derived
+}
+
+// WithField returns a logger that attaches one more field to its entries, see WithFields.
+func (logger *Logger) WithField(key string, value interface{}) *Logger {
+	return logger.WithFields(logWriter.Fields{key: value})
+}
+
+// WithError returns a logger that attaches err to its entries as the "error" field, written as its message.
+// An error wrapping others, e.g. with fmt.Errorf and %w, also gets an "error_chain" field listing the message
+// of every error in the chain, so that the root cause is visible in aggregated logs, see
+// logWriter.ErrorChain:
+//
+//	myLogger.WithError(err).Error("checkout failed")
+//	//[ERROR] ... checkout failed error="charge card: gateway timeout" error_chain="charge card -> gateway timeout"
+//
+// A nil err attaches no field.
+func (logger *Logger) WithError(err error) *Logger {
+	if err == nil {
+		return logger.WithFields(nil)
+	}
+	fields := logWriter.Fields{"error": err}
+	if chain := logWriter.NewErrorChain(err); len(chain) > 1 {
+		fields["error_chain"] = chain
+	}
+	return logger.WithFields(fields)
+}
+
+// With returns a child logger sharing this logger's level, status and worker that bakes the given key/value
+// pairs into every entry it logs, ahead of all other fields and in the order given, e.g. the service, version
+// and component:
+//
+//	billing := myLogger.With("service", "billing", "version", version)
+//	billing.Info("started") //[INFO]  ... started service=billing version=1.4.2
+//
+// Keys that are not strings are converted with fmt.Sprint; a key without a value gets the value "!MISSING".
+// Calling With on a child appends to its pairs, and a key given again keeps its position but takes the new
+// value. Creating a child costs one copy of the fields, so children are best created once and kept.
+func (logger *Logger) With(pairs ...interface{}) *Logger {
+	fields := make(logWriter.Fields, (len(pairs)+1)/2)
+	leading := append([]string(nil), logger.leading...)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			key = fmt.Sprint(pairs[i])
+		}
+		var value interface{} = "!MISSING"
+		if i+1 < len(pairs) {
+			value = pairs[i+1]
+		}
+		if _, exists := fields[key]; !exists && !contains(logger.leading, key) {
+			leading = append(leading, key)
+		}
+		fields[key] = value
+	}
+	derived := logger.WithFields(fields)
+	derived.leading = leading
+	return derived
+}
+
+//Util method that reports whether keys contains key.
+func contains(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// LevelOnSignal makes the logger one level more verbose whenever the process receives raise, e.g. from Info to
+// Debug, and one level less verbose whenever it receives lower, until the returned function is called or the
+// logger is closed. Nil signals default to SIGUSR1 and SIGUSR2, which do not exist on Windows, so that
+//
+//	kill -USR1 $(cat /var/run/app.pid)
+//
+// turns on debug logging of a running service. The level moves between Error and Trace; custom levels move to
+// the next built-in level. Every change is logged at level Warn, even if the new level filters warnings out.
+func (logger *Logger) LevelOnSignal(raise os.Signal, lower os.Signal) (stop func()) {
+	if raise == nil {
+		raise = defaultLevelSignals[0]
+	}
+	if lower == nil {
+		lower = defaultLevelSignals[1]
+	}
+	var signals []os.Signal
+	for _, sig := range []os.Signal{raise, lower} {
+		if sig != nil {
+			signals = append(signals, sig)
+		}
+	}
+	stopped := make(chan struct{})
+	var once sync.Once
+	stop = func() {
+		once.Do(func() { close(stopped) })
+	}
+	if len(signals) == 0 {
+		return stop
+	}
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+	go func() {
+		defer signal.Stop(received)
+		for {
+			select {
+			case sig := <-received:
+				logger.stepLevel(sig == raise, sig)
+			case <-stopped:
+				return
+			case <-logger.stopCh:
+				return
+			}
+		}
+	}()
+	return stop
+}
+
+//This method moves the level one built-in level up, towards Trace, or down, not below Error, and logs the
+// change.
+func (logger *Logger) stepLevel(up bool, sig os.Signal) {
+	previous := logger.GetLevel()
+	next := previous
+	for _, level := range logWriter.AllLevels {
+		if up && !previous.Enables(level) {
+			next = level
+			break
+		}
+		if !up && previous.Enables(level) && !level.Enables(previous) {
+			next = level
+		}
+	}
+	if next == previous || !next.Enables(logWriter.ErrorLevel) {
+		return
+	}
+	logger.SetLevel(next)
+	logger.announce("level changed from %s to %s on %s", previous, next, sig)
+}
+
+//This method logs a warning about a change made to the running logger, even if the level filters warnings out.
+func (logger *Logger) announce(format string, args ...interface{}) {
+	if logger.status.Get() {
+		logger.send(logWriter.NewFormattedEntry(logWriter.WarnLevel, format, args))
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package logger
+
+import (
+	"os"
+)
+
+//signals LevelOnSignal raises and lowers the level on by default: none, as there is no SIGUSR1 or SIGUSR2.
+var defaultLevelSignals = [2]os.Signal{}
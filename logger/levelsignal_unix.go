@@ -0,0 +1,11 @@
+//go:build unix
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+//signals LevelOnSignal raises and lowers the level on by default.
+var defaultLevelSignals = [2]os.Signal{syscall.SIGUSR1, syscall.SIGUSR2}
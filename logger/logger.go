@@ -5,89 +5,258 @@ import (
 	"github.com/shyamgrover/go-lite-logger/utils"
 	"log"
 	"os"
+	"path/filepath"
+	"runtime"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 type Logger struct {
-	once        sync.Once            //for singleton operations
-	filename    string               //logfile with complete path
-	logFile     *os.File             //logFile represents an open file descriptor
-	*log.Logger                      //logger instance
-	logLevel    logWriter.Level      //logger log level
-	status      utils.TAtomBool      //logger status..on or off
-	channel     chan logWriter.Entry //log entries will go on to this channel
-	stopCh      chan struct{}        //stop indicator channel for logger shutdown purposes
-	worker      *logWriter.Worker    //worker that will read log entries from channel and will write to file
+	*loggerCore                  //state shared by this logger and the loggers derived from it
+	destination string           //destination hint attached to every entry logged through this logger
+	fields      logWriter.Fields //fields attached to every entry logged through this logger, never modified
+	module      *module          //module of a logger returned by Named, nil otherwise
+	leading     []string         //keys of the fields added with With, in order, never modified
+	shardKey    uint64           //number of the logger among those derived with WithFields, see ShardByLogger
+	callerSkip  int              //extra stack frames skipped to find the caller, see AddCallerSkip
+}
+
+//loggerCore holds the state shared by a logger and the loggers derived from it with To.
+type loggerCore struct {
+	sequence      uint64                  //last assigned entry sequence number, first for 64-bit atomic alignment
+	shardTurn     uint64                  //entries handed out by ShardRoundRobin
+	derived       uint64                  //loggers derived with WithFields, numbering their shard keys
+	dropped       uint64                  //entries logged after the logger was closed or discarded by the overflow policy
+	enqueued      uint64                  //entries put on the channel
+	resampled     uint64                  //entries left out by samplers replaced on reload
+	once          sync.Once               //for singleton operations
+	filename      string                  //logfile with complete path
+	logFile       *os.File                //logFile represents an open file descriptor
+	fallbackFile  *os.File                //file opened for WithFallbackFile, nil if none
+	*log.Logger                           //logger instance
+	logLevel      logWriter.Level         //logger log level
+	status        utils.TAtomBool         //logger status..on or off
+	channel       chan logWriter.Entry    //log entries will go on to this channel
+	queue         *logWriter.Ring         //ring the entries go on instead of channel, nil without WithRingQueue
+	synchronous   bool                    //entries are handed to the worker on the logging goroutine, see WithSynchronous
+	handleLock    sync.Mutex              //serializes the entries handed to the worker by synchronous loggers
+	overflow      OverflowPolicy          //what logging calls do when the channel is full
+	stopCh        chan struct{}           //stop indicator channel for logger shutdown purposes
+	sendLock      sync.RWMutex            //held for reading while sending on channel and for writing to close stopCh
+	worker        *logWriter.Worker       //worker that will read log entries from channel and will write to file
+	shards        []*shard                //workers besides worker given by WithShards
+	shardBy       ShardStrategy           //which of the workers handles an entry
+	errorCallback utils.ErrorFunction     //user defined error callback, also used by destination workers
+	workerOptions logWriter.WorkerOptions //buffer size, flush interval, formatter and rotation, also used by destination workers
+	permissions   permissions             //modes and checks of the log files, also used for destination files
+	verbosity     verbosityWindow         //state of a temporary debug window opened by EnableDebugFor
+	scopes        scopedLevels            //level overrides for entries logged from particular packages or files
+	destLock      sync.Mutex              //guards destinations
+	destinations  []destination           //destinations added with AddDestination
+	levelRoutes   atomic.Value            //map[logWriter.Level]string of the destinations set with RouteLevels
+	report        CloseReport             //result of CloseLogger
+	extractors    []ContextExtractor      //extractors added with WithContextExtractor, used by WithContext
+	stackLevel    logWriter.Level         //least severe level whose entries get a stack, see WithStackTrace
+	stackDepth    int                     //frames of the stacks recorded, 0 to record none
+	errs          chan error              //failures delivered by Errors
+	errLock       sync.Mutex              //guards sending on errs against closing it
+	errsClosed    bool                    //set once errs is closed
+	moduleLock    sync.Mutex              //guards modules
+	modules       map[string]*module      //modules created by Named, keyed by full name
+	sampling      atomic.Value            //*sampler of the levels given to WithSampling or reloaded, nil if none
+	ring          *traceRing              //latest filtered out Debug and Trace entries, nil without WithTraceOnError
+	onceKeys      sync.Map                //onceKey of the messages logged with the Once methods
+	everySites    sync.Map                //time in Unix nanoseconds, as *int64, of the last entry of each call site of the Every methods
+	everyLines    sync.Map                //the times of everySites keyed by file:line, shared by call sites on the same line
+	levelCounts   sync.Map                //entries put on the channel at each level, as *uint64 keyed by logWriter.Level
+	failures      sync.Map                //failures reported to the error handler, as *uint64 keyed by operation
 }
 
 //This method initializes the channel on which log entries will go. Initiates stopChannel for signalling
-// logger stop. Creates a new worker and calls worker's work method in a separate goroutine.
-func (logger *Logger) init(file *os.File, errorCallback utils.ErrorFunction) {
-	logger.channel = make(chan logWriter.Entry, 2048)
+// logger stop. Creates a new worker, and the shards of WithShards writing to shardFiles or to file if it is
+// nil, and calls their work methods in separate goroutines unless the logger is synchronous.
+func (logger *Logger) init(file *os.File, shardFiles []*os.File, o options) {
+	switch {
+	case o.synchronous:
+		logger.synchronous = true
+	case o.ringQueue:
+		logger.queue = logWriter.NewRing(o.channelSize)
+	default:
+		logger.channel = make(chan logWriter.Entry, o.channelSize)
+	}
 	logger.stopCh = make(chan struct{})
-	logger.worker = logWriter.NewWorker(file, logger.channel, errorCallback)
-	go logger.worker.Work()
+	logger.overflow = o.overflow
+	logger.errorCallback = o.errorCallback
+	logger.errs = make(chan error, errorChannelSize)
+	handler := o.worker.ErrorHandler
+	o.worker.ErrorHandler = func(err error, op string, entry *logWriter.Entry) {
+		count(&logger.failures, op)
+		if handler != nil {
+			handler(err, op, entry)
+		}
+		logger.publishError(err, op, entry)
+	}
+	logger.workerOptions = o.worker
+	logger.permissions = o.permissions
+	logger.sampling.Store(newSampler(o.sampling, logger.now()))
+	if o.traceRing > 0 {
+		logger.ring = newTraceRing(o.traceRing)
+	}
+	logger.extractors = o.extractors
+	logger.stackLevel = o.stackLevel
+	logger.stackDepth = o.stackDepth
+	if logger.queue != nil {
+		logger.worker = logWriter.NewRingWorker(file, logger.queue, o.errorCallback, o.worker)
+	} else {
+		logger.worker = logWriter.NewWorkerWithOptions(file, logger.channel, o.errorCallback, o.worker)
+	}
+	logger.worker.RetainOnFailure(o.retain)
+	logger.shardBy = o.shardBy
+	logger.startShards(o.shards, shardFiles, o)
+	if !logger.synchronous {
+		go logger.worker.Work()
+	}
 }
 
 //This method creates a new logger instance and returns it to the caller if success, else returns error.
 // This takes logger level, logFileName,logs directory and an error callback method which is called in case of aney error.
+// The file path is logDir and fileName concatenated, so logDir needs a trailing separator. New takes the same
+// settings as options and can be extended without breaking callers.
 func CreateLogger(logLevel logWriter.Level, fileName string, logDir string, errorCallback utils.ErrorFunction) (*Logger, error) {
+	if err := createDir(logDir, defaultDirMode); err != nil {
+		return nil, err
+	}
+	return New(WithLevel(logLevel), WithFile(logDir+fileName), WithErrorCallback(errorCallback))
+}
+
+//Util method that creates logDir, if given, with the given mode when it does not exist.
+func createDir(logDir string, mode os.FileMode) error {
 	if len(logDir) > 0 {
 		if _, err := os.Stat(logDir); os.IsNotExist(err) {
-			err = os.MkdirAll(logDir, 0755)
-			if err != nil {
-				return nil, err
+			if err = os.MkdirAll(logDir, mode); err != nil {
+				return err
 			}
+			return os.Chmod(logDir, mode)
 		}
-	} else {
-		logDir = ""
 	}
-	filePath := logDir + fileName
-	myLogger, file, err := getInstance(logLevel, filePath)
-	if err == nil {
-		myLogger.init(file, errorCallback)
-		return myLogger, nil
-	} else {
+	return nil
+}
+
+//Util method that creates the directory of filePath if needed and opens the file for appending, both with the
+// modes of perm, and checks the file against perm. If success, returns the opened file and if error returns
+// error to the caller.
+func openLogFile(filePath string, perm permissions) (*os.File, error) {
+	if err := createDir(filepath.Dir(filePath), perm.dirMode); err != nil {
 		return nil, err
 	}
+	file, err := logWriter.OpenFile(filePath, perm.fileMode)
+	if err != nil {
+		return nil, err
+	}
+	if err = perm.check(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
 }
 
-//Util method that opens a file and creates new logger instance. If success, returns logger, opened file and nil value
-// for error and if error returns error to the caller and nil vaules for logger and file.
-func getInstance(level logWriter.Level, filePath string) (*Logger, *os.File, error) {
-	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err == nil {
-		return &Logger{
-			filename: filePath,
-			logLevel: level,
-			status:   utils.TAtomBool{Flag: 1},
-			logFile:  file,
-		}, file, nil
-	} else {
-		return nil, nil, err
-	}
+//Util method that creates new logger instance writing to the given, already opened, file.
+func getInstance(level logWriter.Level, filePath string, file *os.File) *Logger {
+	return &Logger{loggerCore: &loggerCore{
+		filename: filePath,
+		logLevel: level,
+		status:   utils.TAtomBool{Flag: 1},
+		logFile:  file,
+	}}
 }
 
 //The method gracefully closes opened resources by logger. This can be called only once in entire logger lifecycle.
 // First it closes the signalChannel. Doing this, log entries donot go on the channel. Then it waits for worker
-// to close the resources. And when worker has finished closing, then it closes the logFile.
-func (logger *Logger) CloseLogger() {
+// to close the resources. And when worker has finished closing, then it closes the file the worker wrote to last,
+// which differs from logFile after a rotation. It returns a report
+// of what happened to the logged entries; later calls return the same report, with entries logged after closing
+// added to EntriesDropped.
+func (logger *Logger) CloseLogger() CloseReport {
 	logger.once.Do(func() {
+		start := time.Now()
+		logger.flushSamples()
+		logger.sendLock.Lock()
 		close(logger.stopCh)
-		logger.worker.CloseWorker()
-		logger.logFile.Close()
+		logger.sendLock.Unlock()
+		logger.verbosity.stop()
+		closeErrors := logger.worker.CloseErrors()
+
+		sinkErrors := make(map[string]error)
+		if err := closeErrors[""]; err != nil {
+			sinkErrors[logger.filename] = err
+		}
+		if err := logger.worker.File().Close(); err != nil && sinkErrors[logger.filename] == nil {
+			sinkErrors[logger.filename] = err
+		}
+		for _, s := range logger.shards {
+			if s.file == nil {
+				continue
+			}
+			name := s.file.Name()
+			if err := s.worker.File().Close(); err != nil && closeErrors[name] == nil {
+				sinkErrors[name] = err
+			}
+		}
+		logger.destLock.Lock()
+		for _, dest := range logger.destinations {
+			if err := closeErrors[dest.name]; err != nil {
+				sinkErrors[dest.path] = err
+			}
+			delete(closeErrors, dest.name)
+			if err := dest.route.File().Close(); err != nil && sinkErrors[dest.path] == nil {
+				sinkErrors[dest.path] = err
+			}
+		}
+		logger.destLock.Unlock()
+		if logger.fallbackFile != nil {
+			if err := logger.fallbackFile.Close(); err != nil {
+				sinkErrors[logger.fallbackFile.Name()] = err
+			}
+		}
+		for name, err := range closeErrors {
+			if len(name) > 0 {
+				sinkErrors[name] = err
+			}
+		}
+
+		counters := logger.worker.Counters()
+		logger.report = CloseReport{
+			EntriesFlushed: counters.EntriesFlushed,
+			EntriesDropped: counters.EntriesDropped,
+			BytesWritten:   counters.BytesWritten,
+			Duration:       time.Since(start),
+			SinkErrors:     sinkErrors,
+		}
+		logger.closeErrorChannel()
 	})
+	report := logger.report
+	report.EntriesDropped += atomic.LoadUint64(&logger.dropped)
+	return report
 }
 
-// SetLevel sets the standard logger level.
+// SetLevel sets the standard logger level. On a logger returned by Named it sets the module's level instead.
 func (logger *Logger) SetLevel(level logWriter.Level) {
+	if logger.module != nil {
+		atomic.StoreUint32(&logger.module.level, uint32(level)+1)
+		return
+	}
 	atomic.StoreUint32((*uint32)(&logger.logLevel), uint32(level))
 }
 
-// GetLevel returns the standard logger level.
+// GetLevel returns the standard logger level. On a logger returned by Named it returns the level in effect for
+// the module.
 func (logger *Logger) GetLevel() logWriter.Level {
-	return logger.logLevel
+	level := logWriter.Level(atomic.LoadUint32((*uint32)(&logger.logLevel)))
+	if logger.module != nil {
+		return logger.module.effectiveLevel(level)
+	}
+	return level
 }
 
 //SetStatus sets the standard logger status. true means logging is on and false means logging is off.
@@ -95,39 +264,188 @@ func (logger *Logger) SetStatus(status bool) {
 	logger.status.Set(status)
 }
 
+// LastError returns the most recent failure to write buffered entries to a file, or nil if there was none.
+// The returned error is a *logWriter.FlushError carrying the sequence numbers of the affected entries, so the
+// error callback can use it to find out which messages were lost.
+func (logger *Logger) LastError() error {
+	return logger.worker.LastError()
+}
+
+// RetainOnFailure keeps the contents of a buffer that failed to flush, up to maxBytes, and writes them again on
+// the next flush instead of discarding them. Zero, the default, disables retention.
+func (logger *Logger) RetainOnFailure(maxBytes int) {
+	logger.worker.RetainOnFailure(maxBytes)
+}
+
 // GetStatus returns the standard logger status. true means logging is on and false means logging is off.
 func (logger *Logger) GetStatus() bool {
 	return logger.status.Get()
 }
 
 //This method returns a boolean value indicating if this particular event is loggable or not.
-// It checks if log status is set to on and the given level >= the logger's level, then it returns true
-// otherwise false.
+// It checks if log status is set to on and the logger's level enables the given level, then it returns true.
+// Otherwise, if scoped levels are configured, it returns true when the caller matches a scope whose level
+// allows the event. It must be called directly from the exported logging methods so that the caller lookup
+// resolves to the user's call site, skipping the frames of AddCallerSkip like annotate does.
 func (logger *Logger) isLoggable(level logWriter.Level) bool {
-	return (logger.status.Get() == true &&
-		logger.logLevel >= level)
+	if logger.status.Get() == false {
+		return false
+	}
+	if logger.GetLevel().Enables(level) {
+		return true
+	}
+	return logger.scopes.allows(level, scopeCallerSkip+logger.callerSkip)
 }
 
+//number of stack frames between runtime.Callers in annotate and the user's call site: annotate, logEntry or
+// logFormattedEntry, and the exported logging method.
+const entryCallerSkip = 4
+
 //This method writes log entries on to channel by checking if stop signal is received or not. If stop signal is
 // received, it won't put log entries on channel else it puts entries on channel.
 func (logger *Logger) logEntry(level logWriter.Level, args ...interface{}) {
-	select {
-	case <-logger.stopCh:
-		return
-	default:
-		entry := logWriter.NewEntry(level, args)
-		logger.channel <- entry
-	}
+	entry := logWriter.NewEntry(level, entryMessage(args))
+	logger.annotate(&entry, entryCallerSkip)
+	logger.send(entry)
 }
 
 //This method is similar to logEntry method but takes format as an argument as well.
 func (logger *Logger) logFormattedEntry(level logWriter.Level, format string, args ...interface{}) {
+	entry := logWriter.NewFormattedEntry(level, format, entryMessage(args))
+	logger.annotate(&entry, entryCallerSkip)
+	logger.send(entry)
+}
+
+//Util method that returns the message of an entry logged with args: a single argument as it is, so that logging
+// a plain string allocates no slice, and a copy of args otherwise, so that the caller's variadic slice does not
+// escape to the heap.
+func entryMessage(args []interface{}) interface{} {
+	if len(args) == 1 {
+		if _, ok := args[0].([]interface{}); !ok {
+			return args[0]
+		}
+	}
+	return append([]interface{}(nil), args...)
+}
+
+//This method returns the time of the logger's clock, see WithClock, or of the system for loggers without one.
+func (logger *Logger) now() time.Time {
+	if clock := logger.workerOptions.Clock; clock != nil {
+		return clock.Now()
+	}
+	return time.Now()
+}
+
+//This method records on the entry the call site skip frames above runtime.Callers, plus the logger's own caller
+// skip, and the stack from there if the logger records stacks at the entry's level, see WithStackTrace. Entries
+// of a logger created with WithClock are stamped with the time of its clock.
+func (logger *Logger) annotate(entry *logWriter.Entry, skip int) {
+	if clock := logger.workerOptions.Clock; clock != nil {
+		entry.SetTime(clock.Now())
+	}
+	if logger.stackDepth > 0 && logger.stackLevel.Enables(entry.Level()) {
+		pcs := make([]uintptr, logger.stackDepth)
+		if n := runtime.Callers(skip+logger.callerSkip, pcs); n > 0 {
+			entry.SetCaller(pcs[0])
+			entry.SetStack(pcs[:n])
+		}
+		return
+	}
+	var pcs [1]uintptr
+	if runtime.Callers(skip+logger.callerSkip, pcs[:]) > 0 {
+		entry.SetCaller(pcs[0])
+	}
+}
+
+// AddCallerSkip returns a logger like this one that skips n more stack frames to find the call site written
+// as file:line. Helpers that wrap the logging methods use it so that their callers are named rather than the
+// helper itself:
+//
+//	var log = myLogger.AddCallerSkip(1)
+//
+//	func logRequest(r *http.Request) { log.Info(r.Method, r.URL) }
+func (logger *Logger) AddCallerSkip(n int) *Logger {
+	derived := *logger
+	derived.callerSkip += n
+	return &derived
+}
+
+//This method tags the entry with the logger's destination and fields and, unless sampling leaves it out, numbers
+// it and puts it on the channel, after the summaries of sampled messages whose interval ended. Error, Fatal and
+// Panic entries are preceded by the entries kept for WithTraceOnError.
+func (logger *Logger) send(entry logWriter.Entry) {
+	entry.SetDestination(logger.destinationFor(entry.Level()))
+	entry.SetFields(logger.fields)
+	entry.SetLeadingKeys(logger.leading)
+	if logger.ring != nil && logWriter.ErrorLevel.Enables(entry.Level()) {
+		logger.enqueueAll(logger.ring.drain())
+	}
+	if sampler := logger.currentSampler(); sampler != nil {
+		logged, summaries := sampler.sample(&entry, logger.now())
+		logger.enqueueAll(summaries)
+		if !logged {
+			return
+		}
+	}
+	logger.enqueueNumbered(entry)
+}
+
+//This method numbers the entry and puts it on the channel, and reports whether it did. Entries logged after the
+// logger was closed or discarded by the overflow policy are counted as dropped.
+func (logger *Logger) enqueueNumbered(entry logWriter.Entry) bool {
+	entry.SetSequence(atomic.AddUint64(&logger.sequence, 1))
+	if !logger.enqueue(entry) {
+		atomic.AddUint64(&logger.dropped, 1)
+		return false
+	}
+	atomic.AddUint64(&logger.enqueued, 1)
+	count(&logger.levelCounts, entry.Level())
+	return true
+}
+
+//This method puts the entry on the channel unless the logger is closed or the overflow policy discards it, and
+// reports whether it did. Flush requests always wait for room. The send lock guarantees that CloseLogger cannot
+// close the logger between the check and the send, which would leave the entry on the channel after the worker's
+// final drain.
+func (logger *Logger) enqueue(entry logWriter.Entry) bool {
+	channel, queue := logger.queueFor(entry)
+	return logger.enqueueTo(entry, channel, queue)
+}
+
+//This method puts the entry on the given channel, or on queue if it is not nil, like enqueue.
+func (logger *Logger) enqueueTo(entry logWriter.Entry, channel chan logWriter.Entry, queue *logWriter.Ring) bool {
+	logger.sendLock.RLock()
+	defer logger.sendLock.RUnlock()
 	select {
 	case <-logger.stopCh:
-		return
+		return false
 	default:
-		entry := logWriter.NewFormattedEntry(logWriter.DebugLevel, format, args)
-		logger.channel <- entry
+		if logger.synchronous {
+			logger.handleLock.Lock()
+			logger.worker.HandleNow(entry)
+			logger.handleLock.Unlock()
+			return true
+		}
+		if entry.IsFlush() {
+			if queue != nil {
+				queue.Put(entry)
+			} else {
+				channel <- entry
+			}
+			return true
+		}
+		return logger.put(entry, channel, queue)
+	}
+}
+
+// Trace logs a message at level Trace on the standard logger. This takes variadic interface type
+// arguments, checks if the event is loggable and writes it to the channel.
+// If not loggable, method simply returns.
+func (logger *Logger) Trace(args ...interface{}) {
+	if TraceEnabled && logger.isLoggable(logWriter.TraceLevel) {
+		logger.logEntry(logWriter.TraceLevel, args...)
+	} else if TraceEnabled && logger.ring != nil {
+		logger.remember(logWriter.NewEntry(logWriter.TraceLevel, entryMessage(args)))
 	}
 }
 
@@ -135,8 +453,10 @@ func (logger *Logger) logFormattedEntry(level logWriter.Level, format string, ar
 // arguments, checks if the event is loggable and writes it to the channel.
 // If not loggable, method simply returns.
 func (logger *Logger) Debug(args ...interface{}) {
-	if logger.isLoggable(logWriter.DebugLevel) {
-		logger.logEntry(logWriter.DebugLevel, args)
+	if DebugEnabled && logger.isLoggable(logWriter.DebugLevel) {
+		logger.logEntry(logWriter.DebugLevel, args...)
+	} else if DebugEnabled && logger.ring != nil {
+		logger.remember(logWriter.NewEntry(logWriter.DebugLevel, entryMessage(args)))
 	}
 }
 
@@ -144,8 +464,8 @@ func (logger *Logger) Debug(args ...interface{}) {
 // arguments, checks if the event is loggable and writes it to the channel.
 // If not loggable, method simply returns.
 func (logger *Logger) Info(args ...interface{}) {
-	if logger.isLoggable(logWriter.InfoLevel) {
-		logger.logEntry(logWriter.InfoLevel, args)
+	if InfoEnabled && logger.isLoggable(logWriter.InfoLevel) {
+		logger.logEntry(logWriter.InfoLevel, args...)
 	}
 }
 
@@ -153,8 +473,8 @@ func (logger *Logger) Info(args ...interface{}) {
 // arguments, checks if the event is loggable and writes it to the channel.
 // If not loggable, method simply returns.
 func (logger *Logger) Warn(args ...interface{}) {
-	if logger.isLoggable(logWriter.WarnLevel) {
-		logger.logEntry(logWriter.WarnLevel, args)
+	if WarnEnabled && logger.isLoggable(logWriter.WarnLevel) {
+		logger.logEntry(logWriter.WarnLevel, args...)
 	}
 }
 
@@ -163,7 +483,18 @@ func (logger *Logger) Warn(args ...interface{}) {
 // If not loggable, method simply returns.
 func (logger *Logger) Error(args ...interface{}) {
 	if logger.isLoggable(logWriter.ErrorLevel) {
-		logger.logEntry(logWriter.ErrorLevel, args)
+		logger.logEntry(logWriter.ErrorLevel, args...)
+	}
+}
+
+// Tracef logs a message at level Trace on the standard logger. This takes format and variadic interface
+// type arguments, checks if the event is loggable and writes it to the channel.
+// If not loggable, method simply returns.
+func (logger *Logger) Tracef(format string, args ...interface{}) {
+	if TraceEnabled && logger.isLoggable(logWriter.TraceLevel) {
+		logger.logFormattedEntry(logWriter.TraceLevel, format, args...)
+	} else if TraceEnabled && logger.ring != nil {
+		logger.remember(logWriter.NewFormattedEntry(logWriter.TraceLevel, format, entryMessage(args)))
 	}
 }
 
@@ -171,8 +502,10 @@ func (logger *Logger) Error(args ...interface{}) {
 // type arguments, checks if the event is loggable and writes it to the channel.
 // If not loggable, method simply returns.
 func (logger *Logger) Debugf(format string, args ...interface{}) {
-	if logger.isLoggable(logWriter.DebugLevel) {
-		logger.logFormattedEntry(logWriter.DebugLevel, format, args)
+	if DebugEnabled && logger.isLoggable(logWriter.DebugLevel) {
+		logger.logFormattedEntry(logWriter.DebugLevel, format, args...)
+	} else if DebugEnabled && logger.ring != nil {
+		logger.remember(logWriter.NewFormattedEntry(logWriter.DebugLevel, format, entryMessage(args)))
 	}
 }
 
@@ -180,8 +513,8 @@ func (logger *Logger) Debugf(format string, args ...interface{}) {
 // type arguments, checks if the event is loggable and writes it to the channel.
 // If not loggable, method simply returns.
 func (logger *Logger) Infof(format string, args ...interface{}) {
-	if logger.isLoggable(logWriter.InfoLevel) {
-		logger.logFormattedEntry(logWriter.InfoLevel, format, args)
+	if InfoEnabled && logger.isLoggable(logWriter.InfoLevel) {
+		logger.logFormattedEntry(logWriter.InfoLevel, format, args...)
 	}
 }
 
@@ -189,8 +522,8 @@ func (logger *Logger) Infof(format string, args ...interface{}) {
 // type arguments, checks if the event is loggable and writes it to the channel.
 // If not loggable, method simply returns.
 func (logger *Logger) Warnf(format string, args ...interface{}) {
-	if logger.isLoggable(logWriter.WarnLevel) {
-		logger.logFormattedEntry(logWriter.WarnLevel, format, args)
+	if WarnEnabled && logger.isLoggable(logWriter.WarnLevel) {
+		logger.logFormattedEntry(logWriter.WarnLevel, format, args...)
 	}
 }
 
@@ -199,7 +532,21 @@ func (logger *Logger) Warnf(format string, args ...interface{}) {
 // If not loggable, method simply returns.
 func (logger *Logger) Errorf(format string, args ...interface{}) {
 	if logger.isLoggable(logWriter.ErrorLevel) {
-		logger.logFormattedEntry(logWriter.ErrorLevel, format, args)
+		logger.logFormattedEntry(logWriter.ErrorLevel, format, args...)
+	}
+}
+
+// Tracefunc logs a message at level Trace on the standard logger. This takes variadic function
+// type arguments(that return string values). It checks if the event is loggable then,
+// executes the functions and creates entry from variadic interface type values and writes
+// entry to the channel. If not loggable, method simply returns.
+func (logger *Logger) Tracefunc(args ...utils.FunctionArg) {
+	if TraceEnabled && logger.isLoggable(logWriter.TraceLevel) {
+		var loggerArgs = make([]interface{}, 0, 50)
+		for _, argument := range args {
+			loggerArgs = append(loggerArgs, argument())
+		}
+		logger.logEntry(logWriter.TraceLevel, loggerArgs...)
 	}
 }
 
@@ -208,12 +555,12 @@ func (logger *Logger) Errorf(format string, args ...interface{}) {
 // executes the functions and creates entry from variadic interface type values and writes
 // entry to the channel. If not loggable, method simply returns.
 func (logger *Logger) Debugfunc(args ...utils.FunctionArg) {
-	if logger.isLoggable(logWriter.DebugLevel) {
+	if DebugEnabled && logger.isLoggable(logWriter.DebugLevel) {
 		var loggerArgs = make([]interface{}, 0, 50)
 		for _, argument := range args {
 			loggerArgs = append(loggerArgs, argument())
 		}
-		logger.logEntry(logWriter.DebugLevel, loggerArgs)
+		logger.logEntry(logWriter.DebugLevel, loggerArgs...)
 	}
 }
 
@@ -222,12 +569,12 @@ func (logger *Logger) Debugfunc(args ...utils.FunctionArg) {
 // executes the functions and creates entry from variadic interface type values and writes
 // entry to the channel. If not loggable, method simply returns.
 func (logger *Logger) Infofunc(args ...utils.FunctionArg) {
-	if logger.isLoggable(logWriter.InfoLevel) {
+	if InfoEnabled && logger.isLoggable(logWriter.InfoLevel) {
 		var loggerArgs = make([]interface{}, 0, 50)
 		for _, argument := range args {
 			loggerArgs = append(loggerArgs, argument())
 		}
-		logger.logEntry(logWriter.InfoLevel, loggerArgs)
+		logger.logEntry(logWriter.InfoLevel, loggerArgs...)
 	}
 }
 
@@ -236,12 +583,12 @@ func (logger *Logger) Infofunc(args ...utils.FunctionArg) {
 // executes the functions and creates entry from variadic interface type values and writes
 // entry to the channel. If not loggable, method simply returns.
 func (logger *Logger) Warnfunc(args ...utils.FunctionArg) {
-	if logger.isLoggable(logWriter.WarnLevel) {
+	if WarnEnabled && logger.isLoggable(logWriter.WarnLevel) {
 		var loggerArgs = make([]interface{}, 0, 50)
 		for _, argument := range args {
 			loggerArgs = append(loggerArgs, argument())
 		}
-		logger.logEntry(logWriter.WarnLevel, loggerArgs)
+		logger.logEntry(logWriter.WarnLevel, loggerArgs...)
 	}
 }
 
@@ -255,6 +602,124 @@ func (logger *Logger) Errorfunc(args ...utils.FunctionArg) {
 		for _, argument := range args {
 			loggerArgs = append(loggerArgs, argument())
 		}
-		logger.logEntry(logWriter.ErrorLevel, loggerArgs)
+		logger.logEntry(logWriter.ErrorLevel, loggerArgs...)
+	}
+}
+
+// Log logs a message at the given level, built-in or registered with logWriter.RegisterLevel, on the standard
+// logger. This takes variadic interface type arguments, checks if the event is loggable and writes it to the
+// channel. If not loggable, method simply returns. Entries at FatalLevel and PanicLevel are only logged; use
+// Fatal and Panic to exit or panic.
+func (logger *Logger) Log(level logWriter.Level, args ...interface{}) {
+	if MaxLevel.Enables(level) && logger.isLoggable(level) {
+		logger.logEntry(level, args...)
+	}
+}
+
+// Logf logs a message at the given level like Log, taking a format.
+func (logger *Logger) Logf(level logWriter.Level, format string, args ...interface{}) {
+	if MaxLevel.Enables(level) && logger.isLoggable(level) {
+		logger.logFormattedEntry(level, format, args...)
+	}
+}
+
+// Fatal logs a message at level Fatal on the standard logger, closes the logger so that the entry and all
+// earlier ones reach the file and the sinks, and exits the process with status 1. It exits even if the
+// message is not loggable.
+func (logger *Logger) Fatal(args ...interface{}) {
+	if logger.isLoggable(logWriter.FatalLevel) {
+		logger.logEntry(logWriter.FatalLevel, args...)
+	}
+	logger.CloseLogger()
+	os.Exit(1)
+}
+
+// Fatalf logs a message at level Fatal on the standard logger like Fatal, taking a format.
+func (logger *Logger) Fatalf(format string, args ...interface{}) {
+	if logger.isLoggable(logWriter.FatalLevel) {
+		logger.logFormattedEntry(logWriter.FatalLevel, format, args...)
+	}
+	logger.CloseLogger()
+	os.Exit(1)
+}
+
+// Panic logs a message at level Panic on the standard logger, flushes the entry and all earlier ones to the
+// file and the sinks, and panics with the message. It panics even if the message is not loggable. The logger
+// stays open, so a recovering program can keep logging.
+func (logger *Logger) Panic(args ...interface{}) {
+	entry := logWriter.NewEntry(logWriter.PanicLevel, args)
+	logger.annotate(&entry, entryCallerSkip-1)
+	if logger.isLoggable(logWriter.PanicLevel) {
+		logger.send(entry)
+		logger.Flush()
+	}
+	panic(entry.Message())
+}
+
+// Panicf logs a message at level Panic on the standard logger like Panic, taking a format.
+func (logger *Logger) Panicf(format string, args ...interface{}) {
+	entry := logWriter.NewFormattedEntry(logWriter.PanicLevel, format, args)
+	logger.annotate(&entry, entryCallerSkip-1)
+	if logger.isLoggable(logWriter.PanicLevel) {
+		logger.send(entry)
+		logger.Flush()
+	}
+	panic(entry.Message())
+}
+
+// Flush writes everything logged so far to the file, without waiting for the buffer to fill up or the flush
+// interval to pass, and returns the first write error. Entries still on the channel are written first, since
+// the flush request queues up behind them; destinations and sinks are flushed too. It returns nil at once if
+// the logger is closed, because closing flushed everything already.
+func (logger *Logger) Flush() error {
+	var err error
+	for _, s := range logger.shards { //first, so that the shards sharing the log file hand over their entries
+		done := make(chan error, 1)
+		if !logger.enqueueTo(logWriter.NewFlushEntry(done), s.channel, s.queue) {
+			return nil
+		}
+		if flushErr := <-done; err == nil {
+			err = flushErr
+		}
+	}
+	done := make(chan error, 1)
+	if !logger.enqueue(logWriter.NewFlushEntry(done)) {
+		return nil
+	}
+	if flushErr := <-done; err == nil {
+		err = flushErr
+	}
+	return err
+}
+
+// Sync flushes like Flush and then commits the log file and the destination files to stable storage with
+// fsync, so that the entries survive a crash of the machine. It returns the first error.
+func (logger *Logger) Sync() error {
+	err := logger.Flush()
+	logger.sendLock.RLock() //keeps CloseLogger from closing the files while they are synced
+	defer logger.sendLock.RUnlock()
+	select {
+	case <-logger.stopCh:
+		return err
+	default:
+	}
+	if syncErr := logger.worker.File().Sync(); err == nil {
+		err = syncErr
+	}
+	for _, s := range logger.shards {
+		if s.file == nil {
+			continue
+		}
+		if syncErr := s.worker.File().Sync(); err == nil {
+			err = syncErr
+		}
+	}
+	logger.destLock.Lock()
+	defer logger.destLock.Unlock()
+	for _, dest := range logger.destinations {
+		if syncErr := dest.route.File().Sync(); err == nil {
+			err = syncErr
+		}
 	}
+	return err
 }
@@ -0,0 +1,10 @@
+//go:build !loglevel_debug && !loglevel_error && !loglevel_warn && !loglevel_info
+
+package logger
+
+import "github.com/shyamgrover/go-lite-logger/logWriter"
+
+// MaxLevel is the most verbose level compiled into the binary. Build with one of the tags loglevel_debug,
+// loglevel_info, loglevel_warn or loglevel_error to compile the more verbose logging methods down to no-ops.
+// Fatal and Panic calls are never stripped.
+const MaxLevel = logWriter.TraceLevel
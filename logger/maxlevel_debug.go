@@ -0,0 +1,8 @@
+//go:build loglevel_debug && !loglevel_info && !loglevel_warn && !loglevel_error
+
+package logger
+
+import "github.com/shyamgrover/go-lite-logger/logWriter"
+
+// MaxLevel is the most verbose level compiled into the binary. The loglevel_debug build tag strips Trace calls.
+const MaxLevel = logWriter.DebugLevel
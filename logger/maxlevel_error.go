@@ -0,0 +1,9 @@
+//go:build loglevel_error
+
+package logger
+
+import "github.com/shyamgrover/go-lite-logger/logWriter"
+
+// MaxLevel is the most verbose level compiled into the binary. The loglevel_error build tag strips Trace,
+// Debug, Info and Warn calls.
+const MaxLevel = logWriter.ErrorLevel
@@ -0,0 +1,9 @@
+//go:build loglevel_info && !loglevel_warn && !loglevel_error
+
+package logger
+
+import "github.com/shyamgrover/go-lite-logger/logWriter"
+
+// MaxLevel is the most verbose level compiled into the binary. The loglevel_info build tag strips Trace and
+// Debug calls.
+const MaxLevel = logWriter.InfoLevel
@@ -0,0 +1,9 @@
+//go:build loglevel_warn && !loglevel_error
+
+package logger
+
+import "github.com/shyamgrover/go-lite-logger/logWriter"
+
+// MaxLevel is the most verbose level compiled into the binary. The loglevel_warn build tag strips Trace, Debug
+// and Info calls.
+const MaxLevel = logWriter.WarnLevel
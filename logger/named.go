@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"sync/atomic"
+)
+
+//module is a named part of a program whose level can be set independently of the logger level.
+type module struct {
+	level  uint32  //level set with SetLevel plus one, 0 if none was set; first for atomic access
+	name   string  //full name, e.g. "http.client"
+	parent *module //module Named was called on, nil for top-level modules
+}
+
+//This method returns the level in effect for the module: its own, the nearest ancestor's or the given logger
+// level.
+func (m *module) effectiveLevel(loggerLevel logWriter.Level) logWriter.Level {
+	for ; m != nil; m = m.parent {
+		if level := atomic.LoadUint32(&m.level); level > 0 {
+			return logWriter.Level(level - 1)
+		}
+	}
+	return loggerLevel
+}
+
+// Named returns a logger for a module of the program, e.g. "http" or "db", that adds the module name as the
+// "module" field to every entry. SetLevel and GetLevel on it act on the module's own level, so that one module
+// can log at Debug while the rest logs at Info:
+//
+//	myLogger.Named("db").SetLevel(logWriter.DebugLevel)
+//
+// Until its level is set a module uses the level of the module it was named from, and top-level modules use
+// the logger level. Named on a named logger nests the names, e.g. "http.client". Loggers named alike share the
+// module and its level.
+func (logger *Logger) Named(name string) *Logger {
+	if logger.module != nil {
+		name = logger.module.name + "." + name
+	}
+	logger.moduleLock.Lock()
+	m, ok := logger.modules[name]
+	if !ok {
+		m = &module{name: name, parent: logger.module}
+		if logger.modules == nil {
+			logger.modules = make(map[string]*module)
+		}
+		logger.modules[name] = m
+	}
+	logger.moduleLock.Unlock()
+
+	derived := logger.WithField("module", name)
+	derived.module = m
+	return derived
+}
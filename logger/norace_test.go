@@ -0,0 +1,6 @@
+//go:build !race
+
+package logger_test
+
+//whether the tests run with the race detector, which makes allocation counts meaningless.
+const raceEnabled = false
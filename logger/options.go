@@ -0,0 +1,549 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/utils"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+//frames of the stacks recorded by WithStackTrace unless it is given a depth.
+const defaultStackDepth = 32
+
+//number of entries the channel holds unless WithChannelSize says otherwise.
+const defaultChannelSize = 2048
+
+// Option configures a logger created with New.
+type Option func(*options)
+
+//options collects the settings given to New.
+type options struct {
+	level         logWriter.Level              //logger level
+	file          string                       //log file path
+	dir           string                       //directory the log file path is relative to
+	worker        logWriter.WorkerOptions      //buffer size, flush interval, formatter and rotation
+	channelSize   int                          //capacity of the channel between the logging calls and the worker
+	overflow      OverflowPolicy               //what logging calls do when the channel is full
+	ringQueue     bool                         //hand entries to the worker through a logWriter.Ring instead of a channel
+	synchronous   bool                         //write entries on the logging goroutine, see WithSynchronous
+	shards        int                          //workers the entries are spread over, see WithShards
+	shardBy       ShardStrategy                //which of the workers handles an entry
+	shardFiles    bool                         //give every worker a file of its own
+	errorCallback utils.ErrorFunction          //called when writing to the log file fails
+	selfCheck     bool                         //run SelfCheck before New returns
+	retain        int                          //RetainOnFailure cap in bytes
+	fallbackFile  string                       //path of the fallback file set with WithFallbackFile
+	callerSkip    int                          //extra stack frames skipped to find the caller
+	stackLevel    logWriter.Level              //least severe level whose entries get a stack
+	stackDepth    int                          //frames of the stacks recorded, 0 to record none
+	processFields bool                         //add app, host and pid fields to every entry
+	app           string                       //application name given to WithProcessFields
+	sinks         []namedSink                  //sinks added with WithSink
+	extractors    []ContextExtractor           //extractors added with WithContextExtractor
+	hooks         []logWriter.Hook             //hooks added with WithHook
+	permissions   permissions                  //modes and checks of the log files
+	sampling      map[logWriter.Level]Sampling //policies given to WithSampling
+	traceRing     int                          //filtered out entries kept for WithTraceOnError
+	levelFiles    []levelFile                  //files given to WithLevelFile
+	stderrLevel   *logWriter.Level             //least severe level also written to stderr, nil to write none
+	deliveries    []logWriter.DeliveryHook     //delivery hooks added with WithDeliveryHook
+	environment   bool                         //apply the LOGGER_* variables, see WithEnvironment
+}
+
+//namedSink is a sink given to WithSink.
+type namedSink struct {
+	name string
+	sink logWriter.EntrySink
+}
+
+// WithLevel sets the logger level. The default is InfoLevel.
+func WithLevel(level logWriter.Level) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithFile sets the path of the log file. It is required.
+func WithFile(path string) Option {
+	return func(o *options) {
+		o.file = path
+	}
+}
+
+// WithDir sets the directory of the log file. A relative file path is taken relative to it and the directory is
+// created if it does not exist.
+func WithDir(dir string) Option {
+	return func(o *options) {
+		o.dir = dir
+	}
+}
+
+// WithBufferSize sets the size in bytes at which buffered entries are flushed to the file. The default is 32 KiB.
+func WithBufferSize(size int) Option {
+	return func(o *options) {
+		o.worker.BufferSize = size
+	}
+}
+
+// WithVectoredWrites gathers up to n full buffers and writes them to the file with one vectored write, a writev
+// call on Linux and consecutive writes elsewhere, which saves system calls under heavy logging. Flush, the
+// flush timer and closing the logger write the gathered buffers at once, so they delay nothing but the writes
+// of full buffers. Values below 2, the default, write every full buffer at once; so do workers encrypting their
+// files.
+func WithVectoredWrites(n int) Option {
+	return func(o *options) {
+		o.worker.VectoredWrites = n
+	}
+}
+
+// WithChannelSize sets how many entries the channel between the logging calls and the worker holds. Logging
+// calls block while it is full, so a larger channel absorbs longer bursts at the cost of memory, roughly 100
+// bytes per slot plus the entries' arguments. Values below 1 keep the default of 2048.
+func WithChannelSize(size int) Option {
+	return func(o *options) {
+		o.channelSize = size
+	}
+}
+
+// WithRingQueue hands entries to the worker through a lock-free ring of the channel size, see logWriter.Ring,
+// instead of a channel. Services logging from many goroutines at a high rate spend less time per entry and
+// contend less; the worker polls the ring and waits on it only when it is empty. The ring supports the Block and
+// DropNewest overflow policies; New fails with DropOldest, which would need a second consumer.
+func WithRingQueue() Option {
+	return func(o *options) {
+		o.ringQueue = true
+	}
+}
+
+// WithSynchronous makes the logging calls write their entries to the file themselves, one at a time under a lock,
+// instead of handing them to the worker through the channel. Every entry has reached the file when the call
+// returns, so a short-lived command loses nothing when it exits without closing the logger, at the cost of a
+// write per entry. Hooks, sinks, destinations and the other options work as usual; the channel size and the
+// overflow policy do not apply, and New fails if WithRingQueue or WithShards is given too. Hooks and error
+// handlers run on the logging goroutine and must not log through the same logger.
+func WithSynchronous() Option {
+	return func(o *options) {
+		o.synchronous = true
+	}
+}
+
+// WithFlushInterval sets how often buffered entries are flushed to the file when the buffer does not fill up.
+// The default is 10 seconds.
+func WithFlushInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.worker.FlushInterval = interval
+	}
+}
+
+// WithRetry makes a failed write of the buffer be retried up to attempts times before the failure is reported,
+// waiting backoff before the first retry and twice as long before every further one, e.g. 100ms, 200ms, 400ms.
+// Only the part of the buffer that did not reach the file is written again. The worker waits while it retries,
+// so logging calls may block or drop entries meanwhile, see WithOverflowPolicy. A backoff of zero selects 100ms.
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.worker.RetryAttempts = attempts
+		o.worker.RetryBackoff = backoff
+	}
+}
+
+// WithFallback sends the buffer to writer, e.g. os.Stderr, instead of losing it once writing to the log file has
+// failed after times flushes in a row, e.g. because the disk is full or the file's permissions changed. The log file is
+// still tried first at every flush and failures are still reported, so logging returns to the file when it
+// recovers. Destinations share the fallback, so writer must be safe for concurrent writes, as *os.File is.
+// A times below 1 selects 1.
+func WithFallback(writer io.Writer, times int) Option {
+	return func(o *options) {
+		o.worker.Fallback = writer
+		o.worker.FallbackAfter = times
+	}
+}
+
+// WithFallbackFile is WithFallback with a secondary file, e.g. on another disk, opened by New for appending
+// and closed by CloseLogger.
+func WithFallbackFile(path string, times int) Option {
+	return func(o *options) {
+		o.fallbackFile = path
+		o.worker.FallbackAfter = times
+	}
+}
+
+// WithDiskFullPolicy sets what happens when writing fails because the disk is full: logWriter.DiskFullPause keeps
+// the buffered entries and drops new ones until the disk has room again, logWriter.DiskFullFallback switches to
+// the fallback at once, see WithFallback, and logWriter.DiskFullPurge removes the oldest rotated log files to make
+// room. Entries dropped meanwhile are counted in Stats. The default, logWriter.DiskFullDiscard, handles a full
+// disk like any other write failure.
+func WithDiskFullPolicy(policy logWriter.DiskFullPolicy) Option {
+	return func(o *options) {
+		o.worker.DiskFull = policy
+	}
+}
+
+// WithSyncPolicy sets when the log file is committed to stable storage with fsync, so that entries survive a power
+// failure: logWriter.SyncEveryFlush after every flush, logWriter.SyncOnError right after Error, Fatal and Panic
+// entries and logWriter.SyncInterval periodically, see WithSyncInterval. The default, logWriter.SyncNever, leaves
+// it to the operating system; Sync commits the files on demand.
+func WithSyncPolicy(policy logWriter.SyncPolicy) Option {
+	return func(o *options) {
+		o.worker.Sync = policy
+	}
+}
+
+// WithSyncInterval commits the log file to stable storage with fsync at most every interval, after flushes and on
+// the flush timer, and selects logWriter.SyncInterval.
+func WithSyncInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.worker.Sync = logWriter.SyncInterval
+		o.worker.SyncInterval = interval
+	}
+}
+
+// WithTimeLayout sets the layout of the timestamps of the default text lines, a time.Format layout such as
+// time.RFC3339Nano or a Unix layout such as logWriter.UnixMillis. The default is "2006/01/02 15:04:05.000000".
+// Formatters given to WithFormatter have their own TimestampFormat.
+func WithTimeLayout(layout string) Option {
+	return func(o *options) {
+		o.worker.TimeLayout = layout
+	}
+}
+
+// WithUTC writes the timestamps of the default text lines in UTC instead of local time. Formatters given to
+// WithFormatter have their own UTC setting.
+func WithUTC() Option {
+	return func(o *options) {
+		o.worker.UTC = true
+	}
+}
+
+// WithSequence appends every entry's sequence number to the default text lines as seq=N. Numbers are assigned
+// in the order entries are logged and shared by the loggers derived from this one, so a gap shows a dropped
+// entry and the numbers put entries arriving out of order from several sinks back in order. Formatters given
+// to WithFormatter have a Sequence setting of their own.
+func WithSequence() Option {
+	return func(o *options) {
+		o.worker.Sequence = true
+	}
+}
+
+// WithRedaction makes the worker mask sensitive data of every entry before it reaches the log files or the
+// sinks, e.g. password fields and card numbers:
+//
+//	logger.WithRedaction(&logWriter.Redactor{Keys: []string{"password", "token"},
+//		Patterns: []*regexp.Regexp{logWriter.CreditCardPattern, logWriter.EmailPattern}})
+func WithRedaction(redactor *logWriter.Redactor) Option {
+	return func(o *options) {
+		o.worker.Redactor = redactor
+	}
+}
+
+// WithEncryption encrypts the log files with AES-GCM: every buffer the worker writes becomes a block encrypted
+// with the provider's current key, e.g. logWriter.StaticKey(key) for a 32 byte key. Use
+// logWriter.NewDecryptReader to read the files. Sinks receive the entries unencrypted.
+func WithEncryption(keys logWriter.KeyProvider) Option {
+	return func(o *options) {
+		o.worker.Encryption = keys
+	}
+}
+
+// WithAudit writes the log files in audit mode: every record ends with an HMAC-SHA256, keyed with key, of the
+// previous record's HMAC and the record itself, as a hmac=... pair or a "hmac" key of JSON records. Changing,
+// removing or reordering records breaks the chain, which logWriter.VerifyAudit detects; so do records lost to
+// failed writes or sent to a fallback.
+func WithAudit(key []byte) Option {
+	return func(o *options) {
+		o.worker.AuditKey = key
+	}
+}
+
+// WithFileLock makes the worker hold an advisory lock (flock) on the log file while it writes a buffer, so that
+// the entries of several processes writing to the same file never interleave mid-line. All processes must use
+// it, and only one of them should rotate the file. It is not supported on systems without flock, such as
+// Windows, Plan 9, Solaris, AIX and WebAssembly.
+func WithFileLock() Option {
+	return func(o *options) {
+		o.worker.FileLock = true
+	}
+}
+
+// WithDeduplication collapses identical consecutive entries: an entry with the same level, destination, message
+// and fields as the one before it, logged within window of the first of them, is not written. When the run ends,
+// the last duplicate is written with "(repeated N times)" appended, so a tight error loop costs two lines per
+// window instead of filling the disk.
+func WithDeduplication(window time.Duration) Option {
+	return func(o *options) {
+		o.worker.DedupWindow = window
+	}
+}
+
+// WithClock makes the logger take the time of its entries, flushes and failures from clock, which also drives
+// the timer based flush. Tests pass a clock they advance by hand, e.g. logtest.NewClock, to trigger the flush of
+// WithFlushInterval and to check timestamps without sleeping.
+func WithClock(clock logWriter.Clock) Option {
+	return func(o *options) {
+		o.worker.Clock = clock
+	}
+}
+
+// WithTraceOnError keeps the latest size Debug and Trace entries that the logger level filters out in memory and
+// writes them, with their original times, before the next Error, Fatal or Panic entry. Failures then come with
+// the context that led to them without running at Debug level all the time.
+func WithTraceOnError(size int) Option {
+	return func(o *options) {
+		o.traceRing = size
+	}
+}
+
+// WithFormatter sets how entries are rendered in the log file, e.g. logWriter.JSONFormatter{} for one JSON
+// object per line. By default entries are written as "[LEVEL]  date time file:line: message" lines.
+func WithFormatter(formatter logWriter.Formatter) Option {
+	return func(o *options) {
+		o.worker.Formatter = formatter
+	}
+}
+
+// WithMaxSize rotates the log file when writing would make it larger than maxBytes: the file is renamed with a
+// timestamp suffix, e.g. app.log.2020-05-01T10-00-00, and logging continues in a fresh file. Destination files
+// are rotated the same way. Zero, the default, never rotates.
+func WithMaxSize(maxBytes int64) Option {
+	return func(o *options) {
+		o.worker.MaxSize = maxBytes
+	}
+}
+
+// WithRotation rotates the log file on a schedule, logWriter.Hourly or logWriter.Daily. At the first flush of a
+// new period the file is renamed with a suffix naming the period it holds, e.g. app.log.2020-05-01 for Daily,
+// and logging continues in a fresh file. A file left over from an earlier period is rotated at the first flush.
+// It can be combined with WithMaxSize.
+func WithRotation(period logWriter.RotationPeriod) Option {
+	return func(o *options) {
+		o.worker.Rotation = period
+	}
+}
+
+// WithCompression gzips every rotated file in a background goroutine, e.g. app.log.2020-05-01 becomes
+// app.log.2020-05-01.gz. CloseLogger waits for running compressions to finish.
+func WithCompression() Option {
+	return func(o *options) {
+		o.worker.Compress = true
+	}
+}
+
+// WithSink adds a sink that receives every entry next to the log file, see AddSink.
+func WithSink(name string, sink logWriter.EntrySink) Option {
+	return func(o *options) {
+		o.sinks = append(o.sinks, namedSink{name: name, sink: sink})
+	}
+}
+
+// WithHook adds a hook run for every entry at its levels before the entry is written, see AddHook.
+func WithHook(hook logWriter.Hook) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, hook)
+	}
+}
+
+// WithDeliveryHook adds a hook told about every buffer written to the log files, see OnDelivery.
+func WithDeliveryHook(hook logWriter.DeliveryHook) Option {
+	return func(o *options) {
+		o.deliveries = append(o.deliveries, hook)
+	}
+}
+
+// WithContextExtractor makes WithContext attach the fields returned by extract, e.g. a user ID stored in the
+// context by an authentication middleware. Extractors run in the order they are given; fields of a later one
+// replace fields of an earlier one with the same key.
+func WithContextExtractor(extract ContextExtractor) Option {
+	return func(o *options) {
+		o.extractors = append(o.extractors, extract)
+	}
+}
+
+// WithErrorCallback sets the function called when writing to the log file fails.
+func WithErrorCallback(errorCallback utils.ErrorFunction) Option {
+	return func(o *options) {
+		o.errorCallback = errorCallback
+	}
+}
+
+// WithErrorHandler sets a function that is told what failed, next to the error callback: the error, the
+// operation, e.g. logWriter.OpWrite, and the lost entry when a single entry was lost. A missing log file can be
+// told apart from other write failures with errors.Is(err, logWriter.ErrFileMissing):
+//
+//	logger.WithErrorHandler(func(err error, op string, entry *logWriter.Entry) {
+//		if entry != nil {
+//			fmt.Fprintln(os.Stderr, "lost:", entry.Message())
+//		}
+//	})
+func WithErrorHandler(handler logWriter.ErrorHandler) Option {
+	return func(o *options) {
+		o.worker.ErrorHandler = handler
+	}
+}
+
+// WithCallerSkip makes the logger skip n more stack frames to find the call site written as file:line, for
+// programs that log through a wrapper of their own, see AddCallerSkip.
+func WithCallerSkip(n int) Option {
+	return func(o *options) {
+		o.callerSkip = n
+	}
+}
+
+// WithStackTrace records the stack of the call site, up to depth frames, for entries at level and more severe
+// levels, e.g. logWriter.ErrorLevel for Error, Fatal and Panic entries. The stack follows the message on
+// indented lines in text output and is written under a "stack" key by JSONFormatter. A depth below 1 selects
+// 32 frames.
+func WithStackTrace(level logWriter.Level, depth int) Option {
+	return func(o *options) {
+		if depth < 1 {
+			depth = defaultStackDepth
+		}
+		o.stackLevel = level
+		o.stackDepth = depth
+	}
+}
+
+// WithProcessFields adds fields naming the process to every entry, ahead of all other fields: app, the given
+// application or service name unless it is empty, host, the host name, and pid, the process ID. They tell
+// instances apart when several of them write to a shared aggregation pipeline.
+func WithProcessFields(app string) Option {
+	return func(o *options) {
+		o.processFields = true
+		o.app = app
+	}
+}
+
+// WithSelfCheck makes New run SelfCheck on the new logger and fail if it does not pass.
+func WithSelfCheck() Option {
+	return func(o *options) {
+		o.selfCheck = true
+	}
+}
+
+// WithRetainOnFailure keeps up to maxBytes of entries that failed to flush for the next flush, see
+// RetainOnFailure.
+func WithRetainOnFailure(maxBytes int) Option {
+	return func(o *options) {
+		o.retain = maxBytes
+	}
+}
+
+// New creates a logger configured by the given options. Only WithFile is required:
+//
+//	myLogger, err := logger.New(logger.WithFile("app.log"), logger.WithDir("logs"), logger.WithLevel(logWriter.DebugLevel))
+//
+// With WithEnvironment, the environment variables LOGGER_LEVEL, LOGGER_FILE, LOGGER_FORMAT and
+// LOGGER_FLUSH_INTERVAL override the options; New fails if one of them holds an invalid value.
+func New(opts ...Option) (*Logger, error) {
+	o := options{level: logWriter.InfoLevel, errorCallback: func() {}, channelSize: defaultChannelSize,
+		permissions: defaultPermissions}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := o.applyEnvironment(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	if len(o.file) == 0 {
+		return nil, fmt.Errorf("no log file given, use WithFile")
+	}
+	if o.channelSize < 1 {
+		o.channelSize = defaultChannelSize
+	}
+	if o.ringQueue && o.overflow == DropOldest {
+		return nil, fmt.Errorf("the %s overflow policy is not supported with WithRingQueue", DropOldest)
+	}
+	if o.synchronous && (o.ringQueue || o.shards > 1) {
+		return nil, fmt.Errorf("WithSynchronous cannot be combined with WithRingQueue or WithShards")
+	}
+	if o.errorCallback == nil {
+		o.errorCallback = func() {}
+	}
+	filePath := o.file
+	if len(o.dir) > 0 && !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(o.dir, filePath)
+	}
+
+	var fallback *os.File
+	if len(o.fallbackFile) > 0 {
+		var err error
+		if fallback, err = openLogFile(o.fallbackFile, o.permissions); err != nil {
+			return nil, err
+		}
+		o.worker.Fallback = fallback
+	}
+	file, err := openLogFile(filePath, o.permissions)
+	if err != nil {
+		if fallback != nil {
+			fallback.Close()
+		}
+		return nil, err
+	}
+	var shardFiles []*os.File
+	if o.shards > 1 && o.shardFiles {
+		if shardFiles, err = openShardFiles(filePath, o.shards, o.permissions); err != nil {
+			file.Close()
+			if fallback != nil {
+				fallback.Close()
+			}
+			return nil, err
+		}
+	}
+	myLogger := getInstance(o.level, filePath, file)
+	myLogger.fallbackFile = fallback
+	myLogger.callerSkip = o.callerSkip
+	if o.processFields {
+		myLogger = myLogger.With(processFields(o.app)...)
+	}
+	myLogger.init(file, shardFiles, o)
+	for _, hook := range o.hooks {
+		myLogger.AddHook(hook)
+	}
+	for _, hook := range o.deliveries {
+		myLogger.OnDelivery(hook)
+	}
+	if o.stderrLevel != nil {
+		sink := logWriter.NewLevelSink(logWriter.NewWriterSink(stderr{}, o.worker.Formatter), *o.stderrLevel)
+		o.sinks = append(o.sinks, namedSink{name: "stderr", sink: sink})
+	}
+	for _, s := range o.sinks {
+		if err = myLogger.AddSink(s.name, s.sink); err != nil {
+			myLogger.CloseLogger()
+			return nil, err
+		}
+	}
+	for _, f := range o.levelFiles {
+		path := f.path
+		if len(o.dir) > 0 && !filepath.IsAbs(path) {
+			path = filepath.Join(o.dir, path)
+		}
+		err = myLogger.AddDestination(f.path, filepath.Base(path), filepath.Dir(path)+string(filepath.Separator))
+		if err == nil {
+			err = myLogger.RouteLevels(f.path, f.levels...)
+		}
+		if err != nil {
+			myLogger.CloseLogger()
+			return nil, err
+		}
+	}
+	if o.selfCheck {
+		if err = myLogger.SelfCheck(); err != nil {
+			myLogger.CloseLogger()
+			return nil, err
+		}
+	}
+	return myLogger, nil
+}
+
+//Util method that returns the app, host and pid key/value pairs of WithProcessFields. Keys whose value is not
+// known are left out.
+func processFields(app string) []interface{} {
+	var pairs []interface{}
+	if len(app) > 0 {
+		pairs = append(pairs, "app", app)
+	}
+	if host, err := os.Hostname(); err == nil {
+		pairs = append(pairs, "host", host)
+	}
+	return append(pairs, "pid", os.Getpid())
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"strings"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what a logging call does when the channel to the worker is full, e.g. because the disk
+// is slow.
+type OverflowPolicy int
+
+const (
+	// Block makes logging calls wait until the worker has made room. No entry is lost, but a stalled disk stalls
+	// the callers. It is the default.
+	Block OverflowPolicy = iota
+	// DropNewest discards the entry being logged, so logging calls never wait.
+	DropNewest
+	// DropOldest evicts the oldest queued entry to make room for the one being logged, so logging calls never
+	// wait and the most recent entries are kept.
+	DropOldest
+)
+
+// String returns the name of the policy as used in config files: block, drop_newest or drop_oldest.
+func (policy OverflowPolicy) String() string {
+	switch policy {
+	case DropNewest:
+		return "drop_newest"
+	case DropOldest:
+		return "drop_oldest"
+	}
+	return "block"
+}
+
+// ParseOverflowPolicy returns the policy of the given name, see String. An empty name is Block.
+func ParseOverflowPolicy(name string) (OverflowPolicy, error) {
+	switch strings.ToLower(name) {
+	case "", "block":
+		return Block, nil
+	case "drop_newest":
+		return DropNewest, nil
+	case "drop_oldest":
+		return DropOldest, nil
+	}
+	return Block, fmt.Errorf("unknown overflow policy %q", name)
+}
+
+// WithOverflowPolicy sets what logging calls do when the channel to the worker is full. Entries discarded by
+// DropNewest or DropOldest are counted in the EntriesDropped of the close report. Flush always waits for room,
+// whatever the policy.
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return func(o *options) {
+		o.overflow = policy
+	}
+}
+
+//This method puts a log entry on the channel, or on queue, the ring of WithRingQueue, if it is not nil,
+// following the logger's overflow policy and reports whether it did. It must be called with sendLock held for reading.
+func (logger *Logger) put(entry logWriter.Entry, channel chan logWriter.Entry, queue *logWriter.Ring) bool {
+	if queue != nil {
+		if logger.overflow == DropNewest {
+			return queue.Offer(entry)
+		}
+		queue.Put(entry)
+		return true
+	}
+	switch logger.overflow {
+	case DropNewest:
+		select {
+		case channel <- entry:
+			return true
+		default:
+			return false
+		}
+	case DropOldest:
+		for {
+			select {
+			case channel <- entry:
+				return true
+			default:
+			}
+			logger.evictOldest(channel)
+		}
+	}
+	channel <- entry
+	return true
+}
+
+//This method takes the oldest entry off the full channel and counts it as dropped. A flush request is put back
+// instead, behind the entries queued after it, so that its caller still gets an answer.
+func (logger *Logger) evictOldest(channel chan logWriter.Entry) {
+	select {
+	case oldest := <-channel:
+		if oldest.IsFlush() {
+			channel <- oldest
+			return
+		}
+		atomic.AddUint64(&logger.dropped, 1)
+	default: //the worker emptied the channel in the meantime
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+//Util method that returns the user and group ids owning the file.
+func fileOwner(info os.FileInfo) (uid int, gid int, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(stat.Uid), int(stat.Gid), true
+}
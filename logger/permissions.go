@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"os"
+)
+
+//default mode of the directories created for log files.
+const defaultDirMode os.FileMode = 0755
+
+//permissions are the modes log files and their directories are created with and the checks an opened log file
+// must pass.
+type permissions struct {
+	fileMode os.FileMode //mode of created log files
+	dirMode  os.FileMode //mode of created directories
+	uid      int         //owner the log file must have, -1 for any
+	gid      int         //group the log file must have, -1 for any
+	private  bool        //refuse log files others can read
+}
+
+//permissions used unless options say otherwise.
+var defaultPermissions = permissions{fileMode: logWriter.DefaultFileMode, dirMode: defaultDirMode, uid: -1, gid: -1}
+
+// WithFileMode sets the mode the log files, rotated and compressed files and the files of destinations are
+// created with, 0644 by default. The mode is applied as given, whatever the umask; existing files keep theirs.
+func WithFileMode(mode os.FileMode) Option {
+	return func(o *options) {
+		o.permissions.fileMode = mode
+		o.worker.FileMode = mode
+	}
+}
+
+// WithDirMode sets the mode of the directories created for the log files, 0755 by default.
+func WithDirMode(mode os.FileMode) Option {
+	return func(o *options) {
+		o.permissions.dirMode = mode
+	}
+}
+
+// WithOwner makes New and AddDestination fail unless the log file is owned by the given user and group ids,
+// e.g. to catch a file left behind by a process that ran as root. Pass -1 to accept any user or group. The
+// check is skipped on systems without Unix ownership.
+func WithOwner(uid int, gid int) Option {
+	return func(o *options) {
+		o.permissions.uid = uid
+		o.permissions.gid = gid
+	}
+}
+
+// WithPrivateFiles makes New and AddDestination fail if the log file can be read by users other than its owner
+// and group, e.g. an existing file created with 0644 when the logs hold personal data.
+func WithPrivateFiles() Option {
+	return func(o *options) {
+		o.permissions.private = true
+	}
+}
+
+//This method checks the opened log file against the expected ownership and, for private files, its mode.
+func (perm permissions) check(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if perm.private && info.Mode().Perm()&0004 != 0 {
+		return fmt.Errorf("log file %s is world-readable (mode %v)", file.Name(), info.Mode().Perm())
+	}
+	if uid, gid, ok := fileOwner(info); ok {
+		if perm.uid >= 0 && uid != perm.uid {
+			return fmt.Errorf("log file %s is owned by user %d, not %d", file.Name(), uid, perm.uid)
+		}
+		if perm.gid >= 0 && gid != perm.gid {
+			return fmt.Errorf("log file %s is owned by group %d, not %d", file.Name(), gid, perm.gid)
+		}
+	}
+	return nil
+}
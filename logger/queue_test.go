@@ -0,0 +1,35 @@
+package logger_test
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"testing"
+)
+
+// BenchmarkQueue logs from GOMAXPROCS goroutines at once through the channel and through the ring of
+// WithRingQueue; compare them with -cpu to see how they scale:
+//
+//	go test -run - -bench Queue -cpu 1,4,8 ./logger
+func BenchmarkQueue(b *testing.B) {
+	queues := []struct {
+		name    string
+		options []logger.Option
+	}{
+		{"channel", nil},
+		{"ring", []logger.Option{logger.WithRingQueue()}},
+	}
+	for _, queue := range queues {
+		b.Run(queue.name, func(b *testing.B) {
+			myLogger := newTestLogger(b, queue.options...)
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					myLogger.Info("request handled")
+				}
+			})
+			if err := myLogger.Flush(); err != nil {
+				b.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+//onceKey identifies the messages of a level logged with one of the Once methods under a key.
+type onceKey struct {
+	level logWriter.Level //level of the messages
+	key   string          //key given by the caller
+}
+
+//This method reports whether key is used for the first time at level, remembering it if so.
+func (logger *Logger) firstTime(level logWriter.Level, key string) bool {
+	_, seen := logger.onceKeys.LoadOrStore(onceKey{level: level, key: key}, struct{}{})
+	return !seen
+}
+
+//This method reports whether the call site of the logging method calling it last logged at least interval ago by
+// the logger's clock, recording now as its last time if so. The call site is the caller of that method.
+func (logger *Logger) due(interval time.Duration) bool {
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	now := logger.now().UnixNano()
+	last := logger.everySite(pcs[0])
+	for {
+		previous := atomic.LoadInt64(last)
+		if previous != 0 && now-previous < int64(interval) {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(last, previous, now) {
+			return true
+		}
+	}
+}
+
+//This method returns the time of the last entry of the line of the call site at pc. Call sites in code inlined at
+// several places have a program counter for each, so they are resolved to their line once and share its time.
+func (logger *Logger) everySite(pc uintptr) *int64 {
+	if last, ok := logger.everySites.Load(pc); ok {
+		return last.(*int64)
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	line, _ := logger.everyLines.LoadOrStore(frame.File+":"+strconv.Itoa(frame.Line), new(int64))
+	logger.everySites.Store(pc, line)
+	return line.(*int64)
+}
+
+// DebugOnce logs a message at level Debug the first time it is called with key and ignores later calls with the
+// same key, e.g. for a deprecation notice in a hot path. Keys are remembered until the program exits, so they
+// should come from a small set.
+func (logger *Logger) DebugOnce(key string, args ...interface{}) {
+	if DebugEnabled && logger.isLoggable(logWriter.DebugLevel) && logger.firstTime(logWriter.DebugLevel, key) {
+		logger.logEntry(logWriter.DebugLevel, args...)
+	}
+}
+
+// InfoOnce logs a message at level Info the first time it is called with key and ignores later calls with the
+// same key, e.g. for a deprecation notice in a hot path. Keys are remembered until the program exits, so they
+// should come from a small set.
+func (logger *Logger) InfoOnce(key string, args ...interface{}) {
+	if InfoEnabled && logger.isLoggable(logWriter.InfoLevel) && logger.firstTime(logWriter.InfoLevel, key) {
+		logger.logEntry(logWriter.InfoLevel, args...)
+	}
+}
+
+// WarnOnce logs a message at level Warn the first time it is called with key and ignores later calls with the
+// same key, e.g. for a deprecation notice in a hot path. Keys are remembered until the program exits, so they
+// should come from a small set.
+func (logger *Logger) WarnOnce(key string, args ...interface{}) {
+	if WarnEnabled && logger.isLoggable(logWriter.WarnLevel) && logger.firstTime(logWriter.WarnLevel, key) {
+		logger.logEntry(logWriter.WarnLevel, args...)
+	}
+}
+
+// ErrorOnce logs a message at level Error the first time it is called with key and ignores later calls with
+// the same key, e.g. for a deprecation notice in a hot path. Keys are remembered until the program exits, so
+// they should come from a small set.
+func (logger *Logger) ErrorOnce(key string, args ...interface{}) {
+	if logger.isLoggable(logWriter.ErrorLevel) && logger.firstTime(logWriter.ErrorLevel, key) {
+		logger.logEntry(logWriter.ErrorLevel, args...)
+	}
+}
+
+// DebugEvery logs a message at level Debug unless the same call site logged one less than interval ago, for
+// hot loops where logging every iteration is too noisy.
+func (logger *Logger) DebugEvery(interval time.Duration, args ...interface{}) {
+	if DebugEnabled && logger.isLoggable(logWriter.DebugLevel) && logger.due(interval) {
+		logger.logEntry(logWriter.DebugLevel, args...)
+	}
+}
+
+// InfoEvery logs a message at level Info unless the same call site logged one less than interval ago, for hot
+// loops where logging every iteration is too noisy.
+func (logger *Logger) InfoEvery(interval time.Duration, args ...interface{}) {
+	if InfoEnabled && logger.isLoggable(logWriter.InfoLevel) && logger.due(interval) {
+		logger.logEntry(logWriter.InfoLevel, args...)
+	}
+}
+
+// WarnEvery logs a message at level Warn unless the same call site logged one less than interval ago, for hot
+// loops where logging every iteration is too noisy.
+func (logger *Logger) WarnEvery(interval time.Duration, args ...interface{}) {
+	if WarnEnabled && logger.isLoggable(logWriter.WarnLevel) && logger.due(interval) {
+		logger.logEntry(logWriter.WarnLevel, args...)
+	}
+}
+
+// ErrorEvery logs a message at level Error unless the same call site logged one less than interval ago, for
+// hot loops where logging every iteration is too noisy:
+//
+//	for {
+//		if err := poll(); err != nil {
+//			myLogger.ErrorEvery(time.Minute, "poll failed:", err)
+//		}
+//	}
+//
+// The Every methods are keyed by the line calling them, not by the message: messages with different arguments
+// from one line share the limit, and so do all callers of a helper that calls ErrorEvery, while two lines logging
+// the same message are limited separately. The limit is kept per logger, and children made with With or
+// WithFields share it with their parent. Time is taken from the clock given to WithClock, if any.
+func (logger *Logger) ErrorEvery(interval time.Duration, args ...interface{}) {
+	if logger.isLoggable(logWriter.ErrorLevel) && logger.due(interval) {
+		logger.logEntry(logWriter.ErrorLevel, args...)
+	}
+}
@@ -0,0 +1,28 @@
+package logger_test
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"github.com/shyamgrover/go-lite-logger/logtest"
+	"testing"
+	"time"
+)
+
+// TestEveryClock checks that the Every methods measure their interval with the logger's clock, so that a test
+// clock lets a call site log again without waiting, and that a line inlined at several places is one call site.
+func TestEveryClock(t *testing.T) {
+	clock := logtest.NewClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	sink := logtest.NewSink()
+	myLogger := newTestLogger(t, logger.WithClock(clock), logger.WithSink("test", sink))
+	//one line, called at 0s, 0s, 59s and 60s, through a helper inlined at every call
+	poll := func() { myLogger.ErrorEvery(time.Minute, "poll failed") }
+	poll()
+	poll()
+	clock.Advance(59 * time.Second)
+	poll()
+	clock.Advance(time.Second)
+	poll()
+	myLogger.CloseLogger()
+	if n := len(sink.Entries()); n != 2 {
+		t.Errorf("got %d entries, want 2: the first call and the one a minute later", n)
+	}
+}
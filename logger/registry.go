@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+//registry of loggers keyed by name.
+var loggerRegistry = struct {
+	lock    sync.RWMutex
+	loggers map[string]*Logger
+}{loggers: make(map[string]*Logger)}
+
+// Register creates a logger from config, with opts applied after the config's own options, and makes it
+// available under name, so that components can look it up with Get instead of being handed a pointer:
+//
+//	config, err := logger.LoadConfig("payments.json")
+//	...
+//	_, err = logger.Register("payments", config)
+//	...
+//	logger.Get("payments").Info("charge captured")
+//
+// It returns an error if the name is empty or already registered, or if the logger cannot be created.
+func Register(name string, config *Config, opts ...Option) (*Logger, error) {
+	configOpts, err := config.Options()
+	if err != nil {
+		return nil, err
+	}
+	loggerRegistry.lock.Lock()
+	defer loggerRegistry.lock.Unlock()
+	if err = checkRegistrable(name); err != nil {
+		return nil, err
+	}
+	l, err := New(append(configOpts, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	loggerRegistry.loggers[name] = l
+	return l, nil
+}
+
+// RegisterLogger makes a logger created elsewhere, e.g. with New, available under name. It returns an error if
+// the name is empty or already registered.
+func RegisterLogger(name string, l *Logger) error {
+	if l == nil {
+		return fmt.Errorf("logger %q is nil", name)
+	}
+	loggerRegistry.lock.Lock()
+	defer loggerRegistry.lock.Unlock()
+	if err := checkRegistrable(name); err != nil {
+		return err
+	}
+	loggerRegistry.loggers[name] = l
+	return nil
+}
+
+//Util method that checks that name can be registered. It must be called with the registry lock held.
+func checkRegistrable(name string) error {
+	if len(name) == 0 {
+		return fmt.Errorf("logger name must not be empty")
+	}
+	if _, exists := loggerRegistry.loggers[name]; exists {
+		return fmt.Errorf("logger %q is already registered", name)
+	}
+	return nil
+}
+
+// Get returns the logger registered under name, or nil if there is none.
+func Get(name string) *Logger {
+	loggerRegistry.lock.RLock()
+	defer loggerRegistry.lock.RUnlock()
+	return loggerRegistry.loggers[name]
+}
+
+// Registered returns the names of the registered loggers in sorted order.
+func Registered() []string {
+	loggerRegistry.lock.RLock()
+	defer loggerRegistry.lock.RUnlock()
+	names := make([]string, 0, len(loggerRegistry.loggers))
+	for name := range loggerRegistry.loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Unregister removes the logger registered under name and returns it, or nil if there is none. The logger is
+// not closed.
+func Unregister(name string) *Logger {
+	loggerRegistry.lock.Lock()
+	defer loggerRegistry.lock.Unlock()
+	l := loggerRegistry.loggers[name]
+	delete(loggerRegistry.loggers, name)
+	return l
+}
+
+// CloseRegistered unregisters and closes every registered logger, e.g. on program exit, and returns their
+// close reports keyed by name.
+func CloseRegistered() map[string]CloseReport {
+	loggerRegistry.lock.Lock()
+	loggers := loggerRegistry.loggers
+	loggerRegistry.loggers = make(map[string]*Logger)
+	loggerRegistry.lock.Unlock()
+
+	reports := make(map[string]CloseReport, len(loggers))
+	for name, l := range loggers {
+		reports[name] = l.CloseLogger()
+	}
+	return reports
+}
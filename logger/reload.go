@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+//how often WatchConfig looks at the config file unless it is given an interval.
+const defaultWatchInterval = 2 * time.Second
+
+// WatchConfig applies changes of the config file at path to the running logger until the returned function is
+// called or the logger is closed, so that verbosity can be tuned fleet-wide by pushing a config file. The file
+// is expected to be the one the logger was created from, e.g. with NewFromConfig; it is read once now and then
+// whenever its modification time or size changes, checked every interval, two seconds if interval is zero.
+// Polling needs no file system notifications and also notices files replaced through a symlink, as Kubernetes
+// does with ConfigMaps.
+//
+// The level, the sampling policies and the sinks are applied live, and a warning describes what changed, e.g.
+// "config app.yaml reloaded: level info -> debug, sink kafka added". Other keys take effect on the next start.
+// A file that does not load is reported to the error callback and the error handler with logWriter.OpReload and
+// the logger keeps its settings; so is a sink that cannot be created.
+func (logger *Logger) WatchConfig(path string, interval time.Duration) (stop func(), err error) {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	current, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	stopped := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				latest, err := os.Stat(path)
+				if err != nil || latest.ModTime().Equal(info.ModTime()) && latest.Size() == info.Size() {
+					continue
+				}
+				info = latest
+				next, err := LoadConfig(path)
+				if err == nil {
+					err = logger.reload(path, current, next)
+					current = next
+				}
+				if err != nil {
+					logger.errorCallback()
+					if handler := logger.workerOptions.ErrorHandler; handler != nil {
+						handler(err, logWriter.OpReload, nil)
+					}
+				}
+			case <-stopped:
+				return
+			case <-logger.stopCh:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(stopped) })
+	}, nil
+}
+
+//This method applies the differences between the previous and the next config that can be applied to a running
+// logger, the level, the sampling policies and the sinks, and logs them.
+func (logger *Logger) reload(path string, previous *Config, next *Config) error {
+	var changes []string
+	if !strings.EqualFold(previous.Level, next.Level) {
+		level := logWriter.InfoLevel
+		if len(next.Level) > 0 {
+			var err error
+			if level, err = logWriter.ParseLevel(next.Level); err != nil {
+				return err
+			}
+		}
+		changes = append(changes, fmt.Sprintf("level %s -> %s", logger.GetLevel(), level))
+		logger.SetLevel(level)
+	}
+	if !reflect.DeepEqual(previous.Sampling, next.Sampling) {
+		policies, err := next.samplingPolicies()
+		if err != nil {
+			return err
+		}
+		logger.setSampling(policies)
+		changes = append(changes, "sampling changed")
+	}
+	sinkChanges, err := logger.reloadSinks(previous, next)
+	changes = append(changes, sinkChanges...)
+
+	rest, nextRest := *previous, *next
+	rest.Level, rest.Sampling, rest.Sinks = "", nil, nil
+	nextRest.Level, nextRest.Sampling, nextRest.Sinks = "", nil, nil
+	if !reflect.DeepEqual(rest, nextRest) {
+		changes = append(changes, "other changes take effect on restart")
+	}
+	if len(changes) > 0 {
+		logger.announce("config %s reloaded: %s", path, strings.Join(changes, ", "))
+	}
+	return err
+}
+
+//This method removes the sinks of the previous config that the next one drops or changes and adds the sinks
+// the next config adds or changes. It returns the changes made and the first error.
+func (logger *Logger) reloadSinks(previous *Config, next *Config) ([]string, error) {
+	var changes []string
+	var firstErr error
+	for _, name := range sortedKeys(previous.Sinks) {
+		if settings, ok := next.Sinks[name]; !ok || !reflect.DeepEqual(settings, previous.Sinks[name]) {
+			if err := logger.RemoveSink(name); err != nil && firstErr == nil {
+				firstErr = err
+			}
+			if !ok {
+				changes = append(changes, "sink "+name+" removed")
+			}
+		}
+	}
+	formatter, err := formatterFor(next)
+	if err != nil {
+		return changes, err
+	}
+	for _, name := range sortedKeys(next.Sinks) {
+		settings, ok := previous.Sinks[name]
+		if ok && reflect.DeepEqual(settings, next.Sinks[name]) {
+			continue
+		}
+		sink, err := next.sink(name, formatter)
+		if err == nil {
+			err = logger.AddSink(name, sink)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if ok {
+			changes = append(changes, "sink "+name+" changed")
+		} else {
+			changes = append(changes, "sink "+name+" added")
+		}
+	}
+	return changes, firstErr
+}
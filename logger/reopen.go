@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// Reopen closes the log file and the destination files and opens them again by path. Call it after an external
+// tool such as logrotate renamed the files; without it the logger keeps writing to the renamed files. Entries
+// buffered at that time are written to the reopened files; flushes between the rename and Reopen fail like for
+// any missing file, so call it right after renaming.
+func (logger *Logger) Reopen() error {
+	logger.sendLock.RLock()
+	defer logger.sendLock.RUnlock()
+	select {
+	case <-logger.stopCh:
+		return fmt.Errorf("logger is closed")
+	default:
+	}
+	return logger.worker.Reopen()
+}
+
+// ReopenOnSignal calls Reopen whenever the process receives one of the given signals, SIGHUP if none are given,
+// until the returned function is called or the logger is closed. Reopen failures go to the error callback. On
+// systems without SIGHUP, such as Windows, nothing is watched unless signals are given. A logrotate config for it
+// looks like:
+//
+//	/var/log/app.log {
+//		daily
+//		postrotate
+//			kill -HUP $(cat /var/run/app.pid)
+//		endscript
+//	}
+func (logger *Logger) ReopenOnSignal(signals ...os.Signal) (stop func()) {
+	if len(signals) == 0 {
+		signals = defaultReopenSignals
+	}
+	stopped := make(chan struct{})
+	var once sync.Once
+	stop = func() {
+		once.Do(func() { close(stopped) })
+	}
+	if len(signals) == 0 {
+		return stop
+	}
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+	go func() {
+		defer signal.Stop(received)
+		for {
+			select {
+			case <-received:
+				if err := logger.Reopen(); err != nil {
+					logger.errorCallback()
+					if handler := logger.workerOptions.ErrorHandler; handler != nil {
+						handler(err, logWriter.OpReopen, nil)
+					}
+				}
+			case <-stopped:
+				return
+			case <-logger.stopCh:
+				return
+			}
+		}
+	}()
+	return stop
+}
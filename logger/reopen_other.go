@@ -0,0 +1,10 @@
+//go:build !unix
+
+package logger
+
+import (
+	"os"
+)
+
+//signals ReopenOnSignal watches by default: none, as there is no SIGHUP.
+var defaultReopenSignals []os.Signal
@@ -0,0 +1,11 @@
+//go:build unix
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+//signals ReopenOnSignal watches by default.
+var defaultReopenSignals = []os.Signal{syscall.SIGHUP}
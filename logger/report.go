@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CloseReport describes what happened to the logged entries when a logger was closed.
+type CloseReport struct {
+	EntriesFlushed uint64           //entries written to the log files over the logger's lifetime
+	EntriesDropped uint64           //entries discarded after failed flushes, by the overflow policy or logged after closing
+	BytesWritten   uint64           //bytes written to the log files over the logger's lifetime
+	Duration       time.Duration    //time taken to drain, flush and close
+	SinkErrors     map[string]error //errors of the final flush or of closing a file, keyed by file path, or sink name
+}
+
+// Err returns one of the sink errors, or nil if closing succeeded everywhere.
+func (report CloseReport) Err() error {
+	for _, err := range report.SinkErrors {
+		return err
+	}
+	return nil
+}
+
+// CloseError is returned by Close when entries were dropped or a file or sink failed. Its report tells the
+// details.
+type CloseError struct {
+	Report CloseReport
+}
+
+func (e *CloseError) Error() string {
+	var problems []string
+	if e.Report.EntriesDropped > 0 {
+		problems = append(problems, fmt.Sprintf("%d entries dropped", e.Report.EntriesDropped))
+	}
+	names := make([]string, 0, len(e.Report.SinkErrors))
+	for name := range e.Report.SinkErrors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		problems = append(problems, fmt.Sprintf("%s: %v", name, e.Report.SinkErrors[name]))
+	}
+	return "closing logger: " + strings.Join(problems, "; ")
+}
+
+// Close closes the logger like CloseLogger, draining the channel, flushing and closing the files and sinks,
+// but returns once ctx is done even if closing has not finished, e.g. because a sink hangs:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	if err := myLogger.Close(ctx); err != nil {
+//		fmt.Fprintln(os.Stderr, err)
+//	}
+//
+// It returns a *CloseError if entries were dropped or a file or sink failed, and ctx's error, wrapped, if ctx
+// was done first; closing then goes on in the background. Calling it again returns the result of the first
+// close.
+func (logger *Logger) Close(ctx context.Context) error {
+	done := make(chan CloseReport, 1)
+	go func() {
+		done <- logger.CloseLogger()
+	}()
+	select {
+	case report := <-done:
+		if report.EntriesDropped > 0 || len(report.SinkErrors) > 0 {
+			return &CloseError{Report: report}
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("closing logger: %w", ctx.Err())
+	}
+}
+
+// CloseWithTimeout closes the logger like Close, giving up waiting after timeout.
+func (logger *Logger) CloseWithTimeout(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return logger.Close(ctx)
+}
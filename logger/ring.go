@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"sync"
+)
+
+//traceRing keeps the latest entries filtered out by the logger level, oldest first, see WithTraceOnError.
+type traceRing struct {
+	lock    sync.Mutex        //guards the fields below
+	entries []logWriter.Entry //the ring, of fixed length
+	next    int               //index the next entry is stored at
+	count   int               //entries stored, at most len(entries)
+}
+
+//This returns a ring keeping size entries.
+func newTraceRing(size int) *traceRing {
+	return &traceRing{entries: make([]logWriter.Entry, size)}
+}
+
+//This method stores the entry, replacing the oldest one if the ring is full.
+func (r *traceRing) add(entry logWriter.Entry) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.count < len(r.entries) {
+		r.count++
+	}
+}
+
+//This method removes and returns the stored entries, oldest first.
+func (r *traceRing) drain() []logWriter.Entry {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.count == 0 {
+		return nil
+	}
+	drained := make([]logWriter.Entry, 0, r.count)
+	for i := len(r.entries) - r.count; i < len(r.entries); i++ {
+		index := (r.next + i) % len(r.entries)
+		drained = append(drained, r.entries[index])
+		r.entries[index] = logWriter.Entry{}
+	}
+	r.count = 0
+	return drained
+}
+
+//This method records the call site of an entry filtered out by the logger level and keeps it for
+// WithTraceOnError, tagged with the logger's destination and fields like a logged entry.
+func (logger *Logger) remember(entry logWriter.Entry) {
+	logger.annotate(&entry, entryCallerSkip)
+	entry.SetDestination(logger.destinationFor(entry.Level()))
+	entry.SetFields(logger.fields)
+	entry.SetLeadingKeys(logger.leading)
+	logger.ring.add(entry)
+}
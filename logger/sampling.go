@@ -0,0 +1,199 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sampling reduces floods of identical messages at a level. Within every interval the first First entries of
+// a message are logged, then every Thereafter-th one; the others are logged with probability Rate. Entries with
+// the same level, destination and message, or format for formatted entries, count as the same message. When the
+// interval of a message ends, a "sampled: dropped N similar entries" entry reports the entries left out. It is
+// attributed to the call site that first logged the message in the interval and written right before the entry
+// that noticed the end of the interval, with that entry's time. Intervals are measured with the clock given to
+// WithClock, if any.
+type Sampling struct {
+	Interval   time.Duration //window the counts are kept for, one second by default
+	First      int           //entries of a message logged per interval before sampling starts
+	Thereafter int           //after First, every Thereafter-th entry is logged, 0 to log none of them
+	Rate       float64       //probability with which the other entries are logged, 0 to log none of them
+}
+
+//sampleKey identifies the entries counted together.
+type sampleKey struct {
+	level       logWriter.Level //level of the entries
+	destination string          //destination of the entries
+	message     string          //format of formatted entries, message of the others
+}
+
+//sampleCount counts the entries of a message in its current interval.
+type sampleCount struct {
+	start   time.Time //start of the interval
+	caller  uintptr   //call site of the first entry of the interval, 0 if not captured
+	seen    int       //entries of the interval
+	dropped int       //entries of the interval left out
+}
+
+//sampler decides which entries of the sampled levels are logged. It is shared by a logger and the loggers
+// derived from it, and replaced as a whole when a reloaded config changes the policies.
+type sampler struct {
+	policies  map[logWriter.Level]Sampling //policies of the sampled levels, not modified after New
+	lock      sync.Mutex                   //guards the fields below
+	counts    map[sampleKey]*sampleCount   //counts of the messages seen in their current interval
+	lastSweep time.Time                    //time expired intervals were last reported
+	sampled   uint64                       //entries left out in total
+}
+
+// WithSampling samples the entries logged at level as described by policy, e.g.
+//
+//	logger.WithSampling(logWriter.DebugLevel, logger.Sampling{Interval: time.Second, First: 100, Thereafter: 100})
+//
+// logs the first 100 entries of every debug message per second and every 100th after that. Give it once per
+// level to sample; other levels are not sampled.
+func WithSampling(level logWriter.Level, policy Sampling) Option {
+	return func(o *options) {
+		if policy.Interval <= 0 {
+			policy.Interval = time.Second
+		}
+		if o.sampling == nil {
+			o.sampling = make(map[logWriter.Level]Sampling)
+		}
+		o.sampling[level] = policy
+	}
+}
+
+//This returns a sampler for the policies, nil if there are none, whose first interval starts at now.
+func newSampler(policies map[logWriter.Level]Sampling, now time.Time) *sampler {
+	if len(policies) == 0 {
+		return nil
+	}
+	return &sampler{policies: policies, counts: make(map[sampleKey]*sampleCount), lastSweep: now}
+}
+
+//This method reports whether the entry is logged at now, the time of the logger's clock, and returns the
+// summaries of the messages whose interval ended since the last call, to be logged before it and stamped with
+// its time.
+func (s *sampler) sample(entry *logWriter.Entry, now time.Time) (bool, []logWriter.Entry) {
+	policy, sampled := s.policies[entry.Level()]
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	summaries := s.sweep(now, entry.Time(), false)
+	if !sampled {
+		return true, summaries
+	}
+	key := sampleKey{level: entry.Level(), destination: entry.Destination(), message: entry.Format()}
+	if len(key.message) == 0 {
+		key.message = entry.Message()
+	}
+	count, ok := s.counts[key]
+	if !ok || now.Sub(count.start) >= policy.Interval {
+		if ok && count.dropped > 0 {
+			summaries = append(summaries, summary(key, count, entry.Time()))
+		}
+		count = &sampleCount{start: now, caller: entry.CallerPC()}
+		s.counts[key] = count
+	}
+	count.seen++
+	if count.seen <= policy.First {
+		return true, summaries
+	}
+	if policy.Thereafter > 0 && (count.seen-policy.First)%policy.Thereafter == 0 {
+		return true, summaries
+	}
+	if policy.Rate > 0 && rand.Float64() < policy.Rate {
+		return true, summaries
+	}
+	count.dropped++
+	s.sampled++
+	return false, summaries
+}
+
+//This method removes the messages whose interval ended, or all of them if all is set, and returns summaries of
+// those with entries left out, logged at the given time. Unless all is set it only looks once per shortest
+// interval. It must be called with lock held.
+func (s *sampler) sweep(now time.Time, logged time.Time, all bool) []logWriter.Entry {
+	shortest := time.Duration(0)
+	for _, policy := range s.policies {
+		if shortest == 0 || policy.Interval < shortest {
+			shortest = policy.Interval
+		}
+	}
+	if !all && now.Sub(s.lastSweep) < shortest {
+		return nil
+	}
+	s.lastSweep = now
+	var summaries []logWriter.Entry
+	for key, count := range s.counts {
+		if all || now.Sub(count.start) >= s.policies[key.level].Interval {
+			if count.dropped > 0 {
+				summaries = append(summaries, summary(key, count, logged))
+			}
+			delete(s.counts, key)
+		}
+	}
+	return summaries
+}
+
+//Util method that returns the entry reporting the entries of a message left out in an interval, logged at the
+// given time from the call site of the message.
+func summary(key sampleKey, count *sampleCount, logged time.Time) logWriter.Entry {
+	message := fmt.Sprintf("sampled: dropped %d similar entries: %s", count.dropped, key.message)
+	entry := logWriter.NewEntry(key.level, message)
+	entry.SetDestination(key.destination)
+	entry.SetTime(logged)
+	entry.SetCaller(count.caller)
+	return entry
+}
+
+//This method returns the entries left out in total, 0 for a nil sampler.
+func (s *sampler) count() uint64 {
+	if s == nil {
+		return 0
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.sampled
+}
+
+//This method numbers the entries and puts them on the channel, bypassing the sampler.
+func (logger *Logger) enqueueAll(summaries []logWriter.Entry) {
+	for _, entry := range summaries {
+		logger.enqueueNumbered(entry)
+	}
+}
+
+//This method returns the sampler in effect, nil if no level is sampled.
+func (logger *Logger) currentSampler() *sampler {
+	s, _ := logger.sampling.Load().(*sampler)
+	return s
+}
+
+//This method replaces the sampler by one for the policies and logs the summaries of the replaced one.
+func (logger *Logger) setSampling(policies map[logWriter.Level]Sampling) {
+	previous := logger.currentSampler()
+	logger.sampling.Store(newSampler(policies, logger.now()))
+	if previous != nil {
+		logger.flushSampler(previous)
+		atomic.AddUint64(&logger.resampled, previous.count())
+	}
+}
+
+//This method logs the summaries of all messages with entries left out, e.g. before the logger closes.
+func (logger *Logger) flushSamples() {
+	if sampler := logger.currentSampler(); sampler != nil {
+		logger.flushSampler(sampler)
+	}
+}
+
+//This method logs the summaries of all messages the sampler left entries out of.
+func (logger *Logger) flushSampler(s *sampler) {
+	now := logger.now()
+	s.lock.Lock()
+	summaries := s.sweep(now, now, true)
+	s.lock.Unlock()
+	logger.enqueueAll(summaries)
+}
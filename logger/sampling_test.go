@@ -0,0 +1,34 @@
+package logger_test
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"github.com/shyamgrover/go-lite-logger/logtest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSamplingClock checks that sampling intervals are measured with the logger's clock, so that a test clock
+// ends an interval without waiting.
+func TestSamplingClock(t *testing.T) {
+	clock := logtest.NewClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	sink := logtest.NewSink()
+	myLogger := newTestLogger(t, logger.WithClock(clock), logger.WithSink("test", sink),
+		logger.WithSampling(logWriter.InfoLevel, logger.Sampling{Interval: time.Second, First: 1}))
+	for i := 0; i < 3; i++ {
+		myLogger.Info("cache miss")
+	}
+	clock.Advance(time.Second)
+	myLogger.Info("cache miss")
+	myLogger.CloseLogger()
+
+	var messages []string
+	for _, entry := range sink.Entries() {
+		messages = append(messages, entry.Message())
+	}
+	want := []string{"cache miss", "sampled: dropped 2 similar entries: cache miss", "cache miss"}
+	if strings.Join(messages, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got entries %q, want %q", messages, want)
+	}
+}
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// SelfCheck verifies the whole pipeline end to end: it logs a probe record at Info level (regardless of the
+// logger level and status) that is numbered and counted like any entry but not handed to the sinks, flushes it
+// to the file, reopens the file by name and parses the record back; with
+// a formatter set by WithFormatter only the presence of the record is checked. It
+// returns an error describing the first step that failed, so that a broken setup is detected at startup rather
+// than through the error callback later on.
+func (logger *Logger) SelfCheck() error {
+	info, err := os.Stat(logger.filename)
+	if err != nil {
+		return fmt.Errorf("self-check: %v", err)
+	}
+	offset := info.Size()
+	probe := fmt.Sprintf("go-lite-logger self-check %d", time.Now().UnixNano())
+
+	done := make(chan error, 1)
+	entry := logWriter.NewEntry(logWriter.InfoLevel, probe)
+	entry.SetFileOnly()
+	if !logger.enqueueNumbered(entry) || !logger.enqueue(logWriter.NewFlushEntry(done)) {
+		return fmt.Errorf("self-check: logger is closed")
+	}
+	if err = <-done; err != nil {
+		return fmt.Errorf("self-check: flush failed: %v", err)
+	}
+
+	file, err := os.Open(logger.filename)
+	if err != nil {
+		return fmt.Errorf("self-check: reopen failed: %v", err)
+	}
+	defer file.Close()
+	if info, err = file.Stat(); err == nil && info.Size() < offset {
+		offset = 0 //the file was rotated before the probe was written
+	}
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("self-check: %v", err)
+	}
+	var reader io.Reader = file
+	if keys := logger.workerOptions.Encryption; keys != nil {
+		reader = logWriter.NewDecryptReader(file, keys)
+	}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.Contains(line, probe) {
+			if logger.workerOptions.Formatter == nil && !strings.HasPrefix(line, "[INFO]") {
+				return fmt.Errorf("self-check: malformed probe record %q", line)
+			}
+			return nil
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("self-check: read failed: %v", err)
+	}
+	return fmt.Errorf("self-check: probe record not found in %s", logger.filename)
+}
@@ -0,0 +1,30 @@
+package logger_test
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"github.com/shyamgrover/go-lite-logger/logtest"
+	"testing"
+)
+
+// TestSelfCheckProbe checks that the probe record of SelfCheck is numbered and counted like any entry, and that
+// it is not handed to the sinks.
+func TestSelfCheckProbe(t *testing.T) {
+	sink := logtest.NewSink()
+	myLogger := newTestLogger(t, logger.WithSequence(), logger.WithSink("test", sink))
+	if err := myLogger.SelfCheck(); err != nil {
+		t.Fatal(err)
+	}
+	myLogger.Info("request handled")
+	myLogger.CloseLogger()
+
+	entries := sink.Entries()
+	if len(entries) != 1 || entries[0].Message() != "request handled" {
+		t.Fatalf("sink got %d entries, want only the logged one", len(entries))
+	}
+	if entries[0].Sequence() != 2 {
+		t.Errorf("got sequence %d, want 2 after the probe", entries[0].Sequence())
+	}
+	if stats := myLogger.Stats(); stats.EntriesEnqueued != 2 {
+		t.Errorf("got %d entries enqueued, want 2", stats.EntriesEnqueued)
+	}
+}
@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// ShardStrategy decides which worker of a sharded logger handles an entry, see WithShards.
+type ShardStrategy int
+
+const (
+	// ShardByCaller hands all entries of a call site to the same worker, so that the entries of one goroutine
+	// logging from one place keep their order. It is the default.
+	ShardByCaller ShardStrategy = iota
+	// ShardByLogger hands all entries of a logger to the same worker, so that the entries of each logger derived
+	// with With or WithFields keep their order, e.g. those of one request.
+	ShardByLogger
+	// ShardRoundRobin hands the entries to the workers in turn, which spreads the work most evenly but keeps no
+	// order between entries.
+	ShardRoundRobin
+)
+
+//shardStrategies are the names of the shard strategies, as accepted by ParseShardStrategy.
+var shardStrategies = []string{"caller", "logger", "round_robin"}
+
+// String returns the name of the strategy as ParseShardStrategy accepts it.
+func (strategy ShardStrategy) String() string {
+	if strategy >= 0 && int(strategy) < len(shardStrategies) {
+		return shardStrategies[strategy]
+	}
+	return fmt.Sprintf("ShardStrategy(%d)", int(strategy))
+}
+
+// ParseShardStrategy returns the strategy with the given name: caller, logger or round_robin. An empty name is
+// ShardByCaller.
+func ParseShardStrategy(name string) (ShardStrategy, error) {
+	if len(name) == 0 {
+		return ShardByCaller, nil
+	}
+	for i, known := range shardStrategies {
+		if strings.EqualFold(name, known) {
+			return ShardStrategy(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown shard strategy %q, want caller, logger or round_robin", name)
+}
+
+// WithShards spreads the work of the logger over n workers, each on a goroutine of its own with its own queue
+// and buffer, so that a service logging from many cores is not limited by the speed of one worker. The strategy
+// decides which worker handles an entry. The workers format their entries in parallel and append them to the
+// log file, so that entries handled by different workers may appear out of order; the sequence numbers of
+// WithSequence restore the order. With WithShardFiles every worker writes a file of its own instead. Flush,
+// Sync and CloseLogger cover all workers, and hooks, sinks and destinations see the entries of all of them.
+// Values below 2 leave the logger with one worker.
+func WithShards(n int, strategy ShardStrategy) Option {
+	return func(o *options) {
+		o.shards = n
+		o.shardBy = strategy
+	}
+}
+
+// WithShardFiles gives every worker of a logger created with WithShards a file of its own, so that they do
+// not share the buffer of the first worker either. The first worker writes the log file, the others the files
+// named after it with the number of the worker before the extension, app.1.log, app.2.log and so on. Each file
+// is rotated on its own.
+func WithShardFiles() Option {
+	return func(o *options) {
+		o.shardFiles = true
+	}
+}
+
+//shard is a worker of a sharded logger other than the first one, together with its queue.
+type shard struct {
+	channel chan logWriter.Entry //entries for the worker, nil if it reads queue
+	queue   *logWriter.Ring      //ring the entries go on instead of channel, nil without WithRingQueue
+	worker  *logWriter.Worker    //worker reading channel or queue
+	file    *os.File             //file of its own given by WithShardFiles, nil if it shares the log file
+}
+
+//Util method that returns the path of the file of shard i of a logger writing to path, see WithShardFiles.
+func shardFilePath(path string, i int) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + strconv.Itoa(i) + ext
+}
+
+//Util method that opens the files of the shards 1 to n-1 of a logger writing to path, and closes the ones already
+// opened if one of them cannot be opened.
+func openShardFiles(path string, n int, perm permissions) ([]*os.File, error) {
+	var files []*os.File
+	for i := 1; i < n; i++ {
+		file, err := openLogFile(shardFilePath(path, i), perm)
+		if err != nil {
+			for _, opened := range files {
+				opened.Close()
+			}
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+//This method creates and starts the shards 1 to n-1 of the logger's worker, with the given files or sharing the
+// log file if files is nil.
+func (logger *Logger) startShards(n int, files []*os.File, o options) {
+	for i := 1; i < n; i++ {
+		s := &shard{}
+		if files != nil {
+			s.file = files[i-1]
+		}
+		if o.ringQueue {
+			s.queue = logWriter.NewRing(o.channelSize)
+		} else {
+			s.channel = make(chan logWriter.Entry, o.channelSize)
+		}
+		s.worker = logger.worker.NewShard(s.file, s.channel, s.queue)
+		logger.shards = append(logger.shards, s)
+		go s.worker.Work()
+	}
+}
+
+//This method returns the channel and the ring, one of them nil, of the worker that handles the entry according
+// to the logger's shard strategy. Flush requests and entries without a call site, which the logger makes itself,
+// go to the first worker.
+func (logger *Logger) queueFor(entry logWriter.Entry) (chan logWriter.Entry, *logWriter.Ring) {
+	n := uint64(len(logger.shards) + 1)
+	if n == 1 || entry.IsFlush() || entry.CallerPC() == 0 {
+		return logger.channel, logger.queue
+	}
+	var i uint64
+	switch logger.shardBy {
+	case ShardByLogger:
+		i = logger.shardKey % n
+	case ShardRoundRobin:
+		i = atomic.AddUint64(&logger.shardTurn, 1) % n
+	default:
+		i = (uint64(entry.CallerPC()) * 0x9e3779b97f4a7c15 >> 32) % n
+	}
+	if i == 0 {
+		return logger.channel, logger.queue
+	}
+	s := logger.shards[i-1]
+	return s.channel, s.queue
+}
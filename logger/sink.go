@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"os"
+)
+
+// AddSink adds a sink that receives every entry logged from now on, next to the log file. Each sink is fed from
+// its own goroutine and queue, so a slow or failing sink does not hold up the file or other sinks; entries that
+// do not fit in its queue are counted as dropped. Sink write failures go to the error callback and LastError.
+// The sink is closed by CloseLogger, and its close error is reported under its name. A byte oriented
+// logWriter.Sink, such as a net.Conn, is added with logWriter.NewWriterSink:
+//
+//	conn, err := net.Dial("tcp", "collector:5170")
+//	...
+//	myLogger.AddSink("collector", logWriter.NewWriterSink(conn, logWriter.JSONFormatter{}))
+func (logger *Logger) AddSink(name string, sink logWriter.EntrySink) error {
+	if len(name) == 0 {
+		return fmt.Errorf("sink name must not be empty")
+	}
+	logger.destLock.Lock()
+	defer logger.destLock.Unlock()
+	select {
+	case <-logger.stopCh:
+		return fmt.Errorf("logger is closed")
+	default:
+	}
+	return logger.worker.AddSink(name, sink)
+}
+
+// RemoveSink detaches the sink added under name, e.g. with AddSink or WithSink, after the entries queued for it
+// were written, and closes it. It returns an error if there is no sink of that name.
+func (logger *Logger) RemoveSink(name string) error {
+	logger.destLock.Lock()
+	defer logger.destLock.Unlock()
+	return logger.worker.RemoveSink(name)
+}
+
+// AddHook adds a hook that the worker runs for every entry at the hook's levels before the entry is formatted,
+// written to the files or handed to the sinks. Hooks can add fields, e.g. the deployment, count entries or veto
+// them by returning logWriter.ErrVeto:
+//
+//	type deployment struct{}
+//
+//	func (deployment) Levels() []logWriter.Level { return logWriter.AllLevels }
+//	func (deployment) Fire(entry *logWriter.Entry) error {
+//		entry.AddField("region", os.Getenv("REGION"))
+//		return nil
+//	}
+func (logger *Logger) AddHook(hook logWriter.Hook) {
+	logger.worker.AddHook(hook)
+}
+
+// OnDelivery calls hook after every buffer of entries the worker writes successfully to the log file or to
+// the file of a destination, with the number of entries and bytes and their sequence range. It suits delivery
+// metrics and checkpoints:
+//
+//	myLogger.OnDelivery(func(d logWriter.Delivery) {
+//		deliveredBytes.Add(float64(d.Bytes))
+//	})
+func (logger *Logger) OnDelivery(hook logWriter.DeliveryHook) {
+	logger.worker.OnDelivery(hook)
+}
+
+//stderr is standard error as a logWriter.Sink that stays open when the sink is closed.
+type stderr struct{}
+
+func (stderr) Write(data []byte) (int, error) {
+	return os.Stderr.Write(data)
+}
+
+func (stderr) Close() error {
+	return nil
+}
+
+// WithStderr also writes the entries at level or more severe to standard error, e.g. WithStderr(logWriter.WarnLevel)
+// so that operators see problems on the console and in container logs while every entry still goes to the log
+// file. They are rendered by the formatter given to WithFormatter, or as text, from a sink named "stderr". Only
+// the entries written to standard error count towards CloseReport, and none of them is dropped when a flood of
+// less severe entries comes in.
+func WithStderr(level logWriter.Level) Option {
+	return func(o *options) {
+		o.stderrLevel = &level
+	}
+}
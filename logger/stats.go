@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats are running totals of a logger, for monitoring the health of logging, e.g. alerting when EntriesDropped
+// grows. Entries written to several sinks are counted once for each, like in CloseReport.
+type Stats struct {
+	EntriesEnqueued uint64    //entries handed to the worker
+	EntriesWritten  uint64    //entries written to the log files and delivered to sinks
+	EntriesDropped  uint64    //entries discarded after failed flushes, by the overflow policy or logged after closing
+	EntriesSampled  uint64    //entries left out by sampling, see WithSampling
+	BytesFlushed    uint64    //bytes written to the log files
+	LastFlush       time.Time //time of the latest successful write to a log file, zero if there was none
+	QueueDepth      int       //entries waiting on the channel for the worker
+
+	EntriesByLevel map[logWriter.Level]uint64 //entries handed to the worker, by level
+	Failures       map[string]uint64          //failures reported to the error handler, by operation, e.g. logWriter.OpWrite
+}
+
+// Stats returns the running totals of the logger, its destinations and its sinks. It is cheap enough to be
+// polled and keeps working after the logger is closed.
+func (logger *Logger) Stats() Stats {
+	counters := logger.worker.Counters()
+	stats := Stats{
+		EntriesEnqueued: atomic.LoadUint64(&logger.enqueued),
+		EntriesWritten:  counters.EntriesFlushed,
+		EntriesDropped:  counters.EntriesDropped + atomic.LoadUint64(&logger.dropped),
+		EntriesSampled:  atomic.LoadUint64(&logger.resampled) + logger.currentSampler().count(),
+		BytesFlushed:    counters.BytesWritten,
+		LastFlush:       counters.LastFlush,
+		QueueDepth:      logger.queueDepth(),
+		EntriesByLevel:  make(map[logWriter.Level]uint64),
+		Failures:        make(map[string]uint64),
+	}
+	logger.levelCounts.Range(func(level, n interface{}) bool {
+		stats.EntriesByLevel[level.(logWriter.Level)] = atomic.LoadUint64(n.(*uint64))
+		return true
+	})
+	logger.failures.Range(func(op, n interface{}) bool {
+		stats.Failures[op.(string)] = atomic.LoadUint64(n.(*uint64))
+		return true
+	})
+	return stats
+}
+
+//Util method that increments the counter of key in counters, a map of *uint64.
+func count(counters *sync.Map, key interface{}) {
+	n, ok := counters.Load(key)
+	if !ok {
+		n, _ = counters.LoadOrStore(key, new(uint64))
+	}
+	atomic.AddUint64(n.(*uint64), 1)
+}
+
+//This method returns the number of entries waiting for the workers on the channels or the rings.
+func (logger *Logger) queueDepth() int {
+	depth := len(logger.channel)
+	if logger.queue != nil {
+		depth = logger.queue.Len()
+	}
+	for _, s := range logger.shards {
+		if s.queue != nil {
+			depth += s.queue.Len()
+		} else {
+			depth += len(s.channel)
+		}
+	}
+	return depth
+}
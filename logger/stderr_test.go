@@ -0,0 +1,67 @@
+package logger_test
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//This method points os.Stderr at a file in a temporary directory for the rest of the test and returns the file's
+// path.
+func captureStderr(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "stderr")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stderr
+	os.Stderr = file
+	t.Cleanup(func() {
+		os.Stderr = saved
+		file.Close()
+	})
+	return path
+}
+
+// TestStderrFlood checks that an Error written to standard error is not dropped when a flood of Debug entries,
+// which standard error does not take, comes in ahead of it.
+func TestStderrFlood(t *testing.T) {
+	path := captureStderr(t)
+	myLogger := newTestLogger(t, logger.WithLevel(logWriter.DebugLevel), logger.WithStderr(logWriter.ErrorLevel))
+	for i := 0; i < 20000; i++ {
+		myLogger.Debug("cache miss")
+	}
+	myLogger.Error("request failed")
+	report := myLogger.CloseLogger()
+	if report.EntriesDropped != 0 {
+		t.Errorf("%d entries dropped", report.EntriesDropped)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "request failed") {
+		t.Errorf("standard error misses the Error entry: %q", data)
+	}
+	if strings.Contains(string(data), "cache miss") {
+		t.Error("standard error holds Debug entries")
+	}
+}
+
+// TestStderrCounters checks that the entries standard error leaves out are not counted as flushed.
+func TestStderrCounters(t *testing.T) {
+	captureStderr(t)
+	myLogger := newTestLogger(t, logger.WithStderr(logWriter.ErrorLevel))
+	for i := 0; i < 100; i++ {
+		myLogger.Info("request handled")
+	}
+	myLogger.Error("request failed")
+	report := myLogger.CloseLogger()
+	if report.EntriesFlushed != 102 {
+		t.Errorf("got %d entries flushed, want 101 in the file and 1 on standard error", report.EntriesFlushed)
+	}
+}
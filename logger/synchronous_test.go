@@ -0,0 +1,34 @@
+package logger_test
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSynchronousDestinations checks that synchronous logging calls have written entries sent to level files
+// and named destinations when they return, not only those of the main log file.
+func TestSynchronousDestinations(t *testing.T) {
+	dir := t.TempDir()
+	myLogger := newTestLogger(t, logger.WithSynchronous(), logger.WithDir(dir),
+		logger.WithLevelFile("error.log", logWriter.ErrorLevel))
+	if err := myLogger.AddDestination("audit", "audit.log", dir+string(os.PathSeparator)); err != nil {
+		t.Fatal(err)
+	}
+	myLogger.Info("request handled")
+	myLogger.Error("request failed")
+	myLogger.To("audit").Info("user deleted")
+	for file, message := range map[string]string{"error.log": "request failed", "audit.log": "user deleted"} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), message) {
+			t.Errorf("%s holds %q, want %q", file, data, message)
+		}
+	}
+}
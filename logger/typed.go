@@ -0,0 +1,59 @@
+package logger
+
+import "github.com/shyamgrover/go-lite-logger/logWriter"
+
+//This method logs a message with typed fields. The fields are copied, so that the caller's variadic slice does
+// not escape to the heap and disabled calls allocate nothing.
+func (logger *Logger) logTypedEntry(level logWriter.Level, message string, fields []logWriter.TypedField) {
+	entry := logWriter.NewEntry(level, message)
+	logger.annotate(&entry, entryCallerSkip)
+	if len(fields) > 0 {
+		entry.SetTypedFields(append([]logWriter.TypedField(nil), fields...))
+	}
+	logger.send(entry)
+}
+
+// Tracew logs a message at level Trace with typed fields, see logWriter.TypedField:
+//
+//	myLogger.Tracew("cache miss", logWriter.String("key", key))
+func (logger *Logger) Tracew(message string, fields ...logWriter.TypedField) {
+	if TraceEnabled && logger.isLoggable(logWriter.TraceLevel) {
+		logger.logTypedEntry(logWriter.TraceLevel, message, fields)
+	}
+}
+
+// Debugw logs a message at level Debug with typed fields, see Tracew.
+func (logger *Logger) Debugw(message string, fields ...logWriter.TypedField) {
+	if DebugEnabled && logger.isLoggable(logWriter.DebugLevel) {
+		logger.logTypedEntry(logWriter.DebugLevel, message, fields)
+	}
+}
+
+// Infow logs a message at level Info with typed fields, see Tracew.
+func (logger *Logger) Infow(message string, fields ...logWriter.TypedField) {
+	if InfoEnabled && logger.isLoggable(logWriter.InfoLevel) {
+		logger.logTypedEntry(logWriter.InfoLevel, message, fields)
+	}
+}
+
+// Warnw logs a message at level Warn with typed fields, see Tracew.
+func (logger *Logger) Warnw(message string, fields ...logWriter.TypedField) {
+	if WarnEnabled && logger.isLoggable(logWriter.WarnLevel) {
+		logger.logTypedEntry(logWriter.WarnLevel, message, fields)
+	}
+}
+
+// Errorw logs a message at level Error with typed fields, see Tracew.
+func (logger *Logger) Errorw(message string, fields ...logWriter.TypedField) {
+	if logger.isLoggable(logWriter.ErrorLevel) {
+		logger.logTypedEntry(logWriter.ErrorLevel, message, fields)
+	}
+}
+
+// Logw logs a message at the given level, built-in or registered with logWriter.RegisterLevel, with typed
+// fields, like Log.
+func (logger *Logger) Logw(level logWriter.Level, message string, fields ...logWriter.TypedField) {
+	if MaxLevel.Enables(level) && logger.isLoggable(level) {
+		logger.logTypedEntry(level, message, fields)
+	}
+}
@@ -0,0 +1,160 @@
+package logger
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//verbosityWindow keeps track of a temporary debug window. It remembers the level that was in effect before
+// the window was opened so that it can be restored once the window elapses.
+type verbosityWindow struct {
+	lock       sync.Mutex      //guards the fields below
+	timer      *time.Timer     //timer that closes the window
+	generation uint64          //incremented on every EnableDebugFor call so that stale timers are ignored
+	restore    logWriter.Level //level to restore when the window closes
+}
+
+// EnableDebugFor raises the logger level to Debug, unless it is Trace already, for the given duration and
+// automatically restores the previous level once the duration has elapsed. Calling it again while a window is
+// still open extends the window; the level restored at the end is still the one in effect before the first call.
+func (logger *Logger) EnableDebugFor(duration time.Duration) {
+	window := &logger.verbosity
+	window.lock.Lock()
+	defer window.lock.Unlock()
+
+	if window.timer != nil {
+		window.timer.Stop()
+	} else {
+		window.restore = logger.GetLevel()
+	}
+	window.generation++
+	generation := window.generation
+	if !logger.GetLevel().Enables(logWriter.DebugLevel) {
+		logger.SetLevel(logWriter.DebugLevel)
+	}
+	window.timer = time.AfterFunc(duration, func() {
+		logger.closeDebugWindow(generation)
+	})
+}
+
+//This method restores the level saved by EnableDebugFor. It does nothing if the window has been extended
+// or stopped since the timer with the given generation was started.
+func (logger *Logger) closeDebugWindow(generation uint64) {
+	window := &logger.verbosity
+	window.lock.Lock()
+	defer window.lock.Unlock()
+
+	if window.timer == nil || window.generation != generation {
+		return
+	}
+	window.timer = nil
+	logger.SetLevel(window.restore)
+}
+
+//This method stops a pending debug window without restoring the level. It is called on logger shutdown.
+func (window *verbosityWindow) stop() {
+	window.lock.Lock()
+	defer window.lock.Unlock()
+
+	if window.timer != nil {
+		window.timer.Stop()
+		window.timer = nil
+	}
+}
+
+//number of stack frames between scopedLevels.allows and the user's call site: allows, isLoggable and
+// the exported logging method.
+const scopeCallerSkip = 3
+
+//scope is a level override applied to entries whose call site matches pattern.
+type scope struct {
+	pattern string          //package import path or file path suffix
+	level   logWriter.Level //level in effect for matching call sites
+}
+
+//scopedLevels holds the configured scopes. Readers load the current slice without locking; writers
+// replace it under lock so that the logging fast path never blocks.
+type scopedLevels struct {
+	lock   sync.Mutex   //serializes writers
+	scopes atomic.Value //[]scope
+}
+
+// SetLevelFor sets the level used for entries logged from call sites matching pattern, without changing the
+// level for everything else. The pattern is either a package import path (e.g. "github.com/me/app/db", which
+// also matches its sub packages), a file path suffix (e.g. "db/store.go") or a directory path suffix
+// (e.g. "app/db"). A scope can only raise
+// verbosity: entries allowed by the logger level are always logged.
+func (logger *Logger) SetLevelFor(pattern string, level logWriter.Level) {
+	logger.scopes.set(pattern, level, true)
+}
+
+// ResetLevelFor removes the scope previously added with SetLevelFor for the given pattern.
+func (logger *Logger) ResetLevelFor(pattern string) {
+	logger.scopes.set(pattern, 0, false)
+}
+
+//This method adds, replaces or removes the scope for pattern.
+func (s *scopedLevels) set(pattern string, level logWriter.Level, add bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current, _ := s.scopes.Load().([]scope)
+	updated := make([]scope, 0, len(current)+1)
+	for _, sc := range current {
+		if sc.pattern != pattern {
+			updated = append(updated, sc)
+		}
+	}
+	if add {
+		updated = append(updated, scope{pattern: pattern, level: level})
+	}
+	s.scopes.Store(updated)
+}
+
+//This method reports whether an entry at the given level is allowed by a scope matching the caller found
+// skip frames above it. The caller is only looked up when at least one scope is configured.
+func (s *scopedLevels) allows(level logWriter.Level, skip int) bool {
+	scopes, _ := s.scopes.Load().([]scope)
+	if len(scopes) == 0 {
+		return false
+	}
+	pc, file, _, ok := runtime.Caller(skip)
+	if !ok {
+		return false
+	}
+	pkg := ""
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		pkg = packageOf(fn.Name())
+	}
+	for _, sc := range scopes {
+		if sc.level.Enables(level) && sc.matches(pkg, file) {
+			return true
+		}
+	}
+	return false
+}
+
+//This method reports whether the given package path or file path matches the scope pattern.
+func (sc scope) matches(pkg string, file string) bool {
+	if pkg == sc.pattern || strings.HasPrefix(pkg, sc.pattern+"/") {
+		return true
+	}
+	dir := path.Dir(file)
+	return file == sc.pattern || strings.HasSuffix(file, "/"+sc.pattern) ||
+		dir == sc.pattern || strings.HasSuffix(dir, "/"+sc.pattern)
+}
+
+//This method extracts the package import path from a fully qualified function name such as
+// "github.com/me/app/db.(*Store).Get".
+func packageOf(funcName string) string {
+	slash := strings.LastIndex(funcName, "/")
+	if dot := strings.Index(funcName[slash+1:], "."); dot >= 0 {
+		return funcName[:slash+1+dot]
+	}
+	return funcName
+}
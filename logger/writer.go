@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"bytes"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"io"
+	"sync"
+)
+
+//maxPartialLine is the size at which text without a newline is logged without waiting for the rest of the line.
+const maxPartialLine = 64 * 1024
+
+//levelWriter is the io.Writer returned by WriterLevel.
+type levelWriter struct {
+	lock    sync.Mutex      //guards partial
+	logger  *Logger         //logger the lines are logged through
+	level   logWriter.Level //level the lines are logged at
+	partial []byte          //text after the last newline written so far
+}
+
+// WriterLevel returns an io.Writer logging every line written to it as one entry at the given level, with this
+// logger's destination and fields. It lets libraries that only accept an io.Writer log through the logger:
+//
+//	server := &http.Server{ErrorLog: log.New(myLogger.WriterLevel(logWriter.ErrorLevel), "", 0)}
+//	cmd.Stderr = myLogger.WithField("cmd", "backup").WriterLevel(logWriter.WarnLevel)
+//
+// Text after the last newline is kept until a later write completes the line, or until it grows to 64 KiB.
+// Writes never fail; lines below the logger's level are discarded.
+func (logger *Logger) WriterLevel(level logWriter.Level) io.Writer {
+	return &levelWriter{logger: logger, level: level}
+}
+
+// Write implements io.Writer.
+func (w *levelWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	data := p
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		line := data[:i]
+		if len(w.partial) > 0 {
+			line = append(w.partial, line...)
+			w.partial = w.partial[:0]
+		}
+		w.log(line)
+		data = data[i+1:]
+	}
+	w.partial = append(w.partial, data...)
+	if len(w.partial) >= maxPartialLine {
+		w.log(w.partial)
+		w.partial = w.partial[:0]
+	}
+	return len(p), nil
+}
+
+//This method logs one line, without a trailing carriage return, if the writer's level is loggable.
+func (w *levelWriter) log(line []byte) {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if !MaxLevel.Enables(w.level) || !w.logger.isLoggable(w.level) {
+		return
+	}
+	w.logger.logEntry(w.level, string(line))
+}
@@ -0,0 +1,94 @@
+package logtest
+
+import (
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"sync"
+	"time"
+)
+
+// Clock is a logWriter.Clock that only moves when Advance is called. Passed to logger.WithClock it stamps every
+// entry with a known time and lets a test fire the timer based flush when it chooses:
+//
+//	clock := logtest.NewClock(time.Date(2024, 5, 27, 7, 32, 0, 0, time.UTC))
+//	l, sink := logtest.New(t, logger.WithClock(clock), logger.WithFlushInterval(time.Second))
+//	clock.Advance(time.Second)
+type Clock struct {
+	lock    sync.Mutex
+	now     time.Time
+	tickers []*ticker
+}
+
+//ticker is a Ticker of a Clock.
+type ticker struct {
+	c       chan time.Time
+	every   time.Duration
+	next    time.Time     //time of the next tick
+	stopped chan struct{} //closed by Stop
+	once    sync.Once
+}
+
+// NewClock returns a Clock showing start.
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now returns the time of the clock.
+func (c *Clock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// NewTicker returns a ticker that ticks every d of the clock's time, counted from now.
+func (c *Clock) NewTicker(d time.Duration) logWriter.Ticker {
+	if d <= 0 {
+		panic("logtest: non-positive interval for NewTicker")
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	t := &ticker{c: make(chan time.Time), every: d, next: c.now.Add(d), stopped: make(chan struct{})}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// Advance moves the clock forward by d and delivers one tick to every ticker that became due, however many
+// intervals d spans, as time.Ticker drops the ticks of a slow reader. It returns once every tick was received, so
+// a worker's timer flush has started when Advance returns; wait for its delivery, e.g. with
+// logger.WithDeliveryHook, before checking the file.
+func (c *Clock) Advance(d time.Duration) {
+	c.lock.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+	var due []*ticker
+	running := c.tickers[:0]
+	for _, t := range c.tickers {
+		select {
+		case <-t.stopped:
+			continue
+		default:
+		}
+		running = append(running, t)
+		if !t.next.After(now) {
+			for !t.next.After(now) {
+				t.next = t.next.Add(t.every)
+			}
+			due = append(due, t)
+		}
+	}
+	c.tickers = running
+	c.lock.Unlock()
+	for _, t := range due {
+		select {
+		case t.c <- now:
+		case <-t.stopped:
+		}
+	}
+}
+
+func (t *ticker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *ticker) Stop() {
+	t.once.Do(func() { close(t.stopped) })
+}
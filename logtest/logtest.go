@@ -0,0 +1,161 @@
+// Package logtest records the entries of a logger in memory, so that applications can unit-test their logging
+// without parsing log files:
+//
+//	func TestCheckout(t *testing.T) {
+//		myLogger, sink := logtest.New(t)
+//		checkout(myLogger, order)
+//		sink.AssertLogged(t, logWriter.InfoLevel, "order created")
+//		sink.AssertNotLogged(t, logWriter.ErrorLevel, "")
+//	}
+package logtest
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/logger"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// T is the part of testing.TB the assertions use, so that they also work with other test frameworks.
+type T interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Sink is a logWriter.EntrySink keeping every entry it receives in memory. Add it to a logger with AddSink or
+// logger.WithSink, or let New do that. Sinks receive entries asynchronously, so call the logger's Flush before
+// looking at a sink added by hand; the sinks returned by New flush their logger themselves.
+type Sink struct {
+	lock    sync.Mutex        //guards entries
+	entries []logWriter.Entry //entries received, oldest first
+	flush   func() error      //flushes the logger feeding the sink, nil if unknown
+}
+
+// NewSink returns an empty sink.
+func NewSink() *Sink {
+	return &Sink{}
+}
+
+// New returns a logger at Trace level writing to a file in a temporary directory of the test, and the sink
+// recording its entries. Options are applied after the defaults, e.g. logger.WithLevel to test filtering. The
+// logger is closed when the test ends.
+func New(t testing.TB, opts ...logger.Option) (*logger.Logger, *Sink) {
+	t.Helper()
+	sink := NewSink()
+	opts = append([]logger.Option{logger.WithFile(filepath.Join(t.TempDir(), "test.log")), logger.WithLevel(logWriter.TraceLevel),
+		logger.WithSink("logtest", sink)}, opts...)
+	l, err := logger.New(opts...)
+	if err != nil {
+		t.Fatalf("logtest: creating logger: %v", err)
+	}
+	sink.flush = l.Flush
+	t.Cleanup(func() {
+		l.CloseLogger()
+	})
+	return l, sink
+}
+
+// WriteEntry implements logWriter.EntrySink.
+func (s *Sink) WriteEntry(entry logWriter.Entry) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// Close implements logWriter.EntrySink. The recorded entries stay available.
+func (s *Sink) Close() error {
+	return nil
+}
+
+// Entries returns the entries recorded so far, oldest first.
+func (s *Sink) Entries() []logWriter.Entry {
+	s.sync()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]logWriter.Entry(nil), s.entries...)
+}
+
+// LastEntry returns the entry recorded last, and false if none was recorded.
+func (s *Sink) LastEntry() (logWriter.Entry, bool) {
+	entries := s.Entries()
+	if len(entries) == 0 {
+		return logWriter.Entry{}, false
+	}
+	return entries[len(entries)-1], true
+}
+
+// Reset forgets the entries recorded so far.
+func (s *Sink) Reset() {
+	s.sync()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.entries = nil
+}
+
+// Find returns the entries at level whose message contains substring; an empty substring matches every message.
+func (s *Sink) Find(level logWriter.Level, substring string) []logWriter.Entry {
+	var found []logWriter.Entry
+	for _, entry := range s.Entries() {
+		if entry.Level() == level && strings.Contains(entry.Message(), substring) {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+// AssertLogged fails the test unless an entry at level with substring in its message was recorded.
+func (s *Sink) AssertLogged(t T, level logWriter.Level, substring string) {
+	t.Helper()
+	if len(s.Find(level, substring)) == 0 {
+		t.Errorf("no %s entry containing %q was logged; entries:\n%s", level, substring, s.dump())
+	}
+}
+
+// AssertNotLogged fails the test if an entry at level with substring in its message was recorded, e.g.
+// AssertNotLogged(t, logWriter.ErrorLevel, "") for no errors at all.
+func (s *Sink) AssertNotLogged(t T, level logWriter.Level, substring string) {
+	t.Helper()
+	if found := s.Find(level, substring); len(found) > 0 {
+		t.Errorf("unexpected %s entry containing %q: %s", level, substring, found[0].Message())
+	}
+}
+
+// AssertField fails the test unless an entry with substring in its message has the field key with the given
+// value, compared by its formatted text, e.g. AssertField(t, "order created", "order_id", 42).
+func (s *Sink) AssertField(t T, substring string, key string, value interface{}) {
+	t.Helper()
+	for _, entry := range s.Entries() {
+		if !strings.Contains(entry.Message(), substring) {
+			continue
+		}
+		if v, ok := entry.Fields()[key]; ok && fmt.Sprint(v) == fmt.Sprint(value) {
+			return
+		}
+	}
+	t.Errorf("no entry containing %q has %s=%v; entries:\n%s", substring, key, value, s.dump())
+}
+
+//This method waits for the logger feeding the sink, if known, to hand over the entries logged so far.
+func (s *Sink) sync() {
+	if s.flush != nil {
+		s.flush()
+	}
+}
+
+//This method lists the recorded entries, one per line, for failure messages.
+func (s *Sink) dump() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var b strings.Builder
+	for _, entry := range s.entries {
+		fmt.Fprintf(&b, "\t[%s] %s\n", entry.Level(), entry.Message())
+	}
+	if b.Len() == 0 {
+		return "\t(none)\n"
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+// Package awsv2 implements the cloudwatch sink's Client with the AWS SDK for Go v2.
+package awsv2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/smithy-go"
+	"github.com/shyamgrover/go-lite-logger/sinks/cloudwatch"
+)
+
+// Client sends events with a cloudwatchlogs.Client.
+type Client struct {
+	client *cloudwatchlogs.Client
+}
+
+// NewClient returns a Client using client.
+func NewClient(client *cloudwatchlogs.Client) *Client {
+	return &Client{client: client}
+}
+
+// PutLogEvents implements cloudwatch.Client.
+func (c *Client) PutLogEvents(group string, stream string, events []cloudwatch.Event, sequenceToken *string) (*string, error) {
+	input := &cloudwatchlogs.PutLogEventsInput{
+		LogGroupName:  aws.String(group),
+		LogStreamName: aws.String(stream),
+		LogEvents:     make([]types.InputLogEvent, len(events)),
+		SequenceToken: sequenceToken,
+	}
+	for i, event := range events {
+		input.LogEvents[i] = types.InputLogEvent{
+			Message:   aws.String(event.Message),
+			Timestamp: aws.Int64(event.Timestamp.UnixNano() / 1e6),
+		}
+	}
+	output, err := c.client.PutLogEvents(context.Background(), input)
+	if err == nil {
+		return output.NextSequenceToken, nil
+	}
+	var tokenErr *types.InvalidSequenceTokenException
+	if errors.As(err, &tokenErr) {
+		return nil, &cloudwatch.InvalidSequenceTokenError{ExpectedToken: tokenErr.ExpectedSequenceToken}
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "ThrottlingException" {
+		return nil, fmt.Errorf("%w: %v", cloudwatch.ErrThrottled, err)
+	}
+	return nil, err
+}
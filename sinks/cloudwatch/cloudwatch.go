@@ -0,0 +1,205 @@
+// Package cloudwatch provides a sink shipping entries to an AWS CloudWatch Logs group and stream in batches. It
+// talks to CloudWatch through the Client interface; the awsv2 sub package implements it with the AWS SDK for Go
+// v2, so only programs using it depend on the SDK:
+//
+//	sink, err := cloudwatch.New(awsv2.NewClient(cloudwatchlogs.NewFromConfig(cfg)), cloudwatch.Config{
+//		Group:  "/app/billing",
+//		Stream: hostname,
+//	})
+//	...
+//	myLogger.AddSink("cloudwatch", sink)
+package cloudwatch
+
+import (
+	"errors"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limits of a PutLogEvents call.
+const (
+	MaxBatchEvents = 10000       //events per call
+	MaxBatchBytes  = 1048576     //bytes per call, counting every message plus EventOverhead
+	EventOverhead  = 26          //bytes counted for every event on top of its message
+	MaxEventBytes  = 262144 - 26 //bytes of a single message
+	maxBatchSpan   = 24 * time.Hour
+)
+
+// Event is one log event.
+type Event struct {
+	Timestamp time.Time
+	Message   string
+}
+
+// Client sends events to CloudWatch Logs. PutLogEvents returns the next sequence token, which is passed to the
+// next call. It should return an *InvalidSequenceTokenError when CloudWatch rejects the token and an error
+// wrapping ErrThrottled when the call was throttled.
+type Client interface {
+	PutLogEvents(group string, stream string, events []Event, sequenceToken *string) (*string, error)
+}
+
+// InvalidSequenceTokenError reports a rejected sequence token together with the token CloudWatch expects.
+type InvalidSequenceTokenError struct {
+	ExpectedToken *string
+}
+
+func (e *InvalidSequenceTokenError) Error() string {
+	return "cloudwatch: invalid sequence token"
+}
+
+// ErrThrottled is wrapped by errors of calls that were throttled.
+var ErrThrottled = errors.New("cloudwatch: throttled")
+
+// Config configures a CloudWatch Logs sink.
+type Config struct {
+	Group         string              //log group, which must exist
+	Stream        string              //log stream, which must exist
+	FlushInterval time.Duration       //longest time an entry waits for a batch to be sent, 5 seconds by default
+	Formatter     logWriter.Formatter //renders the messages, logWriter.JSONFormatter by default
+	MaxRetries    int                 //attempts after throttling or a rejected token before a batch fails, 5 by default
+}
+
+// Sink collects entries into batches within the PutLogEvents limits and sends a batch when it is full, when the
+// flush interval passed, and on Flush and Close. Throttled calls are retried with exponential backoff and
+// rejected sequence tokens are replaced by the expected one; a batch that still fails is dropped.
+type Sink struct {
+	lock    sync.Mutex    //guards the fields below and serializes calls
+	client  Client        //CloudWatch Logs client
+	config  Config        //configuration with defaults applied
+	batch   []Event       //events of the next call
+	bytes   int           //size of batch as counted by CloudWatch
+	token   *string       //sequence token for the next call
+	dropped uint64        //events dropped after failed calls
+	closed  bool          //set by Close
+	stop    chan struct{} //stops the flush timer
+	stopped chan struct{} //closed when the flush timer returned
+	sleep   func(time.Duration)
+}
+
+// New returns a CloudWatch Logs sink.
+func New(client Client, config Config) (*Sink, error) {
+	if len(config.Group) == 0 || len(config.Stream) == 0 {
+		return nil, fmt.Errorf("cloudwatch: group and stream are required")
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 5 * time.Second
+	}
+	if config.Formatter == nil {
+		config.Formatter = logWriter.JSONFormatter{}
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = 5
+	}
+	sink := &Sink{client: client, config: config, stop: make(chan struct{}), stopped: make(chan struct{}),
+		sleep: time.Sleep}
+	go sink.flushPeriodically()
+	return sink, nil
+}
+
+//This method sends the batch every flush interval until the sink is closed.
+func (s *Sink) flushPeriodically() {
+	defer close(s.stopped)
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Flush()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Dropped returns the number of events dropped after failed calls.
+func (s *Sink) Dropped() uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.dropped
+}
+
+// WriteEntry implements logWriter.EntrySink. Messages longer than CloudWatch accepts are truncated.
+func (s *Sink) WriteEntry(entry logWriter.Entry) error {
+	data, err := s.config.Formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	message := strings.TrimRight(string(data), "\n")
+	if len(message) > MaxEventBytes {
+		message = message[:MaxEventBytes]
+	}
+	event := Event{Timestamp: entry.Time(), Message: message}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return fmt.Errorf("cloudwatch: sink is closed")
+	}
+	size := len(message) + EventOverhead
+	if len(s.batch) > 0 && (len(s.batch) == MaxBatchEvents || s.bytes+size > MaxBatchBytes ||
+		event.Timestamp.Sub(s.batch[0].Timestamp) >= maxBatchSpan) {
+		err = s.send()
+	}
+	s.batch = append(s.batch, event)
+	s.bytes += size
+	return err
+}
+
+// Flush implements logWriter.Flusher: it sends the batch now.
+func (s *Sink) Flush() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.send()
+}
+
+//This method sends the batch, retrying throttled calls with backoff and calls with a rejected sequence token with
+// the expected token. It must be called with lock held.
+func (s *Sink) send() error {
+	if len(s.batch) == 0 {
+		return nil
+	}
+	events := s.batch
+	s.batch, s.bytes = nil, 0
+	backoff := 200 * time.Millisecond
+	var err error
+	for attempt := 0; attempt <= s.config.MaxRetries; attempt++ {
+		var next *string
+		next, err = s.client.PutLogEvents(s.config.Group, s.config.Stream, events, s.token)
+		if err == nil {
+			s.token = next
+			return nil
+		}
+		var tokenErr *InvalidSequenceTokenError
+		switch {
+		case errors.As(err, &tokenErr):
+			s.token = tokenErr.ExpectedToken
+		case errors.Is(err, ErrThrottled):
+			s.sleep(backoff)
+			backoff *= 2
+		default:
+			attempt = s.config.MaxRetries
+		}
+	}
+	s.dropped += uint64(len(events))
+	return err
+}
+
+// Close implements logWriter.EntrySink. It stops the flush timer and sends the remaining entries.
+func (s *Sink) Close() error {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.lock.Unlock()
+	close(s.stop)
+	<-s.stopped
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.send()
+}
@@ -0,0 +1,112 @@
+// Package console provides a sink writing entries to a terminal, with the level color-coded. It is meant for
+// local development, next to the log file:
+//
+//	myLogger, err := logger.New(logger.WithFile("app.log"), logger.WithSink("console", console.New(os.Stderr)))
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+//ANSI escape sequences.
+const (
+	reset  = "\x1b[0m"
+	red    = "\x1b[31m"
+	yellow = "\x1b[33m"
+	cyan   = "\x1b[36m"
+	gray   = "\x1b[90m"
+)
+
+//color and label of every level.
+var levels = map[logWriter.Level]struct{ color, label string }{
+	logWriter.PanicLevel: {red, "PANIC"},
+	logWriter.FatalLevel: {red, "FATAL"},
+	logWriter.ErrorLevel: {red, "ERROR"},
+	logWriter.WarnLevel:  {yellow, "WARN "},
+	logWriter.InfoLevel:  {cyan, "INFO "},
+	logWriter.DebugLevel: {gray, "DEBUG"},
+	logWriter.TraceLevel: {gray, "TRACE"},
+}
+
+// Sink writes entries as "15:04:05.000 LEVEL message key=value..." lines, with the level colored when
+// colors are enabled. Closing it does not close the underlying writer, so os.Stderr can be used safely.
+type Sink struct {
+	lock       sync.Mutex //serializes writes
+	out        io.Writer  //terminal the entries are written to
+	color      bool       //whether to color the level
+	timeLayout string     //layout of the timestamp
+}
+
+// New returns a sink writing to out. Colors are enabled if out is a terminal and the NO_COLOR environment
+// variable is not set; SetColor overrides this.
+func New(out io.Writer) *Sink {
+	return &Sink{out: out, color: isTerminal(out) && len(os.Getenv("NO_COLOR")) == 0, timeLayout: "15:04:05.000"}
+}
+
+// SetColor enables or disables colors.
+func (s *Sink) SetColor(color bool) {
+	s.lock.Lock()
+	s.color = color
+	s.lock.Unlock()
+}
+
+// SetTimeLayout sets the layout of the timestamp, "15:04:05.000" by default.
+func (s *Sink) SetTimeLayout(layout string) {
+	s.lock.Lock()
+	s.timeLayout = layout
+	s.lock.Unlock()
+}
+
+// WriteEntry implements logWriter.EntrySink.
+func (s *Sink) WriteEntry(entry logWriter.Entry) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	level, ok := levels[entry.Level()]
+	if !ok {
+		level.color = entry.Level().Color()
+		level.label = fmt.Sprintf("%-5s", strings.ToUpper(entry.Level().String()))
+	}
+	var b bytes.Buffer
+	b.WriteString(entry.Time().Format(s.timeLayout))
+	b.WriteByte(' ')
+	if s.color && len(level.color) > 0 {
+		b.WriteString(level.color + level.label + reset)
+	} else {
+		b.WriteString(level.label)
+	}
+	b.WriteByte(' ')
+	b.WriteString(entry.Message())
+	for _, field := range entry.SortedFields() {
+		b.WriteByte(' ')
+		if s.color {
+			b.WriteString(gray + field.Key + "=" + reset)
+		} else {
+			b.WriteString(field.Key + "=")
+		}
+		b.WriteString(field.Value)
+	}
+	b.WriteByte('\n')
+	_, err := s.out.Write(b.Bytes())
+	return err
+}
+
+// Close implements logWriter.EntrySink. It leaves the underlying writer open.
+func (s *Sink) Close() error {
+	return nil
+}
+
+//This method reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
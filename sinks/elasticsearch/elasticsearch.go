@@ -0,0 +1,288 @@
+// Package elasticsearch provides a sink indexing entries into Elasticsearch through the _bulk API. Every entry
+// becomes a document with "@timestamp", "level", "message" and its fields, in an index named after a template
+// such as "app-logs-{2006.01.02}":
+//
+//	sink, err := elasticsearch.New(elasticsearch.Config{
+//		URL:   "http://elasticsearch:9200",
+//		Index: "app-logs-{2006.01.02}",
+//	})
+//	...
+//	myLogger.AddSink("elasticsearch", sink)
+package elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config configures an Elasticsearch sink.
+type Config struct {
+	URL           string        //base URL of the cluster, e.g. http://elasticsearch:9200
+	Index         string        //index name template, see IndexName
+	BatchSize     int           //documents per bulk request, 500 by default
+	FlushInterval time.Duration //longest time an entry waits for a bulk request, 1 second by default
+	MaxRetries    int           //retries of rejected documents before they are dropped, 5 by default
+	Backoff       time.Duration //wait before the first retry, doubled for every further retry, 500ms by default
+	Username      string        //basic auth user, if set
+	Password      string        //basic auth password
+	APIKey        string        //sent as "Authorization: ApiKey <key>", if set
+	Client        *http.Client  //HTTP client, one with a 10 second timeout by default
+}
+
+// IndexName returns the index of a document created at t: every part of template in braces is a time layout
+// that is replaced by t in UTC, e.g. "app-logs-{2006.01.02}" gives "app-logs-2024.05.01".
+func IndexName(template string, t time.Time) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		end := strings.IndexByte(template, '}')
+		if start < 0 || end < start {
+			b.WriteString(template)
+			return b.String()
+		}
+		b.WriteString(template[:start])
+		b.WriteString(t.UTC().Format(template[start+1 : end]))
+		template = template[end+1:]
+	}
+}
+
+// Sink batches entries and indexes them when a batch is full, when the flush interval passed, and on Flush and
+// Close. Requests rejected with 429 Too Many Requests, and single documents rejected with 429, are retried with
+// exponential backoff; documents that still fail, or fail for another reason, are dropped.
+type Sink struct {
+	lock    sync.Mutex    //guards the batch and serializes requests
+	config  Config        //configuration with defaults applied
+	batch   []document    //documents waiting for a request
+	dropped uint64        //documents dropped after failed requests
+	stop    chan struct{} //stops the flush timer
+	stopped chan struct{} //closed when the flush timer returned
+	closed  bool          //set by Close
+}
+
+//document is one entry waiting to be indexed.
+type document struct {
+	index  string //name of the index
+	source []byte //JSON document
+}
+
+// New returns an Elasticsearch sink for the given configuration.
+func New(config Config) (*Sink, error) {
+	if len(config.URL) == 0 || len(config.Index) == 0 {
+		return nil, fmt.Errorf("elasticsearch: URL and index are required")
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 500
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = 5
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = 500 * time.Millisecond
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	sink := &Sink{config: config, stop: make(chan struct{}), stopped: make(chan struct{})}
+	go sink.flushPeriodically()
+	return sink, nil
+}
+
+//This method indexes the batch every flush interval until the sink is closed.
+func (s *Sink) flushPeriodically() {
+	defer close(s.stopped)
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Flush()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Dropped returns the number of documents dropped after failed requests.
+func (s *Sink) Dropped() uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.dropped
+}
+
+// WriteEntry implements logWriter.EntrySink. It indexes the batch when it is full.
+func (s *Sink) WriteEntry(entry logWriter.Entry) error {
+	logged := entry.Time()
+	source := newSource(entry, logged)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return fmt.Errorf("elasticsearch: sink is closed")
+	}
+	s.batch = append(s.batch, document{index: IndexName(s.config.Index, logged), source: source})
+	if len(s.batch) >= s.config.BatchSize {
+		return s.index()
+	}
+	return nil
+}
+
+//This method returns the JSON document of an entry. Errors are stored as their message and values that cannot
+// be marshalled as their fmt representation; a field named like a fixed key is stored as "fields.<key>".
+func newSource(entry logWriter.Entry, now time.Time) []byte {
+	doc := map[string]interface{}{
+		"@timestamp": now.UTC().Format(time.RFC3339Nano),
+		"level":      entry.Level().String(),
+		"message":    entry.Message(),
+	}
+	for key, value := range entry.Fields() {
+		if _, fixed := doc[key]; fixed {
+			key = "fields." + key
+		}
+		if err, ok := value.(error); ok {
+			if _, isMarshaler := value.(json.Marshaler); !isMarshaler {
+				value = err.Error()
+			}
+		}
+		if _, err := json.Marshal(value); err != nil {
+			value = fmt.Sprint(value)
+		}
+		doc[key] = value
+	}
+	source, _ := json.Marshal(doc)
+	return source
+}
+
+// Flush implements logWriter.Flusher: it indexes the batch now.
+func (s *Sink) Flush() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.index()
+}
+
+//This method indexes the batch, retrying rejected documents with backoff. It must be called with lock held.
+func (s *Sink) index() error {
+	docs := s.batch
+	s.batch = nil
+	backoff := s.config.Backoff
+	var err error
+	for attempt := 0; len(docs) > 0; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		var retry []document
+		retry, err = s.send(docs)
+		if err != nil && len(retry) == 0 || attempt == s.config.MaxRetries {
+			s.dropped += uint64(len(retry))
+			return err
+		}
+		docs = retry
+	}
+	return err
+}
+
+//bulkResponse is the part of a _bulk response the sink looks at.
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+//This method sends one bulk request and returns the documents to retry. Documents that failed for good are
+// counted as dropped and reported in the error.
+func (s *Sink) send(docs []document) ([]document, error) {
+	var body bytes.Buffer
+	for _, doc := range docs {
+		action, _ := json.Marshal(map[string]map[string]string{"index": {"_index": doc.index}})
+		body.Write(action)
+		body.WriteByte('\n')
+		body.Write(doc.source)
+		body.WriteByte('\n')
+	}
+	request, err := http.NewRequest(http.MethodPost, strings.TrimRight(s.config.URL, "/")+"/_bulk", &body)
+	if err != nil {
+		s.dropped += uint64(len(docs))
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-ndjson")
+	if len(s.config.APIKey) > 0 {
+		request.Header.Set("Authorization", "ApiKey "+s.config.APIKey)
+	} else if len(s.config.Username) > 0 {
+		request.SetBasicAuth(s.config.Username, s.config.Password)
+	}
+	response, err := s.config.Client.Do(request)
+	if err != nil {
+		s.dropped += uint64(len(docs))
+		return nil, fmt.Errorf("elasticsearch: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusTooManyRequests {
+		return docs, fmt.Errorf("elasticsearch: bulk request rejected with %s", response.Status)
+	}
+	if response.StatusCode/100 != 2 {
+		message, _ := ioutil.ReadAll(io.LimitReader(response.Body, 512))
+		s.dropped += uint64(len(docs))
+		return nil, fmt.Errorf("elasticsearch: bulk request failed with %s: %s", response.Status,
+			bytes.TrimSpace(message))
+	}
+	var result bulkResponse
+	if err = json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("elasticsearch: reading bulk response: %v", err)
+	}
+	if !result.Errors {
+		return nil, nil
+	}
+	var retry []document
+	failed := 0
+	for i, item := range result.Items {
+		for _, outcome := range item {
+			switch {
+			case outcome.Status == http.StatusTooManyRequests && i < len(docs):
+				retry = append(retry, docs[i])
+			case outcome.Status/100 != 2:
+				if failed == 0 {
+					err = fmt.Errorf("elasticsearch: indexing failed: %s: %s", outcome.Error.Type,
+						outcome.Error.Reason)
+				}
+				failed++
+			}
+		}
+	}
+	s.dropped += uint64(failed)
+	if err == nil && len(retry) > 0 {
+		err = fmt.Errorf("elasticsearch: %d documents rejected with 429", len(retry))
+	}
+	return retry, err
+}
+
+// Close implements logWriter.EntrySink. It stops the flush timer and indexes the remaining entries.
+func (s *Sink) Close() error {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.lock.Unlock()
+	close(s.stop)
+	<-s.stopped
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.index()
+}
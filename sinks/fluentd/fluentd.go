@@ -0,0 +1,136 @@
+// Package fluentd provides a sink speaking the Fluentd forward protocol, so that entries can be fed to a
+// fluentd or Fluent Bit forward input:
+//
+//	sink := fluentd.New(fluentd.Config{Address: "fluentd:24224", Tag: "app.billing", RequireAck: true})
+//	myLogger.AddSink("fluentd", sink)
+//
+// Every entry is sent as one event in Message mode, with a record holding "message", "level" and the entry
+// fields. With RequireAck the sink waits for the server to acknowledge every event and resends it on a new
+// connection if the acknowledgement does not arrive, so that events are delivered at least once.
+package fluentd
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"net"
+	"sync"
+	"time"
+)
+
+// Config configures a Fluentd sink.
+type Config struct {
+	Address    string        //host:port of the forward input, localhost:24224 by default
+	Tag        string        //tag of the events, used for routing in fluentd
+	RequireAck bool          //wait for an acknowledgement of every event
+	Timeout    time.Duration //timeout of connecting, writing and waiting for an acknowledgement, 5s by default
+	Retries    int           //further attempts, each on a new connection, before an event fails; 2 by default
+}
+
+// Sink sends entries to a forward input over TCP.
+type Sink struct {
+	lock   sync.Mutex    //serializes events
+	config Config        //configuration with defaults applied
+	conn   net.Conn      //current connection, nil if there is none
+	reader *bufio.Reader //reads acknowledgements from conn
+}
+
+// New returns a Fluentd sink. The connection is made with the first entry and re-made after failures.
+func New(config Config) *Sink {
+	if len(config.Address) == 0 {
+		config.Address = "localhost:24224"
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	if config.Retries <= 0 {
+		config.Retries = 2
+	}
+	return &Sink{config: config}
+}
+
+// WriteEntry implements logWriter.EntrySink.
+func (s *Sink) WriteEntry(entry logWriter.Entry) error {
+	record := make(map[string]interface{}, len(entry.Fields())+2)
+	for key, value := range entry.Fields() {
+		record[key] = value
+	}
+	record["message"] = entry.Message()
+	record["level"] = entry.Level().String()
+
+	var chunk string
+	options := map[string]interface{}{}
+	if s.config.RequireAck {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return err
+		}
+		chunk = base64.StdEncoding.EncodeToString(id)
+		options["chunk"] = chunk
+	}
+	event := appendArrayHeader(nil, 4)
+	event = appendString(event, s.config.Tag)
+	event = appendEventTime(event, entry.Time())
+	event = appendValue(event, record)
+	event = appendValue(event, options)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var err error
+	for attempt := 0; attempt <= s.config.Retries; attempt++ {
+		if err = s.send(event, chunk); err == nil {
+			return nil
+		}
+		s.disconnect()
+	}
+	return fmt.Errorf("fluentd: %v", err)
+}
+
+//This method sends one event and waits for its acknowledgement if chunk is set. It must be called with lock
+// held.
+func (s *Sink) send(event []byte, chunk string) error {
+	if s.conn == nil {
+		conn, err := net.DialTimeout("tcp", s.config.Address, s.config.Timeout)
+		if err != nil {
+			return err
+		}
+		s.conn, s.reader = conn, bufio.NewReader(conn)
+	}
+	s.conn.SetDeadline(time.Now().Add(s.config.Timeout))
+	if _, err := s.conn.Write(event); err != nil {
+		return err
+	}
+	if len(chunk) == 0 {
+		return nil
+	}
+	response, err := readValue(s.reader)
+	if err != nil {
+		return err
+	}
+	if ack, _ := response.(map[string]interface{}); ack == nil || ack["ack"] != chunk {
+		return fmt.Errorf("unexpected acknowledgement %v", response)
+	}
+	return nil
+}
+
+//This method drops the current connection. It must be called with lock held.
+func (s *Sink) disconnect() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn, s.reader = nil, nil
+	}
+}
+
+// Close implements logWriter.EntrySink.
+func (s *Sink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn, s.reader = nil, nil
+	return err
+}
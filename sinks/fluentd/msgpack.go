@@ -0,0 +1,243 @@
+package fluentd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+//This method appends the MessagePack encoding of value. Values of types MessagePack has no counterpart for are
+// encoded as their fmt representation, errors as their message.
+func appendValue(b []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case bool:
+		if v {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case int:
+		return appendInt(b, int64(v))
+	case int8:
+		return appendInt(b, int64(v))
+	case int16:
+		return appendInt(b, int64(v))
+	case int32:
+		return appendInt(b, int64(v))
+	case int64:
+		return appendInt(b, v)
+	case uint:
+		return appendUint(b, uint64(v))
+	case uint8:
+		return appendUint(b, uint64(v))
+	case uint16:
+		return appendUint(b, uint64(v))
+	case uint32:
+		return appendUint(b, uint64(v))
+	case uint64:
+		return appendUint(b, v)
+	case float32:
+		return appendFloat(b, float64(v))
+	case float64:
+		return appendFloat(b, v)
+	case string:
+		return appendString(b, v)
+	case []byte:
+		return appendBinary(b, v)
+	case time.Time:
+		return appendEventTime(b, v)
+	case []interface{}:
+		b = appendArrayHeader(b, len(v))
+		for _, item := range v {
+			b = appendValue(b, item)
+		}
+		return b
+	case map[string]interface{}:
+		b = appendMapHeader(b, len(v))
+		for key, item := range v {
+			b = appendString(b, key)
+			b = appendValue(b, item)
+		}
+		return b
+	case error:
+		return appendString(b, v.Error())
+	}
+	return appendString(b, fmt.Sprint(value))
+}
+
+func appendInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return appendUint(b, uint64(v))
+	case v >= -32:
+		return append(b, byte(v))
+	case v >= math.MinInt8:
+		return append(b, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(v))
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(v))
+}
+
+func appendUint(b []byte, v uint64) []byte {
+	switch {
+	case v <= 0x7f:
+		return append(b, byte(v))
+	case v <= math.MaxUint8:
+		return append(b, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(v))
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xcf), v)
+}
+
+func appendFloat(b []byte, v float64) []byte {
+	return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(v))
+}
+
+func appendString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n <= 31:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(n))
+	}
+	return append(b, s...)
+}
+
+func appendBinary(b []byte, data []byte) []byte {
+	switch n := len(data); {
+	case n <= math.MaxUint8:
+		b = append(b, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xc5), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xc6), uint32(n))
+	}
+	return append(b, data...)
+}
+
+func appendArrayHeader(b []byte, n int) []byte {
+	switch {
+	case n <= 15:
+		return append(b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xdc), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(b, 0xdd), uint32(n))
+}
+
+func appendMapHeader(b []byte, n int) []byte {
+	switch {
+	case n <= 15:
+		return append(b, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xde), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(b, 0xdf), uint32(n))
+}
+
+//This method appends t as the EventTime extension of the forward protocol: fixext 8 of type 0 holding the
+// seconds and nanoseconds as big-endian 32-bit integers.
+func appendEventTime(b []byte, t time.Time) []byte {
+	b = append(b, 0xd7, 0x00)
+	b = binary.BigEndian.AppendUint32(b, uint32(t.Unix()))
+	return binary.BigEndian.AppendUint32(b, uint32(t.Nanosecond()))
+}
+
+//This method reads one MessagePack value, as far as needed for ack responses: maps, strings, binaries,
+// integers, nil and booleans. Maps are returned as map[string]interface{}.
+func readValue(r *bufio.Reader) (interface{}, error) {
+	head, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case head <= 0x7f:
+		return int64(head), nil
+	case head >= 0xe0:
+		return int64(int8(head)), nil
+	case head&0xf0 == 0x80:
+		return readMap(r, int(head&0x0f))
+	case head&0xe0 == 0xa0:
+		return readString(r, int(head&0x1f))
+	}
+	switch head {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xd9:
+		n, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		return readString(r, int(n))
+	case 0xc5, 0xda:
+		n, err := readUint(r, 2)
+		if err != nil {
+			return nil, err
+		}
+		return readString(r, int(n))
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n, err := readUint(r, 1<<(head-0xcc))
+		return int64(n), err
+	case 0xde:
+		n, err := readUint(r, 2)
+		if err != nil {
+			return nil, err
+		}
+		return readMap(r, int(n))
+	}
+	return nil, fmt.Errorf("unsupported MessagePack type 0x%02x in response", head)
+}
+
+func readUint(r *bufio.Reader, size int) (uint64, error) {
+	var v uint64
+	for i := 0; i < size; i++ {
+		c, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+func readString(r *bufio.Reader, n int) (string, error) {
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func readMap(r *bufio.Reader, n int) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		key, err := readValue(r)
+		if err != nil {
+			return nil, err
+		}
+		value, err := readValue(r)
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(key)] = value
+	}
+	return m, nil
+}
@@ -0,0 +1,220 @@
+// Package gelf ships entries to Graylog in the Graylog Extended Log Format (GELF 1.1), over chunked UDP or over
+// TCP:
+//
+//	sink, err := gelf.NewUDPSink("graylog:12201")
+//	...
+//	myLogger.AddSink("graylog", sink)
+//
+// Levels map to syslog severities, the time the entry was formatted becomes the timestamp and entry fields
+// become additional fields, prefixed with an underscore.
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"github.com/shyamgrover/go-lite-logger/sinks/network"
+	"net"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Formatter renders entries as GELF 1.1 messages. It can also be used with a file or a plain sink, in which case
+// messages are written one per line.
+type Formatter struct {
+	Host           string //host field, the name of this machine if empty
+	NullTerminated bool   //end messages with a null byte, as GELF over TCP requires, instead of a newline
+}
+
+//characters not allowed in the names of additional fields.
+var invalidFieldChars = regexp.MustCompile(`[^\w.\-]`)
+
+//name of this machine, looked up once by localHost.
+var (
+	hostOnce sync.Once
+	hostName string
+)
+
+//Util method that returns the name of this machine as reported by os.Hostname, which it calls only once.
+func localHost() string {
+	hostOnce.Do(func() {
+		hostName, _ = os.Hostname()
+	})
+	return hostName
+}
+
+//This method returns the GELF level, the syslog severity, of a level.
+func severity(level logWriter.Level) int {
+	switch level.Base() {
+	case logWriter.PanicLevel, logWriter.FatalLevel:
+		return 2
+	case logWriter.ErrorLevel:
+		return 3
+	case logWriter.WarnLevel:
+		return 4
+	case logWriter.InfoLevel:
+		return 6
+	}
+	return 7
+}
+
+// Format implements logWriter.Formatter.
+func (f Formatter) Format(entry logWriter.Entry) ([]byte, error) {
+	data, err := f.message(entry, entry.Time())
+	if err != nil {
+		return nil, err
+	}
+	if f.NullTerminated {
+		return append(data, 0), nil
+	}
+	return append(data, '\n'), nil
+}
+
+//This method returns the GELF message of the entry, without terminator.
+func (f Formatter) message(entry logWriter.Entry, now time.Time) ([]byte, error) {
+	host := f.Host
+	if len(host) == 0 {
+		host = localHost()
+	}
+	message := map[string]interface{}{
+		"version":       "1.1",
+		"host":          host,
+		"short_message": entry.Message(),
+		"timestamp":     float64(now.UnixNano()/int64(time.Microsecond)) / 1e6,
+		"level":         severity(entry.Level()),
+	}
+	for key, value := range entry.Fields() {
+		key = invalidFieldChars.ReplaceAllString(key, "_")
+		if key == "id" {
+			key = "field_id" //_id is reserved
+		}
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		if _, err := json.Marshal(value); err != nil {
+			value = fmt.Sprint(value)
+		}
+		message["_"+key] = value
+	}
+	return json.Marshal(message)
+}
+
+// NewTCPSink returns a sink sending null-terminated GELF messages to address over TCP, with the reconnection and
+// buffering of network.Sink.
+func NewTCPSink(address string) *network.Sink {
+	return network.New("tcp", address, Formatter{Host: localHost(), NullTerminated: true})
+}
+
+//chunking limits of GELF over UDP.
+const (
+	defaultChunkSize = 1420
+	chunkHeaderSize  = 12
+	maxChunks        = 128
+)
+
+// UDPSink sends GELF messages over UDP, gzip-compressed and split into chunks when they exceed the chunk size.
+type UDPSink struct {
+	lock      sync.Mutex //serializes writes
+	conn      net.Conn   //UDP socket
+	formatter Formatter  //renders the messages
+	chunkSize int        //maximum datagram size
+	compress  bool       //gzip messages before sending
+}
+
+// NewUDPSink returns a sink sending to the GELF UDP input at address, with gzip compression and chunks of
+// 1420 bytes.
+func NewUDPSink(address string) (*UDPSink, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	return &UDPSink{conn: conn, formatter: Formatter{Host: localHost()}, chunkSize: defaultChunkSize, compress: true}, nil
+}
+
+// SetChunkSize sets the maximum datagram size, e.g. 8192 on a LAN. Sizes that leave no room for data after the
+// 12 byte chunk header are replaced by the default of 1420.
+func (s *UDPSink) SetChunkSize(size int) {
+	if size <= chunkHeaderSize {
+		size = defaultChunkSize
+	}
+	s.lock.Lock()
+	s.chunkSize = size
+	s.lock.Unlock()
+}
+
+// SetCompression enables or disables gzip compression.
+func (s *UDPSink) SetCompression(compress bool) {
+	s.lock.Lock()
+	s.compress = compress
+	s.lock.Unlock()
+}
+
+// SetHost sets the host field of the messages, the name of this machine by default.
+func (s *UDPSink) SetHost(host string) {
+	s.lock.Lock()
+	s.formatter.Host = host
+	s.lock.Unlock()
+}
+
+// WriteEntry implements logWriter.EntrySink.
+func (s *UDPSink) WriteEntry(entry logWriter.Entry) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	data, err := s.formatter.message(entry, entry.Time())
+	if err != nil {
+		return err
+	}
+	if s.compress {
+		var compressed bytes.Buffer
+		zipper := gzip.NewWriter(&compressed)
+		zipper.Write(data)
+		zipper.Close()
+		data = compressed.Bytes()
+	}
+	if len(data) <= s.chunkSize {
+		_, err = s.conn.Write(data)
+		return err
+	}
+	return s.writeChunked(data)
+}
+
+//This method sends a message in chunks: every chunk starts with the magic bytes 0x1e 0x0f, the 8 byte message
+// id, the chunk's sequence number and the number of chunks. It must be called with lock held.
+func (s *UDPSink) writeChunked(data []byte) error {
+	payload := s.chunkSize - chunkHeaderSize
+	count := (len(data) + payload - 1) / payload
+	if count > maxChunks {
+		return fmt.Errorf("GELF message of %d bytes needs more than %d chunks", len(data), maxChunks)
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	chunk := make([]byte, 0, s.chunkSize)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * payload
+		if end > len(data) {
+			end = len(data)
+		}
+		chunk = append(chunk[:0], 0x1e, 0x0f)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, data[i*payload:end]...)
+		if _, err := s.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements logWriter.EntrySink.
+func (s *UDPSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.conn.Close()
+}
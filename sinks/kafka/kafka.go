@@ -0,0 +1,94 @@
+// Package kafka provides a sink publishing entries to Kafka topics. It does not implement the Kafka protocol
+// itself but hands messages to a Producer, so that it works with the client a team already uses; the kafkago
+// sub package adapts github.com/segmentio/kafka-go:
+//
+//	writer := &kafka.Writer{Addr: kafka.TCP("broker:9092"), Async: true}
+//	sink, err := kafkasink.New(kafkago.NewProducer(writer), kafkasink.Config{
+//		Topic:       "logs",
+//		LevelTopics: map[logWriter.Level]string{logWriter.ErrorLevel: "logs-errors"},
+//		KeyFields:   []string{"tenant"},
+//	})
+//	...
+//	myLogger.AddSink("kafka", sink)
+package kafka
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"strings"
+)
+
+// Producer publishes messages to Kafka. Implementations may batch and send asynchronously; Produce is only
+// called from one goroutine at a time.
+type Producer interface {
+	Produce(topic string, key []byte, value []byte) error
+	Close() error
+}
+
+// Config configures a Kafka sink.
+type Config struct {
+	Topic       string                     //topic of all entries without a level topic
+	LevelTopics map[logWriter.Level]string //topics of entries of particular levels
+	KeyFields   []string                   //fields whose values, joined by "/", form the message key
+	Formatter   logWriter.Formatter        //renders the message values, logWriter.JSONFormatter by default
+}
+
+// Sink publishes every entry as one message.
+type Sink struct {
+	producer Producer //client publishing the messages
+	config   Config   //configuration with defaults applied
+}
+
+// New returns a sink publishing through producer. Entries without any of the key fields get no key, so
+// Kafka spreads them over the partitions; entries with the same key keep their order.
+func New(producer Producer, config Config) (*Sink, error) {
+	if len(config.Topic) == 0 && len(config.LevelTopics) == 0 {
+		return nil, fmt.Errorf("kafka: no topic given")
+	}
+	if config.Formatter == nil {
+		config.Formatter = logWriter.JSONFormatter{}
+	}
+	return &Sink{producer: producer, config: config}, nil
+}
+
+// WriteEntry implements logWriter.EntrySink.
+func (s *Sink) WriteEntry(entry logWriter.Entry) error {
+	topic, ok := s.config.LevelTopics[entry.Level()]
+	if !ok {
+		topic = s.config.Topic
+	}
+	if len(topic) == 0 {
+		return nil //no topic for this level
+	}
+	value, err := s.config.Formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	return s.producer.Produce(topic, s.key(entry), []byte(strings.TrimRight(string(value), "\n")))
+}
+
+//This method returns the message key of an entry, nil if it has none of the key fields.
+func (s *Sink) key(entry logWriter.Entry) []byte {
+	fields := entry.Fields()
+	var parts []string
+	found := false
+	for _, name := range s.config.KeyFields {
+		value, ok := fields[name]
+		if ok {
+			found = true
+			parts = append(parts, fmt.Sprint(value))
+		} else {
+			parts = append(parts, "")
+		}
+	}
+	if !found {
+		return nil
+	}
+	return []byte(strings.Join(parts, "/"))
+}
+
+// Close implements logWriter.EntrySink. It closes the producer, which should deliver the messages it still
+// holds.
+func (s *Sink) Close() error {
+	return s.producer.Close()
+}
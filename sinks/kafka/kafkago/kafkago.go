@@ -0,0 +1,29 @@
+// Package kafkago adapts a github.com/segmentio/kafka-go Writer to the kafka sink's Producer interface. It is
+// kept apart from the sink so that only programs using it depend on kafka-go.
+package kafkago
+
+import (
+	"context"
+	"github.com/segmentio/kafka-go"
+)
+
+// Producer publishes messages with a kafka-go Writer. The Writer must not have a Topic set, since the sink sets
+// the topic of every message; set Async on it to let kafka-go batch in the background.
+type Producer struct {
+	writer *kafka.Writer
+}
+
+// NewProducer returns a Producer publishing with writer.
+func NewProducer(writer *kafka.Writer) *Producer {
+	return &Producer{writer: writer}
+}
+
+// Produce implements kafka.Producer of the sink package.
+func (p *Producer) Produce(topic string, key []byte, value []byte) error {
+	return p.writer.WriteMessages(context.Background(), kafka.Message{Topic: topic, Key: key, Value: value})
+}
+
+// Close implements kafka.Producer of the sink package. It flushes pending messages and closes the writer.
+func (p *Producer) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,245 @@
+// Package loki provides a sink pushing entries to Grafana Loki's HTTP push API in batches:
+//
+//	sink, err := loki.New(loki.Config{
+//		URL:    "http://loki:3100/loki/api/v1/push",
+//		Labels: map[string]string{"app": "billing", "env": "prod"},
+//	})
+//	...
+//	myLogger.AddSink("loki", sink)
+package loki
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config configures a Loki sink.
+type Config struct {
+	URL           string              //push endpoint, e.g. http://loki:3100/loki/api/v1/push
+	Labels        map[string]string   //labels of every stream, e.g. app and env
+	LevelLabel    bool                //add a "level" label, so that each level is its own stream
+	BatchSize     int                 //entries per push, 1000 by default
+	FlushInterval time.Duration       //longest time an entry waits for a push, 1 second by default
+	Formatter     logWriter.Formatter //renders the log lines, "message key=value..." by default
+	TenantID      string              //sent as X-Scope-OrgID for multi-tenant Loki, if set
+	Client        *http.Client        //HTTP client, one with a 10 second timeout by default
+}
+
+// Sink batches entries and pushes them to Loki when a batch is full, when the flush interval passed, and on
+// Flush and Close. A push that fails is retried once with the next push; entries of a batch that fails twice
+// are dropped.
+type Sink struct {
+	lock    sync.Mutex    //guards the batch
+	config  Config        //configuration with defaults applied
+	batch   []line        //entries waiting for a push
+	retry   []line        //entries of the last failed push
+	dropped uint64        //entries dropped after failed pushes
+	stop    chan struct{} //stops the flush timer
+	stopped chan struct{} //closed when the flush timer returned
+	closed  bool          //set by Close
+}
+
+//line is one entry waiting for a push.
+type line struct {
+	stream    string            //key of the entry's label set
+	labels    map[string]string //labels of the entry's stream
+	timestamp time.Time         //time the entry reached the sink
+	text      string            //rendered log line
+}
+
+// New returns a Loki sink for the given configuration.
+func New(config Config) (*Sink, error) {
+	if len(config.URL) == 0 {
+		return nil, fmt.Errorf("loki: no push URL given")
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 1000
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	sink := &Sink{config: config, stop: make(chan struct{}), stopped: make(chan struct{})}
+	go sink.flushPeriodically()
+	return sink, nil
+}
+
+//This method pushes the batch every flush interval until the sink is closed.
+func (s *Sink) flushPeriodically() {
+	defer close(s.stopped)
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Flush()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Dropped returns the number of entries dropped after failed pushes.
+func (s *Sink) Dropped() uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.dropped
+}
+
+// WriteEntry implements logWriter.EntrySink. It pushes the batch when it is full.
+func (s *Sink) WriteEntry(entry logWriter.Entry) error {
+	text, err := s.render(entry)
+	if err != nil {
+		return err
+	}
+	labels := s.config.Labels
+	if s.config.LevelLabel {
+		labels = make(map[string]string, len(s.config.Labels)+1)
+		for name, value := range s.config.Labels {
+			labels[name] = value
+		}
+		labels["level"] = entry.Level().String()
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return fmt.Errorf("loki: sink is closed")
+	}
+	s.batch = append(s.batch, line{stream: streamKey(labels), labels: labels, timestamp: entry.Time(), text: text})
+	if len(s.batch) >= s.config.BatchSize {
+		return s.push()
+	}
+	return nil
+}
+
+//This method renders the log line of an entry.
+func (s *Sink) render(entry logWriter.Entry) (string, error) {
+	if s.config.Formatter != nil {
+		data, err := s.config.Formatter.Format(entry)
+		return strings.TrimRight(string(data), "\n"), err
+	}
+	var b strings.Builder
+	b.WriteString(entry.Message())
+	for _, field := range entry.SortedFields() {
+		b.WriteString(" " + field.Key + "=" + field.Value)
+	}
+	return b.String(), nil
+}
+
+//This method returns a key identifying a label set.
+func streamKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name + "=" + strconv.Quote(labels[name]) + ",")
+	}
+	return b.String()
+}
+
+// Flush implements logWriter.Flusher: it pushes the batch now.
+func (s *Sink) Flush() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.push()
+}
+
+//This method pushes the entries of the last failed push and the batch. It must be called with lock held.
+func (s *Sink) push() error {
+	retried := len(s.retry)
+	lines := append(s.retry, s.batch...)
+	s.batch, s.retry = nil, nil
+	if len(lines) == 0 {
+		return nil
+	}
+	err := s.send(lines)
+	if err != nil {
+		s.dropped += uint64(retried)
+		s.retry = lines[retried:]
+	}
+	return err
+}
+
+//pushRequest is the body of a push, see https://grafana.com/docs/loki/latest/api/#push-log-entries-to-loki.
+type pushRequest struct {
+	Streams []pushStream `json:"streams"`
+}
+
+type pushStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+//This method sends the lines to Loki, grouped into streams by their labels.
+func (s *Sink) send(lines []line) error {
+	var request pushRequest
+	streams := make(map[string]int)
+	for _, l := range lines {
+		index, ok := streams[l.stream]
+		if !ok {
+			index = len(request.Streams)
+			streams[l.stream] = index
+			request.Streams = append(request.Streams, pushStream{Stream: l.labels})
+		}
+		request.Streams[index].Values = append(request.Streams[index].Values,
+			[2]string{strconv.FormatInt(l.timestamp.UnixNano(), 10), l.text})
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	httpRequest, err := http.NewRequest(http.MethodPost, s.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	if len(s.config.TenantID) > 0 {
+		httpRequest.Header.Set("X-Scope-OrgID", s.config.TenantID)
+	}
+	response, err := s.config.Client.Do(httpRequest)
+	if err != nil {
+		return fmt.Errorf("loki: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode/100 != 2 {
+		message, _ := ioutil.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("loki: push failed with %s: %s", response.Status, bytes.TrimSpace(message))
+	}
+	return nil
+}
+
+// Close implements logWriter.EntrySink. It stops the flush timer and pushes the remaining entries; entries that
+// cannot be pushed are dropped.
+func (s *Sink) Close() error {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.lock.Unlock()
+	close(s.stop)
+	<-s.stopped
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	err := s.push()
+	s.dropped += uint64(len(s.retry))
+	s.retry = nil
+	return err
+}
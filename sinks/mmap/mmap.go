@@ -0,0 +1,249 @@
+// Package mmap provides a sink appending entries to a memory-mapped file, for workloads where the latency of
+// write system calls dominates. Entries are copied into a region of the file mapped into memory, so that
+// writing one costs a copy; the region is synced to disk with msync periodically, on Flush and on Close:
+//
+//	sink, err := mmap.Open("logs/app.mmap.log", logWriter.JSONFormatter{})
+//	myLogger.AddSink("mmap", sink)
+//
+// The file is extended and its blocks reserved a region at a time, 4 MiB by default, and the next region is
+// mapped when one fills up, so entries of any size are written whole. Close truncates the file to the bytes
+// written. After a crash the file may end in zero bytes of the last region; Open appends after the last byte
+// that is not zero. The file must not be truncated by another process while it is mapped. The sink is
+// supported on Linux and macOS; Open fails elsewhere.
+package mmap
+
+import (
+	"errors"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"os"
+	"sync"
+	"time"
+)
+
+//defaults of a new sink.
+const (
+	defaultRegionSize   = 4 << 20
+	defaultSyncInterval = time.Second
+)
+
+// ErrClosed is returned by WriteEntry after Close.
+var ErrClosed = errors.New("mmap sink is closed")
+
+// Sink appends formatted entries to a memory-mapped file.
+type Sink struct {
+	lock       sync.Mutex          //guards the fields below
+	file       *os.File            //file the regions are mapped from
+	formatter  logWriter.Formatter //renders the entries
+	region     []byte              //mapped region of the file, nil until the first entry
+	base       int64               //offset of region in the file, a multiple of the page size
+	end        int64               //bytes of the file written so far
+	regionSize int64               //bytes mapped at a time
+	dirty      bool                //region holds entries not synced yet
+	closed     bool                //set by Close
+	ticker     *time.Ticker        //periodic sync
+	closing    chan struct{}       //closed by Close
+	done       chan struct{}       //closed when the syncer returned
+}
+
+// Open opens or creates the file at path, with mode 0644, and returns a sink appending entries to it, rendered by
+// formatter, or by logWriter.TextFormatter if it is nil. No region is mapped until the first entry.
+func Open(path string, formatter logWriter.Formatter) (*Sink, error) {
+	if !supported {
+		return nil, errors.New("mmap sink is not supported on this platform")
+	}
+	if formatter == nil {
+		formatter = logWriter.TextFormatter{}
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	end, err := logicalEnd(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	sink := &Sink{
+		file:       file,
+		formatter:  formatter,
+		end:        end,
+		regionSize: defaultRegionSize,
+		ticker:     time.NewTicker(defaultSyncInterval),
+		closing:    make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go sink.syncPeriodically()
+	return sink, nil
+}
+
+// SetRegionSize sets how many bytes are mapped and reserved at a time, 4 MiB by default, rounded up to a
+// multiple of the page size. It applies from the next region on.
+func (s *Sink) SetRegionSize(size int64) {
+	page := int64(os.Getpagesize())
+	if size < page {
+		size = page
+	}
+	s.lock.Lock()
+	s.regionSize = (size + page - 1) / page * page
+	s.lock.Unlock()
+}
+
+// SetSyncInterval sets how often written entries are synced to disk, 1 second by default. Non-positive
+// intervals are ignored.
+func (s *Sink) SetSyncInterval(interval time.Duration) {
+	if interval > 0 {
+		s.ticker.Reset(interval)
+	}
+}
+
+// Size returns the bytes of the file written so far.
+func (s *Sink) Size() int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.end
+}
+
+// WriteEntry implements logWriter.EntrySink. It copies the formatted entry into the mapped region, mapping the
+// next region first if the entry does not fit.
+func (s *Sink) WriteEntry(entry logWriter.Entry) error {
+	data, err := s.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	for len(data) > 0 {
+		if s.region == nil || s.end == s.base+int64(len(s.region)) {
+			if err = s.remap(); err != nil {
+				return err
+			}
+		}
+		n := copy(s.region[s.end-s.base:], data)
+		data = data[n:]
+		s.end += int64(n)
+		s.dirty = true
+	}
+	return nil
+}
+
+// Flush implements logWriter.Flusher. It syncs the entries written so far to disk.
+func (s *Sink) Flush() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.sync()
+}
+
+// Close syncs and unmaps the region, truncates the file to the bytes written and closes it.
+func (s *Sink) Close() error {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.closing)
+	s.lock.Unlock()
+	<-s.done
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	err := s.unmap()
+	if truncateErr := s.file.Truncate(s.end); err == nil {
+		err = truncateErr
+	}
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+//This method unmaps the current region, syncing it first, and maps the region of the file starting at the page
+// holding the end of the written bytes, extending the file and reserving its blocks so that a full disk fails
+// here rather than with a fault when the region is written. It must be called with lock held.
+func (s *Sink) remap() error {
+	if err := s.unmap(); err != nil {
+		return err
+	}
+	page := int64(os.Getpagesize())
+	base := s.end / page * page
+	if err := reserve(s.file, base+s.regionSize); err != nil {
+		return err
+	}
+	region, err := mapRegion(s.file, base, int(s.regionSize))
+	if err != nil {
+		return err
+	}
+	s.region, s.base = region, base
+	return nil
+}
+
+//This method syncs and unmaps the current region, if any. It must be called with lock held.
+func (s *Sink) unmap() error {
+	if s.region == nil {
+		return nil
+	}
+	err := s.sync()
+	if unmapErr := unmapRegion(s.region); err == nil {
+		err = unmapErr
+	}
+	s.region = nil
+	return err
+}
+
+//This method syncs the written part of the current region to disk if it holds entries not synced yet. It must
+// be called with lock held.
+func (s *Sink) sync() error {
+	if !s.dirty || s.region == nil {
+		return nil
+	}
+	err := syncRegion(s.region[:s.end-s.base])
+	if err == nil {
+		s.dirty = false
+	}
+	return err
+}
+
+//This method syncs the region on every tick of the ticker until the sink is closed.
+func (s *Sink) syncPeriodically() {
+	defer close(s.done)
+	defer s.ticker.Stop()
+	for {
+		select {
+		case <-s.ticker.C:
+			s.lock.Lock()
+			s.sync()
+			s.lock.Unlock()
+		case <-s.closing:
+			return
+		}
+	}
+}
+
+//Util method that returns the offset after the last byte of the file that is not zero, where appending resumes.
+func logicalEnd(file *os.File) (int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+	chunk := make([]byte, 64*1024)
+	for size > 0 {
+		n := int64(len(chunk))
+		if n > size {
+			n = size
+		}
+		if _, err = file.ReadAt(chunk[:n], size-n); err != nil {
+			return 0, err
+		}
+		for i := n - 1; i >= 0; i-- {
+			if chunk[i] != 0 {
+				return size - n + i + 1, nil
+			}
+		}
+		size -= n
+	}
+	return 0, nil
+}
@@ -0,0 +1,30 @@
+//go:build !linux && !darwin
+
+package mmap
+
+import (
+	"errors"
+	"os"
+)
+
+//whether memory-mapped sinks are supported on this platform.
+const supported = false
+
+//errUnsupported is returned by the functions below, which Open never calls on this platform.
+var errUnsupported = errors.New("mmap is not supported on this platform")
+
+func mapRegion(file *os.File, offset int64, size int) ([]byte, error) {
+	return nil, errUnsupported
+}
+
+func unmapRegion(region []byte) error {
+	return errUnsupported
+}
+
+func syncRegion(region []byte) error {
+	return errUnsupported
+}
+
+func reserve(file *os.File, size int64) error {
+	return errUnsupported
+}
@@ -0,0 +1,40 @@
+//go:build linux || darwin
+
+package mmap
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+//whether memory-mapped sinks are supported on this platform.
+const supported = true
+
+//Util method that maps size bytes of the file from offset on for reading and writing, shared with the file.
+func mapRegion(file *os.File, offset int64, size int) ([]byte, error) {
+	region, err := syscall.Mmap(int(file.Fd()), offset, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, &os.PathError{Op: "mmap", Path: file.Name(), Err: err}
+	}
+	return region, nil
+}
+
+//Util method that unmaps a region returned by mapRegion.
+func unmapRegion(region []byte) error {
+	return syscall.Munmap(region)
+}
+
+//Util method that writes the changes to a mapped region, which must start at a page boundary, to disk and
+// waits for them.
+func syncRegion(region []byte) error {
+	if len(region) == 0 {
+		return nil
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_MSYNC, uintptr(unsafe.Pointer(&region[0])), uintptr(len(region)),
+		syscall.MS_SYNC)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
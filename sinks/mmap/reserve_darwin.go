@@ -0,0 +1,15 @@
+//go:build darwin
+
+package mmap
+
+import "os"
+
+//Util method that extends the file to size bytes if it is smaller. Blocks are allocated when the mapped pages are
+// written.
+func reserve(file *os.File, size int64) error {
+	info, err := file.Stat()
+	if err != nil || info.Size() >= size {
+		return err
+	}
+	return file.Truncate(size)
+}
@@ -0,0 +1,17 @@
+//go:build linux
+
+package mmap
+
+import (
+	"os"
+	"syscall"
+)
+
+//Util method that extends the file to size bytes, if it is smaller, and allocates its blocks, so that writing
+// the mapped file cannot run out of disk space.
+func reserve(file *os.File, size int64) error {
+	if err := syscall.Fallocate(int(file.Fd()), 0, 0, size); err != nil {
+		return &os.PathError{Op: "fallocate", Path: file.Name(), Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+// Package network provides a sink streaming entries to a remote collector over TCP or UDP, e.g. a Logstash
+// tcp input or a Fluent Bit tcp input. Entries are rendered by a formatter, one per line:
+//
+//	sink := network.New("tcp", "collector:5170", logWriter.JSONFormatter{})
+//	myLogger.AddSink("collector", sink)
+//
+// The connection is made lazily and re-made after failures, with exponential backoff. While the collector is
+// unreachable entries are kept in a bounded queue and sent once it is back; when the queue is full the oldest
+// entries are dropped.
+package network
+
+import (
+	"errors"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"net"
+	"sync"
+	"time"
+)
+
+//defaults of a new sink.
+const (
+	defaultQueueSize    = 10000
+	defaultMinBackoff   = 100 * time.Millisecond
+	defaultMaxBackoff   = 30 * time.Second
+	defaultWriteTimeout = 5 * time.Second
+)
+
+// ErrClosed is returned by WriteEntry after Close.
+var ErrClosed = errors.New("network sink is closed")
+
+// Sink sends formatted entries over a network connection from a goroutine of its own, so that WriteEntry never
+// waits for the network.
+type Sink struct {
+	lock      sync.Mutex          //guards the fields below
+	network   string              //"tcp" or "udp"
+	address   string              //address of the collector
+	formatter logWriter.Formatter //renders the entries
+	queue     [][]byte            //formatted entries waiting to be sent, oldest first
+	queueSize int                 //maximum length of queue
+	dropped   uint64              //entries dropped because the queue was full
+	wake      chan struct{}       //signals the sender that the queue is not empty
+	closed    bool                //set by Close
+	closing   chan struct{}       //closed by Close
+	done      chan struct{}       //closed when the sender returned
+
+	minBackoff   time.Duration //first wait after a failure
+	maxBackoff   time.Duration //longest wait between attempts
+	writeTimeout time.Duration //deadline of a single connect or write
+}
+
+// New returns a sink sending entries to address over network, "tcp" or "udp", rendered by formatter, or by
+// logWriter.TextFormatter if it is nil. No connection is made until the first entry.
+func New(network string, address string, formatter logWriter.Formatter) *Sink {
+	if formatter == nil {
+		formatter = logWriter.TextFormatter{}
+	}
+	sink := &Sink{
+		network:      network,
+		address:      address,
+		formatter:    formatter,
+		queueSize:    defaultQueueSize,
+		wake:         make(chan struct{}, 1),
+		closing:      make(chan struct{}),
+		done:         make(chan struct{}),
+		minBackoff:   defaultMinBackoff,
+		maxBackoff:   defaultMaxBackoff,
+		writeTimeout: defaultWriteTimeout,
+	}
+	go sink.send()
+	return sink
+}
+
+// SetQueueSize sets how many entries are kept while the collector is unreachable, 10000 by default.
+func (s *Sink) SetQueueSize(size int) {
+	s.lock.Lock()
+	s.queueSize = size
+	s.lock.Unlock()
+}
+
+// SetBackoff sets the wait after the first failed attempt and the longest wait between attempts, 100ms and
+// 30s by default. The wait doubles after every failed attempt.
+func (s *Sink) SetBackoff(min time.Duration, max time.Duration) {
+	s.lock.Lock()
+	s.minBackoff, s.maxBackoff = min, max
+	s.lock.Unlock()
+}
+
+// Dropped returns the number of entries dropped because the queue was full.
+func (s *Sink) Dropped() uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.dropped
+}
+
+// WriteEntry implements logWriter.EntrySink. It queues the entry and returns without waiting for the network;
+// the only errors are formatting errors and ErrClosed.
+func (s *Sink) WriteEntry(entry logWriter.Entry) error {
+	data, err := s.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	if len(s.queue) >= s.queueSize {
+		s.queue = s.queue[1:]
+		s.dropped++
+	}
+	s.queue = append(s.queue, data)
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+//This method runs in the sink's goroutine. It sends queued entries in order, reconnecting with backoff after
+// failures, until the sink is closed and the queue is empty or the collector is unreachable at close.
+func (s *Sink) send() {
+	defer close(s.done)
+	var conn net.Conn
+	backoff := time.Duration(0)
+	for {
+		s.lock.Lock()
+		if len(s.queue) == 0 {
+			closed := s.closed
+			s.lock.Unlock()
+			if closed {
+				break
+			}
+			select {
+			case <-s.wake:
+			case <-s.closing:
+			}
+			continue
+		}
+		data := s.queue[0]
+		closed := s.closed
+		minBackoff, maxBackoff, timeout := s.minBackoff, s.maxBackoff, s.writeTimeout
+		s.lock.Unlock()
+
+		err := error(nil)
+		if conn == nil {
+			conn, err = net.DialTimeout(s.network, s.address, timeout)
+		}
+		if err == nil {
+			conn.SetWriteDeadline(time.Now().Add(timeout))
+			_, err = conn.Write(data)
+		}
+		if err == nil {
+			backoff = 0
+			s.lock.Lock()
+			s.queue = s.queue[1:]
+			s.lock.Unlock()
+			continue
+		}
+		if conn != nil {
+			conn.Close()
+			conn = nil
+		}
+		if closed {
+			break
+		}
+		if backoff == 0 {
+			backoff = minBackoff
+		} else if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+		select {
+		case <-time.After(backoff):
+		case <-s.closing:
+		}
+	}
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// Close implements logWriter.EntrySink. It sends the queued entries, making one more attempt if the collector
+// is unreachable, and closes the connection. Entries that could not be sent are counted as dropped and reported
+// in the returned error.
+func (s *Sink) Close() error {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.lock.Unlock()
+	close(s.closing)
+	<-s.done
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if unsent := len(s.queue); unsent > 0 {
+		s.dropped += uint64(unsent)
+		s.queue = nil
+		return errors.New("network sink closed with unsent entries, collector unreachable")
+	}
+	return nil
+}
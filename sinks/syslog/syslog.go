@@ -0,0 +1,235 @@
+// Package syslog provides a sink forwarding entries to a syslog daemon as RFC 5424 messages. It is pure Go, so
+// unlike log/syslog it also works on Windows when sending to a remote daemon:
+//
+//	sink, err := syslog.New("udp", "logs.example.com:514")
+//	...
+//	myLogger.AddSink("syslog", sink)
+//
+// Entry fields are sent as the structured data element "fields@32473".
+package syslog
+
+import (
+	"fmt"
+	"github.com/shyamgrover/go-lite-logger/logWriter"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Facility is a syslog facility.
+type Facility int
+
+// Syslog facilities, see RFC 5424 section 6.2.1.
+const (
+	Kern Facility = iota
+	User
+	Mail
+	Daemon
+	Auth
+	Syslog
+	LPR
+	News
+	UUCP
+	Cron
+	AuthPriv
+	FTP
+	Local0 Facility = iota + 4
+	Local1
+	Local2
+	Local3
+	Local4
+	Local5
+	Local6
+	Local7
+)
+
+// Severity returns the syslog severity for a level: crit for Panic and Fatal, err, warning, info, or debug for
+// Debug and Trace. Custom levels get the severity of the built-in level they are filtered like.
+func Severity(level logWriter.Level) int {
+	switch level.Base() {
+	case logWriter.PanicLevel, logWriter.FatalLevel:
+		return 2
+	case logWriter.ErrorLevel:
+		return 3
+	case logWriter.WarnLevel:
+		return 4
+	case logWriter.InfoLevel:
+		return 6
+	}
+	return 7
+}
+
+//local syslog sockets, tried in order when no address is given.
+var localSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+//structured data ID of the entry fields, using the example enterprise number of RFC 5424.
+const fieldsID = "fields@32473"
+
+// Sink sends every entry as one RFC 5424 message. Over TCP messages are framed by octet counting (RFC 6587),
+// over UDP and unix sockets every message is one datagram. A failed write is retried once on a new connection.
+type Sink struct {
+	lock     sync.Mutex //serializes writes and reconnects
+	network  string     //network to dial, empty for the local daemon
+	address  string     //address to dial
+	conn     net.Conn   //current connection, nil after a failed reconnect
+	facility Facility   //facility of all messages
+	hostname string     //HOSTNAME of the messages
+	appName  string     //APP-NAME of the messages
+	procID   string     //PROCID of the messages
+}
+
+// New connects to the syslog daemon at address over network ("udp", "tcp" or "unix"). With an empty network
+// and address it connects to the local daemon's socket. Messages use the User facility and the program name as
+// APP-NAME until set otherwise.
+func New(network string, address string) (*Sink, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+	sink := &Sink{
+		network:  network,
+		address:  address,
+		facility: User,
+		hostname: hostname,
+		appName:  filepath.Base(os.Args[0]),
+		procID:   fmt.Sprint(os.Getpid()),
+	}
+	if err = sink.connect(); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+// SetFacility sets the facility of the messages.
+func (s *Sink) SetFacility(facility Facility) {
+	s.lock.Lock()
+	s.facility = facility
+	s.lock.Unlock()
+}
+
+// SetAppName sets the APP-NAME of the messages.
+func (s *Sink) SetAppName(appName string) {
+	s.lock.Lock()
+	s.appName = appName
+	s.lock.Unlock()
+}
+
+//This method dials the daemon. It must be called with lock held or before the sink is shared.
+func (s *Sink) connect() error {
+	if len(s.network) > 0 {
+		conn, err := net.Dial(s.network, s.address)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+		return nil
+	}
+	var lastErr error
+	for _, socket := range localSockets {
+		for _, network := range []string{"unixgram", "unix"} {
+			conn, err := net.Dial(network, socket)
+			if err == nil {
+				s.conn = conn
+				return nil
+			}
+			lastErr = err
+		}
+	}
+	return fmt.Errorf("no local syslog daemon found: %v", lastErr)
+}
+
+// WriteEntry implements logWriter.EntrySink.
+func (s *Sink) WriteEntry(entry logWriter.Entry) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	message := s.format(entry, entry.Time())
+	if s.conn != nil {
+		if err := s.write(message); err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	if err := s.connect(); err != nil {
+		return err
+	}
+	return s.write(message)
+}
+
+//This method writes one message, framed for stream connections. It must be called with lock held.
+func (s *Sink) write(message string) error {
+	if _, ok := s.conn.(*net.TCPConn); ok {
+		message = fmt.Sprintf("%d %s", len(message), message)
+	}
+	_, err := s.conn.Write([]byte(message))
+	return err
+}
+
+//This method renders the entry as an RFC 5424 message.
+func (s *Sink) format(entry logWriter.Entry, now time.Time) string {
+	priority := int(s.facility)*8 + Severity(entry.Level())
+	return fmt.Sprintf("<%d>1 %s %s %s %s - %s %s", priority, now.Format("2006-01-02T15:04:05.000000Z07:00"),
+		header(s.hostname, 255), header(s.appName, 48), header(s.procID, 128), structuredData(entry),
+		entry.Message())
+}
+
+//This method returns a header field: printable ASCII without spaces, at most max long, "-" if empty.
+func header(value string, max int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, value)
+	if len(value) > max {
+		value = value[:max]
+	}
+	if len(value) == 0 {
+		return "-"
+	}
+	return value
+}
+
+//This method returns the entry's fields as a structured data element, or "-" if it has none.
+func structuredData(entry logWriter.Entry) string {
+	fields := entry.Fields()
+	if len(fields) == 0 {
+		return "-"
+	}
+	var b strings.Builder
+	b.WriteString("[" + fieldsID)
+	for _, field := range entry.SortedFields() {
+		name := strings.Map(func(r rune) rune {
+			if r < 33 || r > 126 || r == '=' || r == ']' || r == '"' {
+				return '_'
+			}
+			return r
+		}, field.Key)
+		if len(name) > 32 {
+			name = name[:32]
+		}
+		value := fmt.Sprint(fields[field.Key])
+		if err, ok := fields[field.Key].(error); ok {
+			value = err.Error()
+		}
+		value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+		b.WriteString(" " + name + `="` + value + `"`)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// Close implements logWriter.EntrySink.
+func (s *Sink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
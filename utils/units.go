@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration parses a human friendly duration. It accepts everything time.ParseDuration does ("500ms",
+// "2h45m"), a whole number of days ("7d") and a plain number, which is taken as seconds ("30", "1.5").
+// Negative durations, NaN, infinities and durations longer than a time.Duration holds, about 290 years, are
+// rejected.
+func ParseDuration(s string) (time.Duration, error) {
+	value := strings.TrimSpace(s)
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return durationOf(s, seconds, time.Second)
+	}
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil {
+			return durationOf(s, float64(days), 24*time.Hour)
+		}
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return duration, nil
+}
+
+//Util method that returns number units as a duration, and fails for NaN, infinities, negative numbers and
+// durations that overflow time.Duration. s is the text the number was parsed from, for the error.
+func durationOf(s string, number float64, unit time.Duration) (time.Duration, error) {
+	if math.IsNaN(number) || math.IsInf(number, 0) || number < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	duration := number * float64(unit)
+	if duration >= math.MaxInt64 {
+		return 0, fmt.Errorf("duration %q is too large", s)
+	}
+	return time.Duration(duration), nil
+}
+
+//multipliers of the size units accepted by ParseSize. Decimal units are powers of 1000 and binary units are
+// powers of 1024, as in "100MB" and "1GiB".
+var sizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// ParseSize parses a human friendly byte size such as "512", "100MB" or "1.5GiB". Units are case insensitive;
+// KB, MB, GB and TB are decimal and KiB, MiB, GiB and TiB are binary.
+func ParseSize(s string) (int64, error) {
+	value := strings.TrimSpace(s)
+	split := len(value)
+	for split > 0 && (value[split-1] < '0' || value[split-1] > '9') && value[split-1] != '.' {
+		split--
+	}
+	number, err := strconv.ParseFloat(value[:split], 64)
+	multiplier, known := sizeUnits[strings.ToLower(strings.TrimSpace(value[split:]))]
+	if err != nil || !known || number < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	size := number * multiplier
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("size %q is too large", s)
+	}
+	return int64(size), nil
+}
+
+// Duration is a time.Duration that can be configured as a human friendly string (see ParseDuration) in JSON
+// and text based config formats.
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a string or a number of seconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONText(data, d)
+}
+
+// Size is a number of bytes that can be configured as a human friendly string (see ParseSize) in JSON and text
+// based config formats.
+type Size int64
+
+func (s Size) String() string {
+	return strconv.FormatInt(int64(s), 10)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Size) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Size) UnmarshalText(text []byte) error {
+	size, err := ParseSize(string(text))
+	if err != nil {
+		return err
+	}
+	*s = Size(size)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a string or a number of bytes.
+func (s *Size) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONText(data, s)
+}
+
+//This method decodes a JSON string or number and hands its text to the target's UnmarshalText.
+func unmarshalJSONText(data []byte, target interface{ UnmarshalText([]byte) error }) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		var number json.Number
+		if err = json.Unmarshal(data, &number); err != nil {
+			return err
+		}
+		text = number.String()
+	}
+	return target.UnmarshalText([]byte(text))
+}